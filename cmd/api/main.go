@@ -8,7 +8,11 @@ import (
 
 
 
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata" // Zona waktu toko (jadwal promo) tetap bisa dimuat di image tanpa tzdata
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
@@ -39,9 +43,23 @@ func main() {
 	app.Use(recover.New())
 
 	// 5. Panggil Setup Routes 
-	routes.SetupRoutes(app)
+	jobs := routes.SetupRoutes(app)
 
-	// 6. Jalankan Server
+	// 6. Background job & server berhenti bersama saat SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for _, job := range jobs {
+		go job.Start(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		logger.Log.Info("Menghentikan server...")
+		if err := app.Shutdown(); err != nil {
+			logger.Log.Errorf("Gagal menghentikan server: %v", err)
+		}
+	}()
+
+	// 7. Jalankan Server
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -36,11 +36,25 @@ func ConnectDatabase() {
 		&core.User{},
 		&core.Category{},
 		&core.Product{},
+		&core.ModifierGroup{},
+		&core.ModifierOption{},
+		&core.BundleSlot{},
+		&core.BundleSlotOption{},
+		&core.PromoSchedule{},
 		&core.Voucher{},
+		&core.Promotion{},
+		&core.PromotionTier{},
+		&core.ChargeRule{},
 		&core.DailyCounter{},
 		&core.Order{},
 		&core.OrderItem{},
+		&core.OrderItemModifier{},
+		&core.OrderItemComponent{},
+		&core.OrderCharge{},
+		&core.OrderStatusHistory{},
 		&core.Payment{},
+		&core.Refund{},
+		&core.RefundItem{},
 	)
 
 	if err != nil {
@@ -0,0 +1,47 @@
+package core
+
+// AllocateProportionally membagi total ke beberapa bagian sebanding dengan bobotnya
+// (metode largest remainder). Jumlah hasil selalu sama persis dengan total, sehingga
+// diskon atau fee yang dipecah ke beberapa bill tidak pernah kurang/lebih satu rupiah pun.
+// Jika semua bobot nol, total dibagi rata.
+func AllocateProportionally(total int, weights []int) []int {
+	shares := make([]int, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	sumWeights := 0
+	for _, w := range weights {
+		sumWeights += w
+	}
+	if sumWeights <= 0 {
+		weights = make([]int, len(shares))
+		for i := range weights {
+			weights[i] = 1
+		}
+		sumWeights = len(weights)
+	}
+
+	allocated := 0
+	remainders := make([]int, len(weights))
+	for i, w := range weights {
+		shares[i] = total * w / sumWeights
+		remainders[i] = total * w % sumWeights
+		allocated += shares[i]
+	}
+
+	// Sisa pembulatan diberikan satu per satu ke bagian dengan pecahan terbesar (indeks terkecil jika seri)
+	for allocated < total {
+		best := 0
+		for i := range remainders {
+			if remainders[i] > remainders[best] {
+				best = i
+			}
+		}
+		shares[best]++
+		remainders[best] = -1
+		allocated++
+	}
+
+	return shares
+}
@@ -0,0 +1,40 @@
+package core_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateProportionally(t *testing.T) {
+	testCases := []struct {
+		name     string
+		total    int
+		weights  []int
+		expected []int
+	}{
+		{name: "Sukses - Habis dibagi", total: 10000, weights: []int{50000, 50000}, expected: []int{5000, 5000}},
+		{name: "Sukses - Sebanding bobot", total: 3000, weights: []int{20000, 10000}, expected: []int{2000, 1000}},
+		{name: "Sukses - Sisa pembulatan ke pecahan terbesar", total: 100, weights: []int{1, 1, 1}, expected: []int{34, 33, 33}},
+		{name: "Sukses - Bobot nol dibagi rata", total: 5, weights: []int{0, 0}, expected: []int{3, 2}},
+		{name: "Sukses - Total nol", total: 0, weights: []int{10, 20}, expected: []int{0, 0}},
+		{name: "Sukses - Tanpa bagian", total: 100, weights: []int{}, expected: []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shares := core.AllocateProportionally(tc.total, tc.weights)
+			assert.Equal(t, tc.expected, shares)
+
+			sum := 0
+			for _, share := range shares {
+				sum += share
+			}
+			if len(tc.weights) > 0 {
+				assert.Equal(t, tc.total, sum)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// BundleSelection adalah pilihan pelanggan untuk satu slot bundle.
+type BundleSelection struct {
+	SlotID    uuid.UUID
+	ProductID uuid.UUID
+}
+
+// SelectBundleComponents menentukan isi bundle dari slot-slotnya dan pilihan pelanggan,
+// lalu mengembalikan snapshot komponen (urut sesuai SortOrder slot). Aturannya:
+//   - slot dengan satu opsi terisi otomatis (pilihan boleh dikirim asal sama);
+//   - slot dengan beberapa opsi wajib dipilih tepat satu kali;
+//   - produk komponen harus tersedia (Options.Product sudah di-preload).
+//
+// Stok komponen TIDAK dicek di sini — stok dicek setelah baris produk dikunci di dalam transaksi.
+func SelectBundleComponents(slots []BundleSlot, selections []BundleSelection) ([]OrderItemComponent, error) {
+	chosen := make(map[uuid.UUID]uuid.UUID, len(selections))
+	for _, sel := range selections {
+		if _, dup := chosen[sel.SlotID]; dup {
+			return nil, fmt.Errorf("%w: slot %s dipilih lebih dari sekali", ErrInvalidBundle, sel.SlotID)
+		}
+		chosen[sel.SlotID] = sel.ProductID
+	}
+
+	ordered := make([]*BundleSlot, len(slots))
+	for i := range slots {
+		ordered[i] = &slots[i]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].SortOrder < ordered[j].SortOrder
+	})
+
+	components := make([]OrderItemComponent, 0, len(slots))
+	for _, slot := range ordered {
+		productID, picked := chosen[slot.ID]
+		delete(chosen, slot.ID)
+
+		var option *BundleSlotOption
+		switch {
+		case picked:
+			for i := range slot.Options {
+				if slot.Options[i].ProductID == productID {
+					option = &slot.Options[i]
+					break
+				}
+			}
+			if option == nil {
+				return nil, fmt.Errorf("%w: produk %s bukan pilihan untuk %s", ErrInvalidBundle, productID, slot.Name)
+			}
+		case len(slot.Options) == 1:
+			option = &slot.Options[0]
+		default:
+			return nil, fmt.Errorf("%w: %s wajib dipilih", ErrInvalidBundle, slot.Name)
+		}
+
+		name := ""
+		if option.Product != nil {
+			if !option.Product.IsAvailable {
+				return nil, fmt.Errorf("%w: %s sedang tidak tersedia", ErrInvalidBundle, option.Product.Name)
+			}
+			name = option.Product.Name
+		}
+		components = append(components, OrderItemComponent{
+			ID:          uuid.New(),
+			ProductID:   option.ProductID,
+			SlotName:    slot.Name,
+			ProductName: name,
+			Qty:         slot.Qty,
+		})
+	}
+
+	for slotID := range chosen {
+		return nil, fmt.Errorf("%w: slot %s bukan milik bundle ini", ErrInvalidBundle, slotID)
+	}
+	return components, nil
+}
+
+// AddStockQty menambahkan qty unit item ke kebutuhan stok per produk. Produk biasa memakai
+// stoknya sendiri; bundle memakai stok setiap komponennya (qty × Qty komponen).
+// qty negatif berarti stok dikembalikan.
+func (i *OrderItem) AddStockQty(into map[uuid.UUID]int, qty int) {
+	if len(i.Components) == 0 {
+		into[i.ProductID] += qty
+		return
+	}
+	for _, c := range i.Components {
+		into[c.ProductID] += qty * c.Qty
+	}
+}
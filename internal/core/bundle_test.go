@@ -0,0 +1,98 @@
+package core_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBundleComponents(t *testing.T) {
+	americano := &core.Product{ID: uuid.New(), Name: "Americano", IsAvailable: true}
+	croissant := &core.Product{ID: uuid.New(), Name: "Croissant", IsAvailable: true}
+	donut := &core.Product{ID: uuid.New(), Name: "Donut", IsAvailable: true}
+	muffin := &core.Product{ID: uuid.New(), Name: "Muffin", IsAvailable: false}
+
+	coffeeSlot := core.BundleSlot{ID: uuid.New(), Name: "Kopi", Qty: 1, SortOrder: 1, Options: []core.BundleSlotOption{
+		{ProductID: americano.ID, Product: americano},
+	}}
+	pastrySlot := core.BundleSlot{ID: uuid.New(), Name: "Pastry", Qty: 2, SortOrder: 2, Options: []core.BundleSlotOption{
+		{ProductID: croissant.ID, Product: croissant},
+		{ProductID: donut.ID, Product: donut},
+		{ProductID: muffin.ID, Product: muffin},
+	}}
+	slots := []core.BundleSlot{pastrySlot, coffeeSlot}
+
+	testCases := []struct {
+		name          string
+		selections    []core.BundleSelection
+		expectedNames []string
+		expectedError bool
+	}{
+		{name: "Sukses - Slot tetap terisi otomatis", selections: []core.BundleSelection{{SlotID: pastrySlot.ID, ProductID: donut.ID}}, expectedNames: []string{"Americano", "Donut"}},
+		{name: "Sukses - Slot tetap boleh dikirim", selections: []core.BundleSelection{{SlotID: coffeeSlot.ID, ProductID: americano.ID}, {SlotID: pastrySlot.ID, ProductID: croissant.ID}}, expectedNames: []string{"Americano", "Croissant"}},
+		{name: "Gagal - Slot pilihan tidak dipilih", selections: nil, expectedError: true},
+		{name: "Gagal - Produk bukan pilihan slot", selections: []core.BundleSelection{{SlotID: pastrySlot.ID, ProductID: americano.ID}}, expectedError: true},
+		{name: "Gagal - Komponen tidak tersedia", selections: []core.BundleSelection{{SlotID: pastrySlot.ID, ProductID: muffin.ID}}, expectedError: true},
+		{name: "Gagal - Slot milik bundle lain", selections: []core.BundleSelection{{SlotID: pastrySlot.ID, ProductID: donut.ID}, {SlotID: uuid.New(), ProductID: donut.ID}}, expectedError: true},
+		{name: "Gagal - Slot dipilih dua kali", selections: []core.BundleSelection{{SlotID: pastrySlot.ID, ProductID: donut.ID}, {SlotID: pastrySlot.ID, ProductID: croissant.ID}}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			components, err := core.SelectBundleComponents(slots, tc.selections)
+			if tc.expectedError {
+				assert.ErrorIs(t, err, core.ErrInvalidBundle)
+				return
+			}
+
+			assert.NoError(t, err)
+			var names []string
+			for _, c := range components {
+				names = append(names, c.ProductName)
+			}
+			assert.Equal(t, tc.expectedNames, names)
+			assert.Equal(t, 2, components[1].Qty)
+		})
+	}
+}
+
+func TestOrderItemAddStockQty(t *testing.T) {
+	bundleID, coffeeID, pastryID := uuid.New(), uuid.New(), uuid.New()
+
+	testCases := []struct {
+		name     string
+		item     core.OrderItem
+		qty      int
+		expected map[uuid.UUID]int
+	}{
+		{name: "Sukses - Produk biasa memakai stoknya sendiri", item: core.OrderItem{ProductID: coffeeID}, qty: 3, expected: map[uuid.UUID]int{coffeeID: 3}},
+		{
+			name: "Sukses - Bundle memakai stok komponen",
+			item: core.OrderItem{ProductID: bundleID, Components: []core.OrderItemComponent{
+				{ProductID: coffeeID, Qty: 1},
+				{ProductID: pastryID, Qty: 2},
+			}},
+			qty:      3,
+			expected: map[uuid.UUID]int{coffeeID: 3, pastryID: 6},
+		},
+		{
+			name: "Sukses - Qty negatif mengembalikan stok",
+			item: core.OrderItem{ProductID: bundleID, Components: []core.OrderItemComponent{
+				{ProductID: pastryID, Qty: 2},
+			}},
+			qty:      -1,
+			expected: map[uuid.UUID]int{pastryID: -2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			into := make(map[uuid.UUID]int)
+			tc.item.AddStockQty(into, tc.qty)
+			assert.Equal(t, tc.expected, into)
+		})
+	}
+}
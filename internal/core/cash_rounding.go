@@ -0,0 +1,61 @@
+package core
+
+// Mode pembulatan nominal pembayaran (StoreProfile.CashRoundingMode).
+const (
+	CashRoundingNone    = "NONE"    // Nominal dibayar persis
+	CashRoundingNearest = "NEAREST" // Ke kelipatan terdekat, setengah ke atas (Rp 12.250 → 12.300 dengan kelipatan 100)
+	CashRoundingUp      = "UP"
+	CashRoundingDown    = "DOWN"
+)
+
+// Cakupan metode bayar yang dibulatkan (StoreProfile.CashRoundingScope).
+const (
+	CashRoundingScopeCash = "CASH" // Hanya pembayaran tunai
+	CashRoundingScopeAll  = "ALL"  // Semua metode, termasuk QRIS/Transfer
+)
+
+// RoundsPayment menentukan apakah pembayaran dengan metode ini ikut dibulatkan.
+func (p *StoreProfile) RoundsPayment(method string) bool {
+	if p.CashRoundingIncrement <= 0 || p.CashRoundingMode == "" || p.CashRoundingMode == CashRoundingNone {
+		return false
+	}
+	return p.CashRoundingScope == CashRoundingScopeAll || method == PaymentMethodCash
+}
+
+// RoundingAdjustment mengembalikan selisih pembulatan untuk satu pembayaran: positif berarti
+// pelanggan membayar lebih dari tagihan, negatif berarti kurang. 0 jika metode tidak dibulatkan.
+// Selisih ini dicatat terpisah (Payment.RoundingAdjustment) sehingga AmountPaid tetap sama dengan tagihan.
+func (p *StoreProfile) RoundingAdjustment(amount int, method string) int {
+	if !p.RoundsPayment(method) {
+		return 0
+	}
+	return RoundToIncrement(amount, p.CashRoundingIncrement, p.CashRoundingMode) - amount
+}
+
+// RoundToIncrement membulatkan nominal non-negatif ke kelipatan increment sesuai mode.
+func RoundToIncrement(amount, increment int, mode string) int {
+	if increment <= 0 {
+		return amount
+	}
+	remainder := amount % increment
+	if remainder == 0 {
+		return amount
+	}
+	switch mode {
+	case CashRoundingUp:
+		return amount - remainder + increment
+	case CashRoundingDown:
+		return amount - remainder
+	case CashRoundingNearest:
+		if remainder*2 >= increment {
+			return amount - remainder + increment
+		}
+		return amount - remainder
+	}
+	return amount
+}
+
+// AmountCharged adalah nominal yang benar-benar diterima dari pelanggan (tagihan + selisih pembulatan).
+func (p *Payment) AmountCharged() int {
+	return p.AmountPaid + p.RoundingAdjustment
+}
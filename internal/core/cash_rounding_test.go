@@ -0,0 +1,38 @@
+package core_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreProfileRoundingAdjustment(t *testing.T) {
+	nearest100 := core.StoreProfile{CashRoundingMode: core.CashRoundingNearest, CashRoundingIncrement: 100, CashRoundingScope: core.CashRoundingScopeCash}
+	down500 := core.StoreProfile{CashRoundingMode: core.CashRoundingDown, CashRoundingIncrement: 500, CashRoundingScope: core.CashRoundingScopeAll}
+	up500 := core.StoreProfile{CashRoundingMode: core.CashRoundingUp, CashRoundingIncrement: 500, CashRoundingScope: core.CashRoundingScopeCash}
+
+	testCases := []struct {
+		name     string
+		profile  core.StoreProfile
+		amount   int
+		method   string
+		expected int
+	}{
+		{name: "Sukses - Terdekat ke atas", profile: nearest100, amount: 37450, method: core.PaymentMethodCash, expected: 50},
+		{name: "Sukses - Terdekat ke bawah", profile: nearest100, amount: 37449, method: core.PaymentMethodCash, expected: -49},
+		{name: "Sukses - Sudah kelipatan", profile: nearest100, amount: 37400, method: core.PaymentMethodCash, expected: 0},
+		{name: "Sukses - Selalu ke bawah untuk semua metode", profile: down500, amount: 37450, method: core.PaymentMethodQRIS, expected: -450},
+		{name: "Sukses - Selalu ke atas", profile: up500, amount: 37050, method: core.PaymentMethodCash, expected: 450},
+		{name: "Sukses - Non-tunai tidak dibulatkan", profile: nearest100, amount: 37450, method: core.PaymentMethodQRIS, expected: 0},
+		{name: "Sukses - Tanpa pengaturan", profile: core.StoreProfile{}, amount: 37450, method: core.PaymentMethodCash, expected: 0},
+		{name: "Sukses - Mode NONE", profile: core.StoreProfile{CashRoundingMode: core.CashRoundingNone, CashRoundingIncrement: 100}, amount: 37450, method: core.PaymentMethodCash, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.profile.RoundingAdjustment(tc.amount, tc.method))
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// ChargeLine adalah satu baris item yang menjadi dasar pengenaan service charge & pajak.
+type ChargeLine struct {
+	CategoryID uuid.UUID
+	Subtotal   int
+	Discount   int // Diskon yang sudah pasti milik baris ini (promo otomatis)
+}
+
+// ChargeBreakdown adalah hasil CalculateCharges untuk satu order (atau satu bill hasil split).
+type ChargeBreakdown struct {
+	Charges       []OrderCharge
+	ServiceCharge int
+	Tax           int
+	TaxIncluded   int // Bagian Tax yang sudah termasuk di harga menu
+}
+
+// Added adalah nominal yang ditambahkan ke TotalFinalAmount (service charge + pajak eksklusif).
+func (b ChargeBreakdown) Added() int {
+	return b.ServiceCharge + b.Tax - b.TaxIncluded
+}
+
+// ExclusiveCharges adalah service charge + pajak eksklusif yang sudah ditambahkan ke TotalFinalAmount.
+func (o *Order) ExclusiveCharges() int {
+	return o.TotalServiceCharge + o.TotalTax - o.TotalTaxIncluded
+}
+
+// CalculateCharges menghitung service charge & pajak dari aturan yang aktif. Aturannya:
+//   - discount (diskon tingkat order, mis. voucher) dialokasikan proporsional ke setiap baris setelah
+//     Discount milik baris itu sendiri; aturan AfterDiscount memakai subtotal setelah kedua diskon,
+//     selain itu subtotal sebelum diskon;
+//   - baris dengan kategori yang dikecualikan aturan tidak ikut dasar pengenaan aturan tersebut;
+//   - semua SERVICE dihitung lebih dulu, lalu TAX eksklusif dikenakan atas baris + service charge
+//     baris tersebut (PB1 juga dikenakan atas service charge);
+//   - TAX inklusif hanya menghitung porsi pajak di dalam harga baris (dasar × tarif / (100% + tarif)).
+//
+// Nominal dibulatkan ke rupiah terdekat per aturan. Aturan tanpa dasar pengenaan tidak menghasilkan baris.
+func CalculateCharges(rules []ChargeRule, lines []ChargeLine, discount int) ChargeBreakdown {
+	var result ChargeBreakdown
+	if len(rules) == 0 || len(lines) == 0 {
+		return result
+	}
+
+	subtotals := make([]int, len(lines))
+	for i, line := range lines {
+		subtotals[i] = line.Subtotal - line.Discount
+	}
+	discounts := AllocateProportionally(discount, subtotals)
+	for i, line := range lines {
+		discounts[i] += line.Discount
+	}
+
+	ordered := make([]ChargeRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Kind != ordered[j].Kind {
+			return ordered[i].Kind == ChargeKindService
+		}
+		return ordered[i].SortOrder < ordered[j].SortOrder
+	})
+
+	serviceShares := make([]int, len(lines)) // Service charge yang jatuh ke setiap baris, untuk dasar pajak
+	for _, rule := range ordered {
+		if !rule.IsActive || rule.RateBps <= 0 {
+			continue
+		}
+		exempt := make(map[uuid.UUID]bool, len(rule.ExemptCategories))
+		for _, category := range rule.ExemptCategories {
+			exempt[category.ID] = true
+		}
+
+		bases := make([]int, len(lines))
+		base := 0
+		for i, line := range lines {
+			if exempt[line.CategoryID] {
+				continue
+			}
+			bases[i] = line.Subtotal
+			if rule.AfterDiscount {
+				bases[i] -= discounts[i]
+			}
+			if rule.Kind == ChargeKindTax && !rule.IsInclusive {
+				bases[i] += serviceShares[i]
+			}
+			base += bases[i]
+		}
+		if base <= 0 {
+			continue
+		}
+
+		var amount int
+		if rule.IsInclusive && rule.Kind == ChargeKindTax {
+			amount = roundDiv(base*rule.RateBps, 10000+rule.RateBps)
+		} else {
+			amount = roundDiv(base*rule.RateBps, 10000)
+		}
+
+		switch rule.Kind {
+		case ChargeKindService:
+			result.ServiceCharge += amount
+			for i, share := range AllocateProportionally(amount, bases) {
+				serviceShares[i] += share
+			}
+		case ChargeKindTax:
+			result.Tax += amount
+			if rule.IsInclusive {
+				result.TaxIncluded += amount
+			}
+		}
+		result.Charges = append(result.Charges, OrderCharge{
+			ID:          uuid.New(),
+			RuleID:      rule.ID,
+			Kind:        rule.Kind,
+			Name:        rule.Name,
+			RateBps:     rule.RateBps,
+			IsInclusive: rule.IsInclusive && rule.Kind == ChargeKindTax,
+			Base:        base,
+			Amount:      amount,
+		})
+	}
+	return result
+}
+
+// roundDiv membagi bilangan non-negatif dan membulatkan ke bilangan bulat terdekat (setengah ke atas).
+func roundDiv(a, b int) int {
+	return (2*a + b) / (2 * b)
+}
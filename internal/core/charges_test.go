@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateCharges(t *testing.T) {
+	food := core.Category{ID: uuid.New(), Name: "Makanan"}
+	drink := core.Category{ID: uuid.New(), Name: "Minuman"}
+	lines := []core.ChargeLine{
+		{CategoryID: food.ID, Subtotal: 100000},
+		{CategoryID: drink.ID, Subtotal: 50000},
+	}
+
+	pb1 := core.ChargeRule{ID: uuid.New(), Kind: core.ChargeKindTax, Name: "PB1", RateBps: 1000, IsActive: true}
+	pb1AfterDiscount := pb1
+	pb1AfterDiscount.AfterDiscount = true
+	pb1ExemptDrink := pb1
+	pb1ExemptDrink.ExemptCategories = []core.Category{drink}
+	pb1Inactive := pb1
+	pb1Inactive.IsActive = false
+	ppnInclusive := core.ChargeRule{ID: uuid.New(), Kind: core.ChargeKindTax, Name: "PPN", RateBps: 1100, IsInclusive: true, IsActive: true}
+	service := core.ChargeRule{ID: uuid.New(), Kind: core.ChargeKindService, Name: "Service", RateBps: 500, IsActive: true}
+
+	testCases := []struct {
+		name            string
+		rules           []core.ChargeRule
+		discount        int
+		expectedService int
+		expectedTax     int
+		expectedIncl    int
+		expectedAdded   int
+		expectedLines   int
+	}{
+		{name: "Sukses - Pajak eksklusif", rules: []core.ChargeRule{pb1}, expectedTax: 15000, expectedAdded: 15000, expectedLines: 1},
+		{name: "Sukses - Pajak juga dikenakan atas service charge", rules: []core.ChargeRule{pb1, service}, expectedService: 7500, expectedTax: 15750, expectedAdded: 23250, expectedLines: 2},
+		{name: "Sukses - Pajak inklusif tidak menambah total", rules: []core.ChargeRule{ppnInclusive}, expectedTax: 14865, expectedIncl: 14865, expectedAdded: 0, expectedLines: 1},
+		{name: "Sukses - Dasar pengenaan sebelum diskon", rules: []core.ChargeRule{pb1}, discount: 30000, expectedTax: 15000, expectedAdded: 15000, expectedLines: 1},
+		{name: "Sukses - Dasar pengenaan setelah diskon", rules: []core.ChargeRule{pb1AfterDiscount}, discount: 30000, expectedTax: 12000, expectedAdded: 12000, expectedLines: 1},
+		{name: "Sukses - Kategori dikecualikan", rules: []core.ChargeRule{pb1ExemptDrink}, expectedTax: 10000, expectedAdded: 10000, expectedLines: 1},
+		{name: "Sukses - Aturan nonaktif diabaikan", rules: []core.ChargeRule{pb1Inactive}, expectedLines: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := core.CalculateCharges(tc.rules, lines, tc.discount)
+
+			assert.Equal(t, tc.expectedService, result.ServiceCharge)
+			assert.Equal(t, tc.expectedTax, result.Tax)
+			assert.Equal(t, tc.expectedIncl, result.TaxIncluded)
+			assert.Equal(t, tc.expectedAdded, result.Added())
+			assert.Len(t, result.Charges, tc.expectedLines)
+		})
+	}
+}
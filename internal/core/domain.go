@@ -17,15 +17,34 @@ const (
 	OrderSourceCashier = "CASHIER"
 	OrderSourceEMenu   = "E_MENU"
 
-	// Order Status
+	// Order Status (lihat order_status.go untuk transisi yang diizinkan)
 	OrderStatusPending   = "PENDING"
+	OrderStatusConfirmed = "CONFIRMED"
+	OrderStatusPreparing = "PREPARING"
+	OrderStatusReady     = "READY"
 	OrderStatusCompleted = "COMPLETED"
 	OrderStatusCancelled = "CANCELLED"
 
 	// Payment Status
-	PaymentStatusUnpaid = "UNPAID"
-	PaymentStatusPaid   = "PAID"
-	PaymentStatusFailed = "FAILED"
+	PaymentStatusUnpaid            = "UNPAID"
+	PaymentStatusPaid              = "PAID"
+	PaymentStatusPartiallyPaid     = "PARTIALLY_PAID" // Split payment: sebagian tagihan sudah dibayar
+	PaymentStatusFailed            = "FAILED"
+	PaymentStatusExpired           = "EXPIRED"            // Payment milik order yang kedaluwarsa karena tidak dibayar
+	PaymentStatusRefunded          = "REFUNDED"           // Seluruh nominal order sudah dikembalikan
+	PaymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED" // Sebagian item sudah dikembalikan
+	PaymentStatusRefundPending     = "REFUND_PENDING"     // Settlement terlambat sedang dikembalikan lewat gateway
+
+	// Refund Status
+	RefundStatusPending   = "PENDING"   // Sudah dialokasikan, menunggu konfirmasi gateway
+	RefundStatusCompleted = "COMPLETED" // Dana sudah dikembalikan
+	RefundStatusFailed    = "FAILED"    // Gateway menolak; alokasi qty & nominal sudah dilepas
+
+	// Promotion Type (lihat ApplyPromotions)
+	PromotionBuyXGetY     = "BUY_X_GET_Y"    // Setiap BuyQty+GetQty unit, GetQty unit termurah didiskon
+	PromotionPercentOff   = "PERCENT_OFF"    // Persen diskon untuk item yang memenuhi syarat
+	PromotionTiered       = "TIERED"         // Persen diskon bertingkat berdasarkan subtotal item yang memenuhi syarat
+	PromotionSpendGetItem = "SPEND_GET_ITEM" // Belanja minimal MinSpend → RewardProduct di keranjang gratis
 
 	// Payment Method
 	PaymentMethodCash     = "CASH"
@@ -35,6 +54,10 @@ const (
 	// Voucher Discount Type
 	DiscountTypePercentage = "PERCENTAGE"
 	DiscountTypeFixed      = "FIXED"
+
+	// Charge Kind (lihat charges.go)
+	ChargeKindService = "SERVICE"
+	ChargeKindTax     = "TAX"
 )
 
 // ==========================================
@@ -48,8 +71,24 @@ type StoreProfile struct {
 	Address   string    `gorm:"type:text" json:"address"`
 	Phone     string    `gorm:"type:varchar(20)" json:"phone"`
 	MarkupFee int       `gorm:"default:0" json:"markup_fee"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// Jam operasional format "HH:MM" (zona waktu toko, lihat Timezone). Kosong = buka 24 jam.
+	// ClosingTime < OpeningTime berarti toko tutup melewati tengah malam.
+	OpeningTime           string    `gorm:"type:varchar(5)" json:"opening_time"`
+	ClosingTime           string    `gorm:"type:varchar(5)" json:"closing_time"`
+	PreOrderLeadMinutes   int       `gorm:"default:30" json:"pre_order_lead_minutes"`                // Pre-order dikirim ke dapur N menit sebelum waktu ambil
+	UnpaidOrderTTLMinutes int       `gorm:"default:0" json:"unpaid_order_ttl_minutes"`               // Order PENDING/UNPAID lebih lama dari ini dibatalkan otomatis; 0 = nonaktif
+	QueuePrefixCashier    string    `gorm:"type:varchar(5);default:'K'" json:"queue_prefix_cashier"` // Skema nomor antrean (queue_number.go); kosong/0 = default
+	QueuePrefixEMenu      string    `gorm:"type:varchar(5);default:'E'" json:"queue_prefix_e_menu"`
+	QueueNumberWidth      int       `gorm:"default:3" json:"queue_number_width"`                        // Jumlah digit (zero-padding)
+	QueueResetPolicy      string    `gorm:"type:varchar(10);default:'DAILY'" json:"queue_reset_policy"` // DAILY | SHIFT | NEVER
+	QueueShiftStarts      string    `gorm:"type:varchar(50)" json:"queue_shift_starts"`                 // Jam mulai shift "06:00,14:00,22:00" (policy SHIFT)
+	BusinessDayCutoffHour int       `gorm:"default:0" json:"business_day_cutoff_hour"`                  // Hari bisnis berganti pada jam ini (0-23), bukan tengah malam
+	CashRoundingMode      string    `gorm:"type:varchar(10);default:'NONE'" json:"cash_rounding_mode"`  // NONE | NEAREST | UP | DOWN (cash_rounding.go)
+	CashRoundingIncrement int       `gorm:"default:0" json:"cash_rounding_increment"`                   // Kelipatan pembulatan, contoh 100 atau 500
+	CashRoundingScope     string    `gorm:"type:varchar(10);default:'CASH'" json:"cash_rounding_scope"` // CASH | ALL
+	Timezone              string    `gorm:"type:varchar(50)" json:"timezone"`                           // Zona waktu IANA toko (jam operasional, hari bisnis, jadwal promo), contoh "Asia/Jakarta"; kosong = zona waktu server
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
 
 // ==========================================
@@ -90,15 +129,88 @@ type Product struct {
 	NormalPrice    int            `gorm:"not null" json:"normal_price"`
 	Stock          int            `gorm:"default:0" json:"stock"` // Dikurangi via pessimistic lock saat checkout
 	IsAvailable    bool           `gorm:"default:true" json:"is_available"`
+	IsBundle       bool           `gorm:"default:false" json:"is_bundle"` // Harga tetap, stok diambil dari komponen (BundleSlots)
 	IsPromoActive  bool           `gorm:"default:false" json:"is_promo_active"`
 	PromoPrice     int            `json:"promo_price"`
-	PromoStartTime string         `gorm:"type:varchar(5)" json:"promo_start_time"` // Format "HH:MM"
+	PromoStartTime string         `gorm:"type:varchar(5)" json:"promo_start_time"` // Format "HH:MM"; diabaikan jika produk punya PromoSchedules
 	PromoEndTime   string         `gorm:"type:varchar(5)" json:"promo_end_time"`   // Format "HH:MM"
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Category       *Category       `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	ModifierGroups []ModifierGroup `gorm:"foreignKey:ProductID" json:"modifier_groups,omitempty"`
+	BundleSlots    []BundleSlot    `gorm:"foreignKey:BundleID" json:"bundle_slots,omitempty"`
+	PromoSchedules []PromoSchedule `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"promo_schedules,omitempty"`
+}
+
+// PromoSchedule adalah satu jendela waktu berlakunya harga promo produk (lihat PromoActiveAt).
+// Semua kolom kosong berarti tanpa batas pada dimensi tersebut; dievaluasi di zona waktu toko.
+type PromoSchedule struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"`
+	Weekdays  string    `gorm:"type:varchar(20)" json:"weekdays"`   // Contoh "1,2,3,4,5" (0 = Minggu); kosong = setiap hari
+	StartTime string    `gorm:"type:varchar(5)" json:"start_time"`  // "HH:MM"; StartTime > EndTime = melewati tengah malam
+	EndTime   string    `gorm:"type:varchar(5)" json:"end_time"`    // "HH:MM", eksklusif
+	StartDate string    `gorm:"type:varchar(10)" json:"start_date"` // "YYYY-MM-DD", inklusif
+	EndDate   string    `gorm:"type:varchar(10)" json:"end_date"`   // "YYYY-MM-DD", inklusif
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ModifierGroup adalah satu kelompok pilihan pada produk, misal "Ukuran" atau "Level Gula".
+// Jumlah opsi yang boleh dipilih dibatasi MinSelect..MaxSelect (lihat SelectModifiers).
+type ModifierGroup struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProductID  uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"`
+	Name       string    `gorm:"type:varchar(100);not null" json:"name"`
+	IsRequired bool      `gorm:"default:false" json:"is_required"` // Wajib dipilih minimal satu opsi
+	MinSelect  int       `gorm:"default:0" json:"min_select"`
+	MaxSelect  int       `gorm:"not null" json:"max_select"` // 0 = tanpa batas
+	SortOrder  int       `gorm:"default:0" json:"sort_order"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Options []ModifierOption `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"options"`
+}
+
+// ModifierOption adalah satu pilihan dalam ModifierGroup beserta selisih harganya terhadap harga produk.
+type ModifierOption struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	GroupID     uuid.UUID `gorm:"type:uuid;not null;index" json:"group_id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	PriceDelta  int       `gorm:"default:0" json:"price_delta"`     // Boleh negatif, misal ukuran kecil -3000
+	TrackStock  bool      `gorm:"default:false" json:"track_stock"` // true = Stock dipotong seperti stok produk
+	Stock       int       `gorm:"default:0" json:"stock"`
+	IsAvailable bool      `gorm:"not null" json:"is_available"` // Tanpa default DB: false dari request harus tetap false
+	SortOrder   int       `gorm:"default:0" json:"sort_order"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BundleSlot adalah satu komponen produk bundle, misal "Kopi" atau "Pastry".
+// Slot dengan satu opsi adalah komponen tetap; slot dengan beberapa opsi dipilih pelanggan
+// saat checkout (lihat SelectBundleComponents).
+type BundleSlot struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	BundleID  uuid.UUID `gorm:"type:uuid;not null;index" json:"bundle_id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	Qty       int       `gorm:"not null" json:"qty"` // Jumlah komponen per satu bundle
+	SortOrder int       `gorm:"default:0" json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Options []BundleSlotOption `gorm:"foreignKey:SlotID;constraint:OnDelete:CASCADE" json:"options"`
+}
+
+// BundleSlotOption adalah produk yang boleh mengisi sebuah BundleSlot.
+type BundleSlotOption struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	SlotID    uuid.UUID `gorm:"type:uuid;not null;index" json:"slot_id"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null" json:"product_id"`
+	SortOrder int       `gorm:"default:0" json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Product *Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
 }
 
 // ==========================================
@@ -117,16 +229,81 @@ type Voucher struct {
 	CreatedAt         time.Time `json:"created_at"`
 }
 
+// ==========================================
+// PROMOTIONS (otomatis, tanpa kode)
+// ==========================================
+
+// Promotion adalah aturan promo otomatis yang dievaluasi saat checkout (lihat ApplyPromotions).
+// Target ProductID/CategoryID kosong berarti semua produk. Jadwal memakai aturan yang sama dengan
+// PromoSchedule dan dievaluasi di zona waktu toko.
+type Promotion struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name             string     `gorm:"type:varchar(100);not null" json:"name"` // Dicetak di struk, contoh "Beli 2 Gratis 1"
+	Type             string     `gorm:"type:varchar(20);not null" json:"type"`
+	Priority         int        `gorm:"not null" json:"priority"`           // Lebih kecil dievaluasi lebih dulu
+	StackWithVoucher bool       `gorm:"not null" json:"stack_with_voucher"` // false = dilewati jika order memakai voucher
+	IsActive         bool       `gorm:"not null" json:"is_active"`
+	ProductID        *uuid.UUID `gorm:"type:uuid" json:"product_id"`
+	CategoryID       *uuid.UUID `gorm:"type:uuid" json:"category_id"`
+
+	BuyQty          int        `gorm:"not null;default:0" json:"buy_qty"`          // BUY_X_GET_Y
+	GetQty          int        `gorm:"not null;default:0" json:"get_qty"`          // BUY_X_GET_Y & SPEND_GET_ITEM: jumlah unit hadiah
+	DiscountPercent int        `gorm:"not null;default:0" json:"discount_percent"` // PERCENT_OFF; BUY_X_GET_Y & SPEND_GET_ITEM: 0 = gratis
+	MinQty          int        `gorm:"not null;default:0" json:"min_qty"`          // PERCENT_OFF: minimal unit yang memenuhi syarat
+	MinSpend        int        `gorm:"not null;default:0" json:"min_spend"`        // SPEND_GET_ITEM: subtotal keranjang minimal
+	RewardProductID *uuid.UUID `gorm:"type:uuid" json:"reward_product_id"`         // SPEND_GET_ITEM
+
+	Weekdays  string    `gorm:"type:varchar(20)" json:"weekdays"` // Format sama dengan PromoSchedule
+	StartTime string    `gorm:"type:varchar(5)" json:"start_time"`
+	EndTime   string    `gorm:"type:varchar(5)" json:"end_time"`
+	StartDate string    `gorm:"type:varchar(10)" json:"start_date"`
+	EndDate   string    `gorm:"type:varchar(10)" json:"end_date"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Tiers []PromotionTier `gorm:"foreignKey:PromotionID;constraint:OnDelete:CASCADE" json:"tiers,omitempty"` // TIERED
+}
+
+// PromotionTier adalah satu tingkat promo TIERED: subtotal minimal → persen diskon.
+type PromotionTier struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	PromotionID     uuid.UUID `gorm:"type:uuid;not null;index" json:"promotion_id"`
+	MinSpend        int       `gorm:"not null" json:"min_spend"`
+	DiscountPercent int       `gorm:"not null" json:"discount_percent"`
+}
+
+// ==========================================
+// TAX & SERVICE CHARGE
+// ==========================================
+
+// ChargeRule adalah aturan service charge atau pajak (PPN/PB1) yang dikenakan ke setiap order.
+// Tarif dalam basis poin agar tetap integer: 1000 = 10%, 1100 = 11%.
+type ChargeRule struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Kind          string    `gorm:"type:varchar(10);not null" json:"kind"` // SERVICE | TAX
+	Name          string    `gorm:"type:varchar(50);not null" json:"name"` // Label di struk, misal "PB1" atau "Service"
+	RateBps       int       `gorm:"not null" json:"rate_bps"`
+	IsInclusive   bool      `gorm:"not null" json:"is_inclusive"`   // Hanya TAX: harga menu sudah termasuk pajak, total tidak bertambah
+	AfterDiscount bool      `gorm:"not null" json:"after_discount"` // true = dasar pengenaan dihitung setelah diskon voucher
+	IsActive      bool      `gorm:"not null" json:"is_active"`
+	SortOrder     int       `gorm:"default:0" json:"sort_order"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	ExemptCategories []Category `gorm:"many2many:charge_rule_exempt_categories" json:"exempt_categories"`
+}
+
 // ==========================================
 // DAILY COUNTER (Untuk atomic queue number)
 // ==========================================
 
-// DailyCounter adalah Single Source of Truth untuk nomor antrean per hari per source.
+// DailyCounter adalah Single Source of Truth untuk nomor antrean per periode per source.
+// Periode mengikuti StoreProfile.QueuePeriod (hari bisnis, shift, atau tanpa reset).
 // Menggunakan FOR UPDATE pessimistic lock saat increment untuk mencegah race condition.
 type DailyCounter struct {
-	ID        string `gorm:"type:varchar(50);primaryKey" json:"id"`     // Format: "CASHIER-20260221"
-	Date      string `gorm:"type:varchar(10);not null" json:"date"`      // Format: "20260221"
-	Source    string `gorm:"type:varchar(50);not null" json:"source"`    // CASHIER | E_MENU
+	ID        string `gorm:"type:varchar(50);primaryKey" json:"id"`   // Format: "CASHIER-20260221"
+	Date      string `gorm:"type:varchar(10);not null" json:"date"`   // Periode: "20260221" | "20260221-2" | "ALL"
+	Source    string `gorm:"type:varchar(50);not null" json:"source"` // CASHIER | E_MENU
 	LastCount int    `gorm:"not null;default:0" json:"last_count"`
 }
 
@@ -136,48 +313,168 @@ type DailyCounter struct {
 
 type Order struct {
 	ID               uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	VoucherID        *uuid.UUID `gorm:"type:uuid" json:"voucher_id"` // Pointer karena opsional
-	OrderSource      string     `gorm:"type:varchar(50);not null;default:'CASHIER'" json:"order_source"` // CASHIER | E_MENU
-	QueueNumber      string     `gorm:"type:varchar(20)" json:"queue_number"`                            // K-001 | E-001
-	TableNumber      *string    `gorm:"type:varchar(50)" json:"table_number"`
+	VoucherID        *uuid.UUID `gorm:"type:uuid" json:"voucher_id"`                                                                   // Pointer karena opsional
+	OrderSource      string     `gorm:"type:varchar(50);not null;default:'CASHIER'" json:"order_source"`                               // CASHIER | E_MENU
+	QueueNumber      string     `gorm:"type:varchar(20)" json:"queue_number"`                                                          // K-001 | E-001
+	TrackingToken    string     `gorm:"type:varchar(64);uniqueIndex" json:"tracking_token"`                                            // Rahasia acak untuk lacak order publik
+	TableNumber      *string    `gorm:"type:varchar(50);uniqueIndex:idx_orders_open_tab,where:is_open_tab = true" json:"table_number"` // Maks. satu tab terbuka per meja
 	OrderStatus      string     `gorm:"type:varchar(50);default:'PENDING'" json:"order_status"`
 	PaymentStatus    string     `gorm:"type:varchar(50);default:'UNPAID'" json:"payment_status"`
 	TotalBasePrice   int        `gorm:"not null" json:"total_base_price"`
 	TotalDiscount    int        `gorm:"default:0" json:"total_discount"`
 	PlatformFee      int        `gorm:"default:0" json:"platform_fee"`
 	TotalFinalAmount int        `gorm:"not null" json:"total_final_amount"`
-	CreatedAt        time.Time  `json:"created_at"`
+	TotalPaid        int        `gorm:"default:0" json:"total_paid"` // Jumlah AmountPaid dari semua Payment PAID
+	TotalRefunded    int        `gorm:"default:0" json:"total_refunded"`
+	CancelReason     string     `gorm:"type:varchar(255)" json:"cancel_reason,omitempty"`
+	CancelledAt      *time.Time `gorm:"type:timestamptz" json:"cancelled_at,omitempty"`
+	IsOpenTab        bool       `gorm:"default:false" json:"is_open_tab"` // Tab dine-in: item ditambah per ronde, dibayar sekali setelah ditutup
+	TabClosedAt      *time.Time `gorm:"type:timestamptz" json:"tab_closed_at,omitempty"`
+	ParentOrderID    *uuid.UUID `gorm:"type:uuid;index" json:"parent_order_id,omitempty"`      // Diisi pada bill hasil split: menunjuk order asal
+	MergedIntoID     *uuid.UUID `gorm:"type:uuid;index" json:"merged_into_id,omitempty"`       // Diisi pada order yang digabung: menunjuk order tujuan
+	ScheduledFor     *time.Time `gorm:"type:timestamptz;index" json:"scheduled_for,omitempty"` // Waktu ambil pre-order; nil = dibuat untuk segera
+	ReleasedAt       *time.Time `gorm:"type:timestamptz" json:"released_at,omitempty"`         // Kapan pre-order dikirim ke kitchen display
+	IdempotencyKey   *string    `gorm:"type:varchar(100);uniqueIndex" json:"-"`                // Header Idempotency-Key dari kasir (retry aman)
+	RequestHash      string     `gorm:"type:varchar(64)" json:"-"`                             // SHA-256 body checkout untuk mendeteksi key dipakai ulang
+	CreatedAt        time.Time  `gorm:"index" json:"created_at"`                               // Dipakai keyset pagination (created_at, id)
 	UpdatedAt        time.Time  `json:"updated_at"`
 
-	Voucher  *Voucher    `gorm:"foreignKey:VoucherID" json:"voucher,omitempty"`
-	Items    []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
-	Payments []Payment   `gorm:"foreignKey:OrderID" json:"payments"`
+	// TotalPromoDiscount adalah bagian TotalDiscount dari promo otomatis (jumlah PromoDiscount item);
+	// sisanya diskon voucher yang dihitung setelah promo.
+	TotalPromoDiscount int `gorm:"not null;default:0" json:"total_promo_discount"`
+
+	// Service charge & pajak (lihat CalculateCharges). TotalTaxIncluded adalah bagian TotalTax
+	// yang sudah termasuk di harga menu, sehingga tidak ikut ditambahkan ke TotalFinalAmount.
+	TotalServiceCharge int `gorm:"not null;default:0" json:"total_service_charge"`
+	TotalTax           int `gorm:"not null;default:0" json:"total_tax"`
+	TotalTaxIncluded   int `gorm:"not null;default:0" json:"total_tax_included"`
+
+	// OutstandingAmount tidak disimpan, dihitung ulang setiap kali order dibaca (lihat AfterFind)
+	OutstandingAmount int `gorm:"-" json:"outstanding_amount"`
+
+	Voucher       *Voucher             `gorm:"foreignKey:VoucherID" json:"voucher,omitempty"`
+	Items         []OrderItem          `gorm:"foreignKey:OrderID" json:"items"`
+	Payments      []Payment            `gorm:"foreignKey:OrderID" json:"payments"`
+	StatusHistory []OrderStatusHistory `gorm:"foreignKey:OrderID" json:"status_history,omitempty"`
+	Refunds       []Refund             `gorm:"foreignKey:OrderID" json:"refunds,omitempty"`
+	Charges       []OrderCharge        `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"charges"`
+}
+
+// OrderStatusHistory mencatat setiap perpindahan OrderStatus: dari mana, ke mana, oleh siapa, dan kapan.
+type OrderStatusHistory struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"order_id"`
+	FromStatus string     `gorm:"type:varchar(50);not null" json:"from_status"`
+	ToStatus   string     `gorm:"type:varchar(50);not null" json:"to_status"`
+	ChangedBy  *uuid.UUID `gorm:"type:uuid" json:"changed_by"` // User ID dari JWT, nil jika perubahan oleh sistem
+	Note       string     `gorm:"type:varchar(255)" json:"note"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 type OrderItem struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	OrderID   uuid.UUID `gorm:"type:uuid;not null" json:"order_id"`
-	ProductID uuid.UUID `gorm:"type:uuid;not null" json:"product_id"`
-	Qty       int       `gorm:"not null" json:"qty"`
-	UnitPrice int       `gorm:"not null" json:"unit_price"`
-	Subtotal  int       `gorm:"not null" json:"subtotal"`
-	Notes     string    `gorm:"type:varchar(255)" json:"notes"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderID     uuid.UUID `gorm:"type:uuid;not null" json:"order_id"`
+	ProductID   uuid.UUID `gorm:"type:uuid;not null" json:"product_id"`
+	Qty         int       `gorm:"not null" json:"qty"`
+	UnitPrice   int       `gorm:"not null" json:"unit_price"`
+	Subtotal    int       `gorm:"not null" json:"subtotal"`
+	Notes       string    `gorm:"type:varchar(255)" json:"notes"`
+	RefundedQty int       `gorm:"not null;default:0" json:"refunded_qty"` // Tidak boleh melebihi Qty
+	Round       int       `gorm:"not null;default:1" json:"round"`        // Ronde pemesanan pada tab (order biasa selalu 1)
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Promo otomatis yang memberi diskon pada baris ini (snapshot; bukan FK agar promo boleh dihapus).
+	// Subtotal tetap sebelum diskon; PromoDiscount sudah termasuk di Order.TotalDiscount.
+	PromotionID   *uuid.UUID `gorm:"type:uuid" json:"promotion_id"`
+	PromotionName string     `gorm:"type:varchar(100)" json:"promotion_name"`
+	PromoDiscount int        `gorm:"not null;default:0" json:"promo_discount"`
 
-	Product Product `gorm:"foreignKey:ProductID" json:"product"`
+	Product    Product              `gorm:"foreignKey:ProductID" json:"product"`
+	Modifiers  []OrderItemModifier  `gorm:"foreignKey:OrderItemID;constraint:OnDelete:CASCADE" json:"modifiers"`
+	Components []OrderItemComponent `gorm:"foreignKey:OrderItemID;constraint:OnDelete:CASCADE" json:"components"` // Hanya untuk bundle
+}
+
+// OrderItemModifier adalah snapshot opsi modifier yang dipilih untuk satu OrderItem.
+// Nama dan harga disalin saat dipesan agar struk & laporan tidak berubah ketika menu diedit.
+type OrderItemModifier struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderItemID      uuid.UUID `gorm:"type:uuid;not null;index" json:"order_item_id"`
+	ModifierOptionID uuid.UUID `gorm:"type:uuid;not null;index" json:"modifier_option_id"` // Bukan FK: opsi boleh dihapus dari menu
+	GroupName        string    `gorm:"type:varchar(100);not null" json:"group_name"`
+	OptionName       string    `gorm:"type:varchar(100);not null" json:"option_name"`
+	PriceDelta       int       `gorm:"not null;default:0" json:"price_delta"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// OrderItemComponent adalah snapshot isi bundle untuk satu OrderItem. Stok yang dipotong,
+// dikembalikan saat batal, dan di-restock saat refund adalah stok komponen ini, bukan stok bundle.
+type OrderItemComponent struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;not null;index" json:"order_item_id"`
+	ProductID   uuid.UUID `gorm:"type:uuid;not null;index" json:"product_id"` // Bukan FK: produk boleh dihapus dari katalog
+	SlotName    string    `gorm:"type:varchar(100);not null" json:"slot_name"`
+	ProductName string    `gorm:"type:varchar(255);not null" json:"product_name"`
+	Qty         int       `gorm:"not null" json:"qty"` // Per satu unit bundle
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// OrderCharge adalah snapshot satu baris service charge/pajak pada order, dicetak apa adanya di struk.
+type OrderCharge struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderID     uuid.UUID `gorm:"type:uuid;not null;index" json:"order_id"`
+	RuleID      uuid.UUID `gorm:"type:uuid;not null" json:"rule_id"` // Bukan FK: aturan boleh diubah/dihapus
+	Kind        string    `gorm:"type:varchar(10);not null" json:"kind"`
+	Name        string    `gorm:"type:varchar(50);not null" json:"name"`
+	RateBps     int       `gorm:"not null" json:"rate_bps"`
+	IsInclusive bool      `gorm:"not null" json:"is_inclusive"`
+	Base        int       `gorm:"not null" json:"base"` // Dasar pengenaan
+	Amount      int       `gorm:"not null" json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type Payment struct {
-	ID                   uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	OrderID              uuid.UUID  `gorm:"type:uuid;not null" json:"order_id"`
-	PaymentMethod        string     `gorm:"type:varchar(50);not null" json:"payment_method"` // CASH | QRIS | TRANSFER
-	MidtransTransactionID *string   `gorm:"type:varchar(255)" json:"midtrans_transaction_id"`
-	IdempotencyKey       string     `gorm:"type:varchar(255);uniqueIndex" json:"idempotency_key"` // Midtrans order_id, mencegah duplikasi webhook
-	AmountPaid           int        `gorm:"not null" json:"amount_paid"`
-	PaymentStatus        string     `gorm:"type:varchar(50);not null;default:'UNPAID'" json:"payment_status"` // UNPAID | PAID | FAILED
-	PaidAt               *time.Time `gorm:"type:timestamptz" json:"paid_at"`
-	WebhookReceivedAt    *time.Time `gorm:"type:timestamptz" json:"webhook_received_at"` // Timestamp saat webhook diterima pertama kali
-	CreatedAt            time.Time  `json:"created_at"`
+	ID                    uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderID               uuid.UUID  `gorm:"type:uuid;not null" json:"order_id"`
+	PaymentMethod         string     `gorm:"type:varchar(50);not null" json:"payment_method"` // CASH | QRIS | TRANSFER
+	MidtransTransactionID *string    `gorm:"type:varchar(255)" json:"midtrans_transaction_id"`
+	IdempotencyKey        string     `gorm:"type:varchar(255);uniqueIndex" json:"idempotency_key"` // Midtrans order_id, mencegah duplikasi webhook
+	AmountPaid            int        `gorm:"not null" json:"amount_paid"`
+	AmountTendered        int        `gorm:"default:0" json:"amount_tendered"`                                 // Khusus CASH: uang yang diserahkan pelanggan
+	ChangeAmount          int        `gorm:"default:0" json:"change_amount"`                                   // Khusus CASH: kembalian = AmountTendered - (AmountPaid + RoundingAdjustment)
+	RoundingAdjustment    int        `gorm:"not null;default:0" json:"rounding_adjustment"`                    // Selisih pembulatan; yang diterima = AmountPaid + RoundingAdjustment
+	PaymentStatus         string     `gorm:"type:varchar(50);not null;default:'UNPAID'" json:"payment_status"` // UNPAID | PAID | FAILED
+	PaidAt                *time.Time `gorm:"type:timestamptz" json:"paid_at"`
+	WebhookReceivedAt     *time.Time `gorm:"type:timestamptz" json:"webhook_received_at"` // Timestamp saat webhook diterima pertama kali
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+// ==========================================
+// REFUNDS
+// ==========================================
+
+// Refund adalah satu kejadian pengembalian dana atas sebagian/seluruh item order yang sudah lunas.
+type Refund struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrderID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"order_id"`
+	PaymentID       uuid.UUID  `gorm:"type:uuid;not null" json:"payment_id"` // Payment PAID yang dananya dikembalikan
+	Amount          int        `gorm:"not null" json:"amount"`
+	Reason          string     `gorm:"type:varchar(255);not null" json:"reason"`
+	Restock         bool       `gorm:"default:false" json:"restock"`                                // true = stok produk dikembalikan
+	GatewayRefundID *string    `gorm:"type:varchar(255)" json:"gateway_refund_id"`                  // nil untuk refund tunai
+	Status          string     `gorm:"type:varchar(20);not null;default:'COMPLETED'" json:"status"` // PENDING | COMPLETED | FAILED
+	CreatedBy       *uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+
+	Items []RefundItem `gorm:"foreignKey:RefundID" json:"items"`
+}
+
+// RefundItem menghubungkan Refund dengan OrderItem yang dikembalikan beserta qty dan nominalnya.
+type RefundItem struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	RefundID    uuid.UUID `gorm:"type:uuid;not null;index" json:"refund_id"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;not null;index" json:"order_item_id"`
+	Qty         int       `gorm:"not null" json:"qty"`
+	Amount      int       `gorm:"not null" json:"amount"`
 }
 
 // ==========================================
@@ -192,4 +489,13 @@ func (c *Category) BeforeSave(tx *gorm.DB) (err error) {
 func (p *Product) BeforeSave(tx *gorm.DB) (err error) {
 	p.Slug = slug.Make(p.Name)
 	return
-}
\ No newline at end of file
+}
+
+// AfterFind menghitung sisa tagihan order agar selalu konsisten dengan TotalPaid.
+func (o *Order) AfterFind(tx *gorm.DB) (err error) {
+	o.OutstandingAmount = o.TotalFinalAmount - o.TotalPaid
+	if o.OutstandingAmount < 0 {
+		o.OutstandingAmount = 0
+	}
+	return
+}
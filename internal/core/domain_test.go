@@ -0,0 +1,31 @@
+package core_test
+
+import (
+	"sync"
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+// Satu meja maksimal punya satu tab terbuka; OpenTab mengandalkan index ini saat dua kasir balapan.
+func TestOrderOpenTabIndex(t *testing.T) {
+	s, err := schema.Parse(&core.Order{}, &sync.Map{}, schema.NamingStrategy{})
+	assert.NoError(t, err)
+
+	var found *schema.Index
+	for _, idx := range s.ParseIndexes() {
+		if idx.Name == "idx_orders_open_tab" {
+			found = idx
+		}
+	}
+	if assert.NotNil(t, found) {
+		assert.Equal(t, "UNIQUE", found.Class)
+		assert.Equal(t, "is_open_tab = true", found.Where)
+		if assert.Len(t, found.Fields, 1) {
+			assert.Equal(t, "table_number", found.Fields[0].DBName)
+		}
+	}
+}
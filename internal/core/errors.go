@@ -11,6 +11,37 @@ var (
 	ErrVoucherInvalid    = errors.New("voucher tidak valid atau sudah kadaluarsa")
 	ErrVoucherMinOrder   = errors.New("total pesanan tidak memenuhi minimum untuk voucher ini")
 	ErrOrderAlreadyPaid  = errors.New("pesanan sudah dibayar")
+	ErrOrderNotPaid      = errors.New("pesanan belum dibayar")
+	ErrOrderCancelled    = errors.New("pesanan sudah dibatalkan")
+	ErrInvalidTransition = errors.New("perubahan status pesanan tidak diizinkan")
+	ErrOrderNotEditable  = errors.New("pesanan hanya bisa diubah selama berstatus PENDING dan belum dibayar")
+	ErrPaymentInProgress = errors.New("pesanan memiliki pembayaran yang sedang diproses, void terlebih dahulu")
+	ErrTabAlreadyOpen    = errors.New("meja ini masih memiliki tab yang terbuka")
+	ErrTabNotOpen        = errors.New("pesanan bukan tab yang sedang terbuka")
+	ErrTabOpen           = errors.New("tab masih terbuka, tutup tab sebelum melakukan pembayaran")
+	ErrIdempotencyReuse  = errors.New("idempotency key sudah dipakai untuk request checkout yang berbeda")
+	ErrInvalidSchedule   = errors.New("waktu ambil pre-order harus di masa depan dan maksimal 7 hari ke depan")
+	ErrStoreClosed       = errors.New("waktu ambil berada di luar jam operasional toko")
+	ErrInvalidSplit      = errors.New("pembagian tagihan tidak valid")
+	ErrVoucherConflict   = errors.New("pesanan yang digabung memakai voucher berbeda, lepas salah satu voucher terlebih dahulu")
+	ErrEmptyOrder        = errors.New("pesanan harus memiliki minimal satu item, batalkan pesanan untuk menghapus semuanya")
 	ErrInvalidSignature  = errors.New("signature webhook tidak valid")
+	ErrInvalidTableToken = errors.New("QR meja tidak valid, silakan scan ulang")
+	ErrRefundExceedsQty  = errors.New("jumlah refund melebihi item yang dapat dikembalikan")
+	ErrRefundExceedsPaid = errors.New("nominal refund melebihi dana pada pembayaran ini")
+	ErrOverpayment       = errors.New("nominal pembayaran melebihi sisa tagihan")
+	ErrInsufficientCash  = errors.New("uang yang diterima kurang dari nominal yang harus dibayar")
+	ErrPaymentNotPending = errors.New("pembayaran tidak dalam status menunggu")
+	ErrPaymentGateway    = errors.New("gagal memproses permintaan ke payment gateway")
+	ErrInvalidCursor     = errors.New("cursor paginasi tidak valid")
+	ErrInvalidDateFilter = errors.New("filter tanggal tidak valid")
+	ErrReceiptFormat     = errors.New("format struk tidak didukung untuk jenis ini")
+	ErrQueueScheme       = errors.New("pengaturan nomor antrean tidak valid")
+	ErrCashRounding      = errors.New("pengaturan pembulatan pembayaran tidak valid")
+	ErrInvalidModifier   = errors.New("pilihan modifier tidak valid")
+	ErrInvalidBundle     = errors.New("isi bundle tidak valid")
+	ErrInvalidPromo      = errors.New("jadwal promo tidak valid")
+	ErrInvalidPromotion  = errors.New("aturan promo otomatis tidak valid")
+	ErrInvalidChargeRule = errors.New("aturan pajak/service charge tidak valid")
 	ErrInternalServer    = errors.New("terjadi kesalahan pada server")
 )
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipe event siklus hidup order yang dikirim ke kitchen display.
+const (
+	EventOrderSnapshot      = "order.snapshot" // Replay order yang masih terbuka saat client (re)connect
+	EventOrderCreated       = "order.created"
+	EventOrderPaid          = "order.paid"
+	EventOrderStatusChanged = "order.status_changed"
+	EventOrderItemsChanged  = "order.items_changed"
+	EventOrderRoundAdded    = "order.round_added" // Ronde baru pada tab dine-in
+	EventOrderReleased      = "order.released"    // Pre-order masuk lead time dan mulai disiapkan dapur
+)
+
+// OrderEvent adalah notifikasi ringan: hanya identitas dan status order.
+// Subscriber yang butuh detail (item, catatan) memuat ulang order dari database.
+type OrderEvent struct {
+	Type          string    `json:"type"`
+	OrderID       uuid.UUID `json:"order_id"`
+	OrderSource   string    `json:"order_source"`
+	OrderStatus   string    `json:"order_status"`
+	PaymentStatus string    `json:"payment_status"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// OrderEventPublisher adalah PORT untuk menyiarkan event order. Dipanggil SETELAH commit
+// agar subscriber tidak pernah melihat data yang kemudian di-rollback.
+type OrderEventPublisher interface {
+	Publish(event OrderEvent)
+}
+
+// OrderEventSubscriber adalah PORT untuk berlangganan event order.
+// Fungsi yang dikembalikan WAJIB dipanggil saat koneksi client ditutup.
+type OrderEventSubscriber interface {
+	Subscribe() (<-chan OrderEvent, func())
+}
+
+// OrderEventBus menggabungkan kedua port di atas (diimplementasikan oleh realtime.Hub).
+type OrderEventBus interface {
+	OrderEventPublisher
+	OrderEventSubscriber
+}
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// LessProductID adalah SATU-SATUNYA urutan penguncian baris produk (FOR UPDATE).
+// Semua operasi yang mengunci beberapa produk sekaligus (checkout, pembatalan, refund, dst.)
+// WAJIB mengunci dalam urutan ini agar tidak terjadi circular wait → deadlock.
+func LessProductID(a, b uuid.UUID) bool {
+	return strings.Compare(a.String(), b.String()) < 0
+}
+
+// LessOrderID adalah urutan penguncian jika beberapa baris order dikunci sekaligus (merge bill).
+// Order selalu dikunci SEBELUM produk dan payment.
+func LessOrderID(a, b uuid.UUID) bool {
+	return strings.Compare(a.String(), b.String()) < 0
+}
+
+// LessModifierOptionID adalah urutan penguncian stok opsi modifier.
+// Opsi modifier selalu dikunci SETELAH semua produk dalam transaksi yang sama.
+func LessModifierOptionID(a, b uuid.UUID) bool {
+	return strings.Compare(a.String(), b.String()) < 0
+}
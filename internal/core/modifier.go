@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// MinSelections mengembalikan jumlah minimal opsi yang wajib dipilih pada group ini.
+func (g *ModifierGroup) MinSelections() int {
+	if g.IsRequired && g.MinSelect < 1 {
+		return 1
+	}
+	return g.MinSelect
+}
+
+// SelectModifiers memvalidasi opsi yang dipilih pelanggan terhadap modifier group sebuah produk
+// dan mengembalikan snapshot-nya (urut sesuai SortOrder group lalu opsi). Aturannya:
+//   - setiap opsi harus milik salah satu group produk dan sedang tersedia;
+//   - jumlah opsi per group harus di antara MinSelections() dan MaxSelect (0 = tanpa batas).
+//
+// Stok opsi TIDAK dicek di sini — stok dicek setelah baris opsi dikunci di dalam transaksi.
+func SelectModifiers(groups []ModifierGroup, optionIDs []uuid.UUID) ([]OrderItemModifier, error) {
+	type selected struct {
+		group  *ModifierGroup
+		option *ModifierOption
+	}
+	byOptionID := make(map[uuid.UUID]selected)
+	for i := range groups {
+		for j := range groups[i].Options {
+			byOptionID[groups[i].Options[j].ID] = selected{group: &groups[i], option: &groups[i].Options[j]}
+		}
+	}
+
+	counts := make(map[uuid.UUID]int, len(groups))
+	chosen := make([]selected, 0, len(optionIDs))
+	seen := make(map[uuid.UUID]bool, len(optionIDs))
+	for _, id := range optionIDs {
+		sel, ok := byOptionID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: opsi %s bukan milik produk ini", ErrInvalidModifier, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: opsi %s dipilih lebih dari sekali", ErrInvalidModifier, sel.option.Name)
+		}
+		if !sel.option.IsAvailable {
+			return nil, fmt.Errorf("%w: %s sedang tidak tersedia", ErrInvalidModifier, sel.option.Name)
+		}
+		seen[id] = true
+		counts[sel.group.ID]++
+		chosen = append(chosen, sel)
+	}
+
+	for i := range groups {
+		group := &groups[i]
+		count := counts[group.ID]
+		if count < group.MinSelections() {
+			return nil, fmt.Errorf("%w: %s wajib dipilih minimal %d", ErrInvalidModifier, group.Name, group.MinSelections())
+		}
+		if group.MaxSelect > 0 && count > group.MaxSelect {
+			return nil, fmt.Errorf("%w: %s maksimal %d pilihan", ErrInvalidModifier, group.Name, group.MaxSelect)
+		}
+	}
+
+	sort.SliceStable(chosen, func(i, j int) bool {
+		if chosen[i].group.SortOrder != chosen[j].group.SortOrder {
+			return chosen[i].group.SortOrder < chosen[j].group.SortOrder
+		}
+		return chosen[i].option.SortOrder < chosen[j].option.SortOrder
+	})
+
+	modifiers := make([]OrderItemModifier, 0, len(chosen))
+	for _, sel := range chosen {
+		modifiers = append(modifiers, OrderItemModifier{
+			ID:               uuid.New(),
+			ModifierOptionID: sel.option.ID,
+			GroupName:        sel.group.Name,
+			OptionName:       sel.option.Name,
+			PriceDelta:       sel.option.PriceDelta,
+		})
+	}
+	return modifiers, nil
+}
+
+// ModifierDelta adalah total selisih harga semua modifier pada satu unit item.
+func (i *OrderItem) ModifierDelta() int {
+	delta := 0
+	for _, m := range i.Modifiers {
+		delta += m.PriceDelta
+	}
+	return delta
+}
@@ -0,0 +1,59 @@
+package core_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectModifiers(t *testing.T) {
+	regular := core.ModifierOption{ID: uuid.New(), Name: "Regular", IsAvailable: true, SortOrder: 1}
+	large := core.ModifierOption{ID: uuid.New(), Name: "Large", PriceDelta: 5000, IsAvailable: true, SortOrder: 2}
+	extraShot := core.ModifierOption{ID: uuid.New(), Name: "Extra Shot", PriceDelta: 4000, IsAvailable: true, SortOrder: 1}
+	oatMilk := core.ModifierOption{ID: uuid.New(), Name: "Oat Milk", PriceDelta: 6000, IsAvailable: false, SortOrder: 2}
+	boba := core.ModifierOption{ID: uuid.New(), Name: "Boba", PriceDelta: 3000, IsAvailable: true, SortOrder: 3}
+
+	groups := []core.ModifierGroup{
+		{ID: uuid.New(), Name: "Ukuran", IsRequired: true, MaxSelect: 1, SortOrder: 1, Options: []core.ModifierOption{regular, large}},
+		{ID: uuid.New(), Name: "Topping", MaxSelect: 1, SortOrder: 2, Options: []core.ModifierOption{extraShot, oatMilk, boba}},
+	}
+
+	testCases := []struct {
+		name          string
+		optionIDs     []uuid.UUID
+		expectedNames []string
+		expectedDelta int
+		expectedError bool
+	}{
+		{name: "Sukses - Opsi wajib saja", optionIDs: []uuid.UUID{large.ID}, expectedNames: []string{"Large"}, expectedDelta: 5000},
+		{name: "Sukses - Urut sesuai group", optionIDs: []uuid.UUID{extraShot.ID, regular.ID}, expectedNames: []string{"Regular", "Extra Shot"}, expectedDelta: 4000},
+		{name: "Gagal - Group wajib tidak dipilih", optionIDs: []uuid.UUID{extraShot.ID}, expectedError: true},
+		{name: "Gagal - Melebihi max_select", optionIDs: []uuid.UUID{regular.ID, extraShot.ID, boba.ID}, expectedError: true},
+		{name: "Gagal - Opsi tidak tersedia", optionIDs: []uuid.UUID{regular.ID, oatMilk.ID}, expectedError: true},
+		{name: "Gagal - Opsi milik produk lain", optionIDs: []uuid.UUID{regular.ID, uuid.New()}, expectedError: true},
+		{name: "Gagal - Opsi dipilih dua kali", optionIDs: []uuid.UUID{large.ID, large.ID}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modifiers, err := core.SelectModifiers(groups, tc.optionIDs)
+			if tc.expectedError {
+				assert.ErrorIs(t, err, core.ErrInvalidModifier)
+				return
+			}
+
+			assert.NoError(t, err)
+			var names []string
+			for _, m := range modifiers {
+				names = append(names, m.OptionName)
+			}
+			assert.Equal(t, tc.expectedNames, names)
+
+			item := core.OrderItem{Modifiers: modifiers}
+			assert.Equal(t, tc.expectedDelta, item.ModifierDelta())
+		})
+	}
+}
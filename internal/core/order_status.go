@@ -0,0 +1,29 @@
+package core
+
+// orderStatusTransitions adalah state machine siklus hidup pesanan.
+// Alur normal: PENDING → CONFIRMED → PREPARING → READY → COMPLETED.
+// CANCELLED hanya bisa dicapai sebelum pesanan siap diserahkan ke pelanggan.
+var orderStatusTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPreparing, OrderStatusCancelled},
+	OrderStatusPreparing: {OrderStatusReady, OrderStatusCancelled},
+	OrderStatusReady:     {OrderStatusCompleted},
+	OrderStatusCompleted: {},
+	OrderStatusCancelled: {},
+}
+
+// CanTransitionOrderStatus mengecek apakah perpindahan status from → to diizinkan state machine.
+func CanTransitionOrderStatus(from, to string) bool {
+	for _, next := range orderStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinalOrderStatus mengembalikan true jika status tidak bisa berpindah lagi (COMPLETED / CANCELLED).
+func IsFinalOrderStatus(status string) bool {
+	next, ok := orderStatusTransitions[status]
+	return ok && len(next) == 0
+}
@@ -0,0 +1,42 @@
+package core_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransitionOrderStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		expected bool
+	}{
+		{name: "Sukses - PENDING ke CONFIRMED", from: core.OrderStatusPending, to: core.OrderStatusConfirmed, expected: true},
+		{name: "Sukses - CONFIRMED ke PREPARING", from: core.OrderStatusConfirmed, to: core.OrderStatusPreparing, expected: true},
+		{name: "Sukses - PREPARING ke READY", from: core.OrderStatusPreparing, to: core.OrderStatusReady, expected: true},
+		{name: "Sukses - READY ke COMPLETED", from: core.OrderStatusReady, to: core.OrderStatusCompleted, expected: true},
+		{name: "Sukses - PENDING ke CANCELLED", from: core.OrderStatusPending, to: core.OrderStatusCancelled, expected: true},
+		{name: "Gagal - Lompat PENDING ke READY", from: core.OrderStatusPending, to: core.OrderStatusReady, expected: false},
+		{name: "Gagal - Mundur READY ke PREPARING", from: core.OrderStatusReady, to: core.OrderStatusPreparing, expected: false},
+		{name: "Gagal - READY ke CANCELLED", from: core.OrderStatusReady, to: core.OrderStatusCancelled, expected: false},
+		{name: "Gagal - COMPLETED adalah status final", from: core.OrderStatusCompleted, to: core.OrderStatusCancelled, expected: false},
+		{name: "Gagal - Status tidak dikenal", from: "UNKNOWN", to: core.OrderStatusConfirmed, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, core.CanTransitionOrderStatus(tc.from, tc.to))
+		})
+	}
+}
+
+func TestIsFinalOrderStatus(t *testing.T) {
+	assert.True(t, core.IsFinalOrderStatus(core.OrderStatusCompleted))
+	assert.True(t, core.IsFinalOrderStatus(core.OrderStatusCancelled))
+	assert.False(t, core.IsFinalOrderStatus(core.OrderStatusPending))
+	assert.False(t, core.IsFinalOrderStatus("UNKNOWN"))
+}
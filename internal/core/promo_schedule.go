@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format tanggal & jam yang dipakai PromoSchedule (selalu di zona waktu toko).
+const (
+	PromoDateLayout = "2006-01-02"
+	PromoTimeLayout = "15:04"
+)
+
+// ActiveAt mengecek apakah jadwal promo berlaku pada waktu t. t harus sudah dalam zona waktu toko
+// (StoreProfile.Location). Jam mulai inklusif, jam selesai eksklusif.
+//
+// Jendela melewati tengah malam (StartTime > EndTime, contoh 22:00–02:00) dihitung sebagai milik
+// hari saat jendela dimulai: Jumat 01:00 termasuk jendela Kamis malam, sehingga hari & rentang
+// tanggal dicek terhadap hari sebelumnya.
+func (s PromoSchedule) ActiveAt(t time.Time) bool {
+	current := fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute())
+	day := t
+
+	if s.StartTime != "" && s.EndTime != "" && s.StartTime != s.EndTime {
+		switch {
+		case s.StartTime < s.EndTime:
+			if current < s.StartTime || current >= s.EndTime {
+				return false
+			}
+		case current >= s.StartTime:
+			// Bagian sebelum tengah malam: hari ini
+		case current < s.EndTime:
+			day = t.AddDate(0, 0, -1)
+		default:
+			return false
+		}
+	}
+
+	date := day.Format(PromoDateLayout)
+	if s.StartDate != "" && date < s.StartDate {
+		return false
+	}
+	if s.EndDate != "" && date > s.EndDate {
+		return false
+	}
+
+	weekdays := s.WeekdayList()
+	if len(weekdays) == 0 {
+		return true
+	}
+	for _, wd := range weekdays {
+		if wd == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// WeekdayList mengurai kolom Weekdays ("1,2,3,4,5"; 0 = Minggu). Kosong = setiap hari.
+func (s PromoSchedule) WeekdayList() []time.Weekday {
+	var weekdays []time.Weekday
+	for _, raw := range strings.Split(s.Weekdays, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || n < 0 || n > 6 {
+			continue
+		}
+		weekdays = append(weekdays, time.Weekday(n))
+	}
+	return weekdays
+}
+
+// PromoActiveAt mengecek apakah harga promo produk berlaku pada waktu t (zona waktu toko).
+// Produk dengan PromoSchedules memakai jadwal tersebut (cukup salah satu yang berlaku);
+// produk lama tanpa jadwal memakai PromoStartTime–PromoEndTime sebagai jendela harian.
+func (p *Product) PromoActiveAt(t time.Time) bool {
+	if !p.IsPromoActive || p.PromoPrice <= 0 {
+		return false
+	}
+	if len(p.PromoSchedules) > 0 {
+		for _, schedule := range p.PromoSchedules {
+			if schedule.ActiveAt(t) {
+				return true
+			}
+		}
+		return false
+	}
+	if p.PromoStartTime == "" || p.PromoEndTime == "" {
+		return false
+	}
+	return PromoSchedule{StartTime: p.PromoStartTime, EndTime: p.PromoEndTime}.ActiveAt(t)
+}
+
+// PriceAt mengembalikan harga jual produk pada waktu t: PromoPrice jika promo berlaku, selain itu NormalPrice.
+func (p *Product) PriceAt(t time.Time) int {
+	if p.PromoActiveAt(t) {
+		return p.PromoPrice
+	}
+	return p.NormalPrice
+}
@@ -0,0 +1,66 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoScheduleActiveAt(t *testing.T) {
+	// 2 Januari 2026 adalah hari Jumat
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.Local)
+	}
+	lateNight := core.PromoSchedule{StartTime: "22:00", EndTime: "02:00"}
+	fridayNight := core.PromoSchedule{Weekdays: "5", StartTime: "22:00", EndTime: "02:00"}
+	weekdayLunch := core.PromoSchedule{Weekdays: "1,2,3,4,5", StartTime: "11:00", EndTime: "14:00"}
+	january := core.PromoSchedule{StartDate: "2026-01-01", EndDate: "2026-01-02"}
+
+	testCases := []struct {
+		name     string
+		schedule core.PromoSchedule
+		t        time.Time
+		expected bool
+	}{
+		{name: "Sukses - Malam sebelum tengah malam", schedule: lateNight, t: at(2, 23, 30), expected: true},
+		{name: "Sukses - Malam setelah tengah malam", schedule: lateNight, t: at(3, 1, 59), expected: true},
+		{name: "Sukses - Jam selesai eksklusif", schedule: lateNight, t: at(3, 2, 0), expected: false},
+		{name: "Sukses - Di luar jendela malam", schedule: lateNight, t: at(2, 21, 59), expected: false},
+		{name: "Sukses - Lewat tengah malam ikut hari mulai", schedule: fridayNight, t: at(3, 1, 0), expected: true},
+		{name: "Sukses - Hari berikutnya bukan hari promo", schedule: fridayNight, t: at(3, 23, 0), expected: false},
+		{name: "Sukses - Hari kerja", schedule: weekdayLunch, t: at(2, 12, 0), expected: true},
+		{name: "Sukses - Akhir pekan", schedule: weekdayLunch, t: at(3, 12, 0), expected: false},
+		{name: "Sukses - Dalam rentang tanggal", schedule: january, t: at(2, 23, 59), expected: true},
+		{name: "Sukses - Setelah rentang tanggal", schedule: january, t: at(3, 0, 0), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.schedule.ActiveAt(tc.t))
+		})
+	}
+}
+
+func TestProductPriceAt(t *testing.T) {
+	noon := time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name     string
+		product  core.Product
+		expected int
+	}{
+		{name: "Sukses - Promo jadwal berlaku", product: core.Product{NormalPrice: 20000, PromoPrice: 15000, IsPromoActive: true, PromoSchedules: []core.PromoSchedule{{StartTime: "11:00", EndTime: "14:00"}}}, expected: 15000},
+		{name: "Sukses - Jadwal menggantikan jam lama", product: core.Product{NormalPrice: 20000, PromoPrice: 15000, IsPromoActive: true, PromoStartTime: "11:00", PromoEndTime: "14:00", PromoSchedules: []core.PromoSchedule{{StartTime: "18:00", EndTime: "20:00"}}}, expected: 20000},
+		{name: "Sukses - Jam lama tanpa jadwal", product: core.Product{NormalPrice: 20000, PromoPrice: 15000, IsPromoActive: true, PromoStartTime: "11:00", PromoEndTime: "14:00"}, expected: 15000},
+		{name: "Sukses - Promo nonaktif", product: core.Product{NormalPrice: 20000, PromoPrice: 15000, PromoSchedules: []core.PromoSchedule{{}}}, expected: 20000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.product.PriceAt(noon))
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PromotionLine adalah satu baris keranjang yang dievaluasi ApplyPromotions.
+type PromotionLine struct {
+	ProductID  uuid.UUID
+	CategoryID uuid.UUID
+	UnitPrice  int
+	Qty        int
+}
+
+// PromotionDiscount adalah diskon promo untuk satu baris (urutan sama dengan input).
+// PromotionID nil berarti baris tidak mendapat diskon promo.
+type PromotionDiscount struct {
+	PromotionID   *uuid.UUID
+	PromotionName string
+	Amount        int
+}
+
+// ActiveAt mengecek apakah promo aktif dan jadwalnya berlaku pada waktu t (zona waktu toko).
+func (p Promotion) ActiveAt(t time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	return PromoSchedule{
+		Weekdays:  p.Weekdays,
+		StartTime: p.StartTime,
+		EndTime:   p.EndTime,
+		StartDate: p.StartDate,
+		EndDate:   p.EndDate,
+	}.ActiveAt(t)
+}
+
+// Targets mengecek apakah baris termasuk produk/kategori sasaran promo.
+func (p Promotion) Targets(line PromotionLine) bool {
+	if p.ProductID != nil && *p.ProductID != line.ProductID {
+		return false
+	}
+	if p.CategoryID != nil && *p.CategoryID != line.CategoryID {
+		return false
+	}
+	return true
+}
+
+// rewardPercent adalah persen diskon unit hadiah; 0 berarti gratis.
+func (p Promotion) rewardPercent() int {
+	if p.DiscountPercent <= 0 || p.DiscountPercent > 100 {
+		return 100
+	}
+	return p.DiscountPercent
+}
+
+// ApplyPromotions menghitung diskon promo otomatis per baris. Aturan prioritas & stacking:
+//   - promo yang aktif pada waktu at dievaluasi urut Priority (lalu nama), lebih kecil lebih dulu;
+//   - satu baris hanya bisa dipakai satu promo: baris yang sudah ikut dihitung sebuah promo
+//     (sebagai syarat maupun hadiah) tidak dievaluasi lagi oleh promo berikutnya;
+//   - jika hasVoucher, promo dengan StackWithVoucher = false dilewati: voucher yang dimasukkan
+//     pelanggan selalu dipakai dan dihitung atas subtotal setelah diskon promo;
+//   - syarat SPEND_GET_ITEM memakai subtotal keranjang sebelum diskon apa pun.
+//
+// Diskon persen dibulatkan ke bawah per baris dan tidak pernah melebihi subtotal baris.
+func ApplyPromotions(promotions []Promotion, lines []PromotionLine, at time.Time, hasVoucher bool) []PromotionDiscount {
+	result := make([]PromotionDiscount, len(lines))
+	claimed := make([]bool, len(lines))
+
+	cartSubtotal := 0
+	for _, line := range lines {
+		cartSubtotal += line.UnitPrice * line.Qty
+	}
+
+	ordered := make([]Promotion, 0, len(promotions))
+	for _, promo := range promotions {
+		if promo.ActiveAt(at) && (promo.StackWithVoucher || !hasVoucher) {
+			ordered = append(ordered, promo)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+
+	for _, promo := range ordered {
+		var eligible []int
+		for i, line := range lines {
+			if !claimed[i] && line.Qty > 0 && promo.Targets(line) {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		discounts := make(map[int]int)
+		var used []int
+		switch promo.Type {
+		case PromotionBuyXGetY:
+			used, discounts = buyXGetY(promo, lines, eligible)
+		case PromotionPercentOff:
+			units := 0
+			for _, i := range eligible {
+				units += lines[i].Qty
+			}
+			if units < promo.MinQty || promo.DiscountPercent <= 0 {
+				continue
+			}
+			used = eligible
+			for _, i := range eligible {
+				discounts[i] = lines[i].UnitPrice * lines[i].Qty * min(promo.DiscountPercent, 100) / 100
+			}
+		case PromotionTiered:
+			subtotal := 0
+			for _, i := range eligible {
+				subtotal += lines[i].UnitPrice * lines[i].Qty
+			}
+			percent := 0
+			for _, tier := range promo.Tiers {
+				if subtotal >= tier.MinSpend && tier.DiscountPercent > percent {
+					percent = min(tier.DiscountPercent, 100)
+				}
+			}
+			if percent == 0 {
+				continue
+			}
+			used = eligible
+			for _, i := range eligible {
+				discounts[i] = lines[i].UnitPrice * lines[i].Qty * percent / 100
+			}
+		case PromotionSpendGetItem:
+			used, discounts = spendGetItem(promo, lines, eligible, cartSubtotal)
+		}
+		if len(used) == 0 {
+			continue
+		}
+
+		promoID := promo.ID
+		for _, i := range used {
+			claimed[i] = true
+			amount := min(discounts[i], lines[i].UnitPrice*lines[i].Qty)
+			if amount <= 0 {
+				continue
+			}
+			result[i] = PromotionDiscount{PromotionID: &promoID, PromotionName: promo.Name, Amount: amount}
+		}
+	}
+	return result
+}
+
+// buyXGetY: setiap kelompok BuyQty+GetQty unit, GetQty unit termurah mendapat diskon hadiah.
+// Semua baris sasaran dianggap terpakai jika minimal satu kelompok terbentuk.
+func buyXGetY(promo Promotion, lines []PromotionLine, eligible []int) ([]int, map[int]int) {
+	group := promo.BuyQty + promo.GetQty
+	if promo.BuyQty <= 0 || promo.GetQty <= 0 {
+		return nil, nil
+	}
+
+	type unit struct {
+		line  int
+		price int
+	}
+	var units []unit
+	for _, i := range eligible {
+		for q := 0; q < lines[i].Qty; q++ {
+			units = append(units, unit{line: i, price: lines[i].UnitPrice})
+		}
+	}
+	free := len(units) / group * promo.GetQty
+	if free == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].price < units[j].price
+	})
+
+	discounts := make(map[int]int)
+	for _, u := range units[:free] {
+		discounts[u.line] += u.price * promo.rewardPercent() / 100
+	}
+	return eligible, discounts
+}
+
+// spendGetItem: jika subtotal keranjang >= MinSpend, GetQty unit RewardProduct (default 1) yang ada
+// di keranjang mendapat diskon hadiah. Hanya baris hadiah yang dianggap terpakai.
+func spendGetItem(promo Promotion, lines []PromotionLine, eligible []int, cartSubtotal int) ([]int, map[int]int) {
+	if promo.RewardProductID == nil || cartSubtotal < promo.MinSpend {
+		return nil, nil
+	}
+	remaining := max(promo.GetQty, 1)
+
+	var rewards []int
+	for _, i := range eligible {
+		if lines[i].ProductID == *promo.RewardProductID {
+			rewards = append(rewards, i)
+		}
+	}
+	sort.SliceStable(rewards, func(a, b int) bool {
+		return lines[rewards[a]].UnitPrice < lines[rewards[b]].UnitPrice
+	})
+
+	var used []int
+	discounts := make(map[int]int)
+	for _, i := range rewards {
+		if remaining == 0 {
+			break
+		}
+		qty := min(lines[i].Qty, remaining)
+		remaining -= qty
+		discounts[i] = lines[i].UnitPrice * qty * promo.rewardPercent() / 100
+		used = append(used, i)
+	}
+	return used, discounts
+}
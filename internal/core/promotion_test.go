@@ -0,0 +1,121 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPromotions(t *testing.T) {
+	// 2 Januari 2026 adalah hari Jumat
+	evening := time.Date(2026, 1, 2, 20, 30, 0, 0, time.Local)
+	noon := time.Date(2026, 1, 2, 12, 0, 0, 0, time.Local)
+
+	pastry := uuid.New()
+	drinks := uuid.New()
+	croissant := uuid.New()
+	danish := uuid.New()
+	coffee := uuid.New()
+	cookie := uuid.New()
+
+	buy2get1 := core.Promotion{ID: uuid.New(), Name: "Beli 2 Gratis 1", Type: core.PromotionBuyXGetY, IsActive: true, CategoryID: &pastry, BuyQty: 2, GetQty: 1}
+	pastryNight := core.Promotion{ID: uuid.New(), Name: "Pastry Malam", Type: core.PromotionPercentOff, Priority: 1, IsActive: true, CategoryID: &pastry, DiscountPercent: 10, StartTime: "20:00", EndTime: "23:00"}
+	freeCookie := core.Promotion{ID: uuid.New(), Name: "Gratis Cookie", Type: core.PromotionSpendGetItem, IsActive: true, StackWithVoucher: true, MinSpend: 100000, RewardProductID: &cookie}
+	tieredDrinks := core.Promotion{ID: uuid.New(), Name: "Minuman Bertingkat", Type: core.PromotionTiered, IsActive: true, CategoryID: &drinks, Tiers: []core.PromotionTier{{MinSpend: 50000, DiscountPercent: 5}, {MinSpend: 100000, DiscountPercent: 15}}}
+
+	testCases := []struct {
+		name       string
+		promotions []core.Promotion
+		lines      []core.PromotionLine
+		at         time.Time
+		hasVoucher bool
+		expected   []int
+	}{
+		{
+			name:       "Sukses - Beli 2 gratis 1 unit termurah",
+			promotions: []core.Promotion{buy2get1},
+			lines:      []core.PromotionLine{{ProductID: croissant, CategoryID: pastry, UnitPrice: 25000, Qty: 2}, {ProductID: danish, CategoryID: pastry, UnitPrice: 20000, Qty: 1}},
+			at:         noon,
+			expected:   []int{0, 20000},
+		},
+		{
+			name:       "Sukses - Beli 2 gratis 1 belum cukup unit",
+			promotions: []core.Promotion{buy2get1},
+			lines:      []core.PromotionLine{{ProductID: croissant, CategoryID: pastry, UnitPrice: 25000, Qty: 2}},
+			at:         noon,
+			expected:   []int{0},
+		},
+		{
+			name:       "Sukses - Persen kategori sesuai jadwal",
+			promotions: []core.Promotion{pastryNight},
+			lines:      []core.PromotionLine{{ProductID: croissant, CategoryID: pastry, UnitPrice: 25000, Qty: 2}, {ProductID: coffee, CategoryID: drinks, UnitPrice: 30000, Qty: 1}},
+			at:         evening,
+			expected:   []int{5000, 0},
+		},
+		{
+			name:       "Sukses - Persen kategori di luar jadwal",
+			promotions: []core.Promotion{pastryNight},
+			lines:      []core.PromotionLine{{ProductID: croissant, CategoryID: pastry, UnitPrice: 25000, Qty: 2}},
+			at:         noon,
+			expected:   []int{0},
+		},
+		{
+			name:       "Sukses - Prioritas lebih kecil mengklaim baris",
+			promotions: []core.Promotion{pastryNight, buy2get1},
+			lines:      []core.PromotionLine{{ProductID: croissant, CategoryID: pastry, UnitPrice: 25000, Qty: 3}},
+			at:         evening,
+			expected:   []int{25000},
+		},
+		{
+			name:       "Sukses - Belanja minimal dapat cookie gratis",
+			promotions: []core.Promotion{freeCookie},
+			lines:      []core.PromotionLine{{ProductID: coffee, CategoryID: drinks, UnitPrice: 30000, Qty: 3}, {ProductID: cookie, CategoryID: pastry, UnitPrice: 12000, Qty: 2}},
+			at:         noon,
+			expected:   []int{0, 12000},
+		},
+		{
+			name:       "Sukses - Belanja kurang dari minimal",
+			promotions: []core.Promotion{freeCookie},
+			lines:      []core.PromotionLine{{ProductID: coffee, CategoryID: drinks, UnitPrice: 30000, Qty: 2}, {ProductID: cookie, CategoryID: pastry, UnitPrice: 12000, Qty: 1}},
+			at:         noon,
+			expected:   []int{0, 0},
+		},
+		{
+			name:       "Sukses - Diskon bertingkat memakai tier tertinggi",
+			promotions: []core.Promotion{tieredDrinks},
+			lines:      []core.PromotionLine{{ProductID: coffee, CategoryID: drinks, UnitPrice: 30000, Qty: 4}},
+			at:         noon,
+			expected:   []int{18000},
+		},
+		{
+			name:       "Sukses - Promo tanpa stacking dilewati saat ada voucher",
+			promotions: []core.Promotion{buy2get1, freeCookie},
+			lines:      []core.PromotionLine{{ProductID: croissant, CategoryID: pastry, UnitPrice: 25000, Qty: 4}, {ProductID: cookie, UnitPrice: 12000, Qty: 1}},
+			at:         noon,
+			hasVoucher: true,
+			expected:   []int{0, 12000},
+		},
+		{
+			name:       "Sukses - Promo nonaktif diabaikan",
+			promotions: []core.Promotion{{Name: "Nonaktif", Type: core.PromotionPercentOff, DiscountPercent: 50}},
+			lines:      []core.PromotionLine{{ProductID: coffee, CategoryID: drinks, UnitPrice: 30000, Qty: 1}},
+			at:         noon,
+			expected:   []int{0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := core.ApplyPromotions(tc.promotions, tc.lines, tc.at, tc.hasVoucher)
+			amounts := make([]int, len(result))
+			for i, d := range result {
+				amounts[i] = d.Amount
+			}
+			assert.Equal(t, tc.expected, amounts)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kebijakan reset nomor antrean (StoreProfile.QueueResetPolicy).
+const (
+	QueueResetDaily = "DAILY" // Kembali ke 1 setiap hari bisnis (lihat BusinessDayCutoffHour)
+	QueueResetShift = "SHIFT" // Kembali ke 1 di setiap jam mulai shift (QueueShiftStarts)
+	QueueResetNever = "NEVER" // Terus bertambah tanpa reset
+)
+
+// Nilai default skema nomor antrean, dipakai jika kolom StoreProfile masih kosong (0 / "").
+const (
+	DefaultQueuePrefixCashier = "K"
+	DefaultQueuePrefixEMenu   = "E"
+	DefaultQueueNumberWidth   = 3
+)
+
+// QueuePrefix mengembalikan prefix nomor antrean untuk sebuah order source.
+func (p *StoreProfile) QueuePrefix(source string) string {
+	if source == OrderSourceEMenu {
+		if p.QueuePrefixEMenu == "" {
+			return DefaultQueuePrefixEMenu
+		}
+		return p.QueuePrefixEMenu
+	}
+	if p.QueuePrefixCashier == "" {
+		return DefaultQueuePrefixCashier
+	}
+	return p.QueuePrefixCashier
+}
+
+// FormatQueueNumber menyusun nomor antrean, contoh: ("CASHIER", 7) → "K-007".
+func (p *StoreProfile) FormatQueueNumber(source string, count int) string {
+	width := p.QueueNumberWidth
+	if width <= 0 {
+		width = DefaultQueueNumberWidth
+	}
+	return fmt.Sprintf("%s-%0*d", p.QueuePrefix(source), width, count)
+}
+
+// BusinessDate mengembalikan tanggal hari bisnis untuk waktu t. Dengan cutoff 04:00,
+// order pukul 01:30 masih dihitung sebagai hari bisnis sebelumnya.
+func (p *StoreProfile) BusinessDate(t time.Time) time.Time {
+	shifted := t.Add(-time.Duration(p.BusinessDayCutoffHour) * time.Hour)
+	return time.Date(shifted.Year(), shifted.Month(), shifted.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// QueuePeriod mengembalikan kunci periode counter antrean untuk waktu t (zona waktu t).
+// Nomor antrean kembali ke 1 setiap kali kunci ini berubah:
+//   - DAILY: "20260221" (tanggal hari bisnis)
+//   - SHIFT: "20260221-2" (tanggal mulai shift + urutan shift); shift sebelum jam mulai
+//     shift pertama adalah lanjutan shift terakhir hari sebelumnya
+//   - NEVER: "ALL"
+func (p *StoreProfile) QueuePeriod(t time.Time) string {
+	switch p.QueueResetPolicy {
+	case QueueResetNever:
+		return "ALL"
+	case QueueResetShift:
+		starts := ParseShiftStarts(p.QueueShiftStarts)
+		if len(starts) == 0 {
+			break
+		}
+		current := fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute())
+		for i := len(starts) - 1; i >= 0; i-- {
+			if current >= starts[i] {
+				return fmt.Sprintf("%s-%d", t.Format("20060102"), i+1)
+			}
+		}
+		return fmt.Sprintf("%s-%d", t.AddDate(0, 0, -1).Format("20060102"), len(starts))
+	}
+	return p.BusinessDate(t).Format("20060102")
+}
+
+// ParseShiftStarts mengubah daftar jam mulai shift "HH:MM,HH:MM" menjadi slice terurut.
+func ParseShiftStarts(value string) []string {
+	var starts []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			starts = append(starts, s)
+		}
+	}
+	sort.Strings(starts)
+	return starts
+}
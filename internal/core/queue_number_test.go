@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreProfileFormatQueueNumber(t *testing.T) {
+	testCases := []struct {
+		name     string
+		profile  core.StoreProfile
+		source   string
+		count    int
+		expected string
+	}{
+		{name: "Sukses - Default kasir", source: core.OrderSourceCashier, count: 7, expected: "K-007"},
+		{name: "Sukses - Default E-Menu", source: core.OrderSourceEMenu, count: 12, expected: "E-012"},
+		{name: "Sukses - Prefix dan lebar kustom", profile: core.StoreProfile{QueuePrefixCashier: "A", QueueNumberWidth: 4}, source: core.OrderSourceCashier, count: 5, expected: "A-0005"},
+		{name: "Sukses - Melebihi lebar digit", profile: core.StoreProfile{QueueNumberWidth: 2}, source: core.OrderSourceEMenu, count: 123, expected: "E-123"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.profile.FormatQueueNumber(tc.source, tc.count))
+		})
+	}
+}
+
+func TestStoreProfileQueuePeriod(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.Local)
+	}
+	shifts := "14:00,06:00,22:00"
+
+	testCases := []struct {
+		name     string
+		profile  core.StoreProfile
+		at       time.Time
+		expected string
+	}{
+		{name: "Sukses - Harian tanpa cutoff", at: at(5, 1, 30), expected: "20260105"},
+		{name: "Sukses - Cutoff 04:00 dini hari masuk hari sebelumnya", profile: core.StoreProfile{BusinessDayCutoffHour: 4}, at: at(5, 1, 30), expected: "20260104"},
+		{name: "Sukses - Cutoff 04:00 tepat jam cutoff", profile: core.StoreProfile{BusinessDayCutoffHour: 4}, at: at(5, 4, 0), expected: "20260105"},
+		{name: "Sukses - Shift pertama", profile: core.StoreProfile{QueueResetPolicy: core.QueueResetShift, QueueShiftStarts: shifts}, at: at(5, 6, 0), expected: "20260105-1"},
+		{name: "Sukses - Shift kedua", profile: core.StoreProfile{QueueResetPolicy: core.QueueResetShift, QueueShiftStarts: shifts}, at: at(5, 15, 0), expected: "20260105-2"},
+		{name: "Sukses - Shift malam lewat tengah malam", profile: core.StoreProfile{QueueResetPolicy: core.QueueResetShift, QueueShiftStarts: shifts}, at: at(6, 2, 0), expected: "20260105-3"},
+		{name: "Sukses - Shift tanpa jam mulai kembali ke harian", profile: core.StoreProfile{QueueResetPolicy: core.QueueResetShift}, at: at(5, 9, 0), expected: "20260105"},
+		{name: "Sukses - Tanpa reset", profile: core.StoreProfile{QueueResetPolicy: core.QueueResetNever}, at: at(5, 9, 0), expected: "ALL"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.profile.QueuePeriod(tc.at))
+		})
+	}
+}
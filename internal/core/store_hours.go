@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultPreOrderLead dipakai jika PreOrderLeadMinutes belum diatur (0).
+const DefaultPreOrderLead = 30 * time.Minute
+
+// IsOpenAt mengecek apakah waktu t (jam & menit, zona waktu t) berada di dalam jam operasional toko.
+// Jam buka inklusif, jam tutup eksklusif. Mendukung jam operasional melewati tengah malam.
+func (p *StoreProfile) IsOpenAt(t time.Time) bool {
+	if p.OpeningTime == "" || p.ClosingTime == "" || p.OpeningTime == p.ClosingTime {
+		return true
+	}
+
+	current := fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute())
+	if p.OpeningTime < p.ClosingTime {
+		return current >= p.OpeningTime && current < p.ClosingTime
+	}
+	// Contoh 18:00–02:00: buka jika >= 18:00 ATAU < 02:00
+	return current >= p.OpeningTime || current < p.ClosingTime
+}
+
+// UnpaidOrderTTL mengembalikan batas umur order yang belum dibayar sebelum dibatalkan otomatis.
+// Nilai 0 berarti fitur kedaluwarsa dinonaktifkan.
+func (p *StoreProfile) UnpaidOrderTTL() time.Duration {
+	if p.UnpaidOrderTTLMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(p.UnpaidOrderTTLMinutes) * time.Minute
+}
+
+// PreOrderLeadTime mengembalikan berapa lama sebelum waktu ambil sebuah pre-order dikirim ke dapur.
+func (p *StoreProfile) PreOrderLeadTime() time.Duration {
+	if p.PreOrderLeadMinutes <= 0 {
+		return DefaultPreOrderLead
+	}
+	return time.Duration(p.PreOrderLeadMinutes) * time.Minute
+}
+
+// Location mengembalikan zona waktu toko untuk jam operasional, hari bisnis, dan jadwal promo.
+// Kosong/tidak dikenal = zona waktu server.
+func (p *StoreProfile) Location() *time.Location {
+	if p.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
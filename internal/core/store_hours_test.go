@@ -0,0 +1,45 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreProfileIsOpenAt(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 5, hour, minute, 0, 0, time.Local)
+	}
+
+	testCases := []struct {
+		name     string
+		opening  string
+		closing  string
+		at       time.Time
+		expected bool
+	}{
+		{name: "Sukses - Jam operasional belum diatur", at: at(3, 0), expected: true},
+		{name: "Sukses - Di dalam jam buka", opening: "08:00", closing: "21:00", at: at(12, 30), expected: true},
+		{name: "Sukses - Tepat jam buka", opening: "08:00", closing: "21:00", at: at(8, 0), expected: true},
+		{name: "Gagal - Tepat jam tutup", opening: "08:00", closing: "21:00", at: at(21, 0), expected: false},
+		{name: "Gagal - Sebelum buka", opening: "08:00", closing: "21:00", at: at(7, 59), expected: false},
+		{name: "Sukses - Lewat tengah malam (malam)", opening: "18:00", closing: "02:00", at: at(23, 0), expected: true},
+		{name: "Sukses - Lewat tengah malam (dini hari)", opening: "18:00", closing: "02:00", at: at(1, 30), expected: true},
+		{name: "Gagal - Lewat tengah malam (siang)", opening: "18:00", closing: "02:00", at: at(12, 0), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := &core.StoreProfile{OpeningTime: tc.opening, ClosingTime: tc.closing}
+			assert.Equal(t, tc.expected, profile.IsOpenAt(tc.at))
+		})
+	}
+}
+
+func TestStoreProfilePreOrderLeadTime(t *testing.T) {
+	assert.Equal(t, core.DefaultPreOrderLead, (&core.StoreProfile{}).PreOrderLeadTime())
+	assert.Equal(t, 45*time.Minute, (&core.StoreProfile{PreOrderLeadMinutes: 45}).PreOrderLeadTime())
+}
@@ -27,12 +27,13 @@ func NewMidtransAdapter() *MidtransAdapter {
 	}
 }
 
-// CreatePaymentLink membuat link pembayaran baru di Midtrans Snap.
+// CreatePaymentLink membuat link pembayaran baru di Midtrans Snap untuk SATU payment.
+// Midtrans order_id memakai payment.ID (bukan order.ID) karena satu order bisa memiliki banyak payment.
 // Mengembalikan: snap_url (untuk redirect user), transaction_id (sebagai idempotency key), error.
 //
 // Untuk production, integrasikan dengan SDK: github.com/midtrans/midtrans-go
 // Implementasi di bawah adalah mock yang sudah menerapkan interface yang benar.
-func (m *MidtransAdapter) CreatePaymentLink(order *core.Order) (paymentURL string, transactionID string, err error) {
+func (m *MidtransAdapter) CreatePaymentLink(order *core.Order, payment *core.Payment) (paymentURL string, transactionID string, err error) {
 	// --- MOCK IMPLEMENTATION ---
 	// Dalam production, gantikan blok ini dengan pemanggilan Midtrans Snap API:
 	//
@@ -40,18 +41,55 @@ func (m *MidtransAdapter) CreatePaymentLink(order *core.Order) (paymentURL strin
 	//   snapClient.New(m.ServerKey, midtrans.Sandbox)
 	//   req := &snap.Request{
 	//       TransactionDetails: midtrans.TransactionDetails{
-	//           OrderID:  order.ID.String(),
-	//           GrossAmt: int64(order.TotalFinalAmount),
+	//           OrderID:  payment.ID.String(),
+	//           GrossAmt: int64(payment.AmountCharged()), // Sudah termasuk pembulatan
 	//       },
 	//   }
 	//   snapResp, err := snapClient.CreateTransaction(req)
 	//   return snapResp.RedirectURL, snapResp.Token, err
 
-	mockTransactionID := fmt.Sprintf("MOCK-MIDTRANS-%s", order.ID.String())
+	mockTransactionID := fmt.Sprintf("MOCK-MIDTRANS-%s", payment.ID.String())
 	mockURL := fmt.Sprintf("https://app.sandbox.midtrans.com/snap/v2/vtweb/%s", mockTransactionID)
 	return mockURL, mockTransactionID, nil
 }
 
+// Refund mengembalikan dana transaksi yang sudah settle melalui Midtrans Refund API.
+// Mengembalikan: refund_key dari Midtrans untuk disimpan sebagai GatewayRefundID.
+func (m *MidtransAdapter) Refund(payment *core.Payment, amount int, reason string) (string, error) {
+	// --- MOCK IMPLEMENTATION ---
+	// Dalam production, gantikan blok ini dengan pemanggilan Midtrans Core API:
+	//
+	//   c := coreapi.Client{}
+	//   c.New(m.ServerKey, midtrans.Sandbox)
+	//   resp, err := c.RefundTransaction(payment.IdempotencyKey, &coreapi.RefundReq{
+	//       RefundKey: refundKey,
+	//       Amount:    int64(amount),
+	//       Reason:    reason,
+	//   })
+
+	if payment.MidtransTransactionID == nil {
+		return "", fmt.Errorf("payment %s tidak memiliki transaction id gateway", payment.ID)
+	}
+	return fmt.Sprintf("MOCK-REFUND-%s-%d", *payment.MidtransTransactionID, amount), nil
+}
+
+// CancelPaymentLink meng-expire transaksi Midtrans yang masih pending sehingga link pembayaran
+// tidak bisa dibayar lagi (dipakai saat kasir men-void payment).
+func (m *MidtransAdapter) CancelPaymentLink(payment *core.Payment) error {
+	// --- MOCK IMPLEMENTATION ---
+	// Dalam production, gantikan blok ini dengan pemanggilan Midtrans Core API:
+	//
+	//   c := coreapi.Client{}
+	//   c.New(m.ServerKey, midtrans.Sandbox)
+	//   _, err := c.ExpireTransaction(payment.IdempotencyKey)
+	//   return err
+
+	if payment.MidtransTransactionID == nil {
+		return fmt.Errorf("payment %s tidak memiliki transaction id gateway", payment.ID)
+	}
+	return nil
+}
+
 // VerifySignature memvalidasi bahwa webhook benar-benar dikirim oleh Midtrans.
 // SHA512(order_id + status_code + gross_amount + server_key) == signature_key
 func (m *MidtransAdapter) VerifySignature(payload payment.WebhookPayload) bool {
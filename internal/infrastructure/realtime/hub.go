@@ -0,0 +1,59 @@
+package realtime
+
+import (
+	"sync"
+
+	"go-fiber-pos/internal/core"
+	"go-fiber-pos/pkg/logger"
+)
+
+// subscriberBuffer adalah kapasitas antrean per subscriber. Jika penuh (client lambat),
+// event dibuang untuk subscriber itu saja — publisher tidak boleh ikut terblokir.
+const subscriberBuffer = 64
+
+// Hub adalah ADAPTER in-memory untuk core.OrderEventPublisher & core.OrderEventSubscriber.
+// Cukup untuk single-instance; jika API di-scale horizontal, ganti dengan Redis Pub/Sub / NATS.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[chan core.OrderEvent]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan core.OrderEvent]struct{})}
+}
+
+// Publish mengirim event ke semua subscriber tanpa pernah memblokir pemanggil.
+func (h *Hub) Publish(event core.OrderEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			if logger.Log != nil {
+				logger.Log.Warnf("Subscriber realtime lambat, event %s untuk order %s dibuang", event.Type, event.OrderID)
+			}
+		}
+	}
+}
+
+// Subscribe mendaftarkan subscriber baru dan mengembalikan fungsi untuk berhenti berlangganan.
+func (h *Hub) Subscribe() (<-chan core.OrderEvent, func()) {
+	ch := make(chan core.OrderEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
@@ -1,6 +1,8 @@
 package order
 
 import (
+	"time"
+
 	"go-fiber-pos/internal/core"
 
 	"github.com/google/uuid"
@@ -13,22 +15,139 @@ type OrderRepository interface {
 	CreateWithTx(tx *gorm.DB, order *core.Order) error
 	// LockAndGetProduct mengambil product dengan FOR UPDATE pessimistic lock untuk mencegah race condition stok.
 	LockAndGetProduct(tx *gorm.DB, productID uuid.UUID) (*core.Product, error)
+	// FindProducts mengambil beberapa produk sekaligus tanpa lock (quote harga, baca biasa).
+	FindProducts(productIDs []uuid.UUID) (map[uuid.UUID]*core.Product, error)
 	// DeductStockWithTx memperbarui stok produk dalam transaksi yang sudah ada.
 	DeductStockWithTx(tx *gorm.DB, product *core.Product) error
+	// FindModifierGroups mengambil modifier group produk beserta opsinya (tanpa lock, baca biasa).
+	FindModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error)
+	// FindBundleSlots mengambil slot bundle beserta opsi & produknya (tanpa lock); produk biasa tidak punya slot.
+	FindBundleSlots(productID uuid.UUID) ([]core.BundleSlot, error)
+	// LockModifierOption mengambil opsi modifier dengan FOR UPDATE untuk memotong/mengembalikan stoknya.
+	LockModifierOption(tx *gorm.DB, optionID uuid.UUID) (*core.ModifierOption, error)
+	SaveModifierOptionWithTx(tx *gorm.DB, option *core.ModifierOption) error
+	// FindModifierOptions mengambil opsi modifier tanpa lock untuk mengecek stoknya pada quote harga.
+	FindModifierOptions(optionIDs []uuid.UUID) (map[uuid.UUID]*core.ModifierOption, error)
+	// CreateItemModifiersWithTx menyimpan snapshot modifier untuk item yang ditambahkan ke order yang sudah ada.
+	CreateItemModifiersWithTx(tx *gorm.DB, modifiers []core.OrderItemModifier) error
+	CreateItemComponentsWithTx(tx *gorm.DB, components []core.OrderItemComponent) error
 	// GetNextQueueNumber menggunakan DailyCounter + FOR UPDATE untuk generate nomor antrean atomic.
 	GetNextQueueNumber(tx *gorm.DB, source string) (string, error)
 	// FindByCode mencari voucher berdasarkan kode (tanpa lock, baca biasa).
 	FindVoucherByCode(code string) (*core.Voucher, error)
 	// GetStoreMarkupFee mengambil markup fee dari profil toko.
 	GetStoreMarkupFee() int
+	// GetStoreProfile mengambil profil toko (jam operasional & lead time pre-order).
+	GetStoreProfile() (*core.StoreProfile, error)
 	FindByID(id uuid.UUID) (*core.Order, error)
-	GetAll() ([]core.Order, error)
+	// FindByIdempotencyKey mencari order hasil checkout sebelumnya dengan Idempotency-Key yang sama.
+	FindByIdempotencyKey(key string) (*core.Order, error)
+	// FindByTrackingToken mencari order berdasarkan token lacak publik (bukan ID internal).
+	FindByTrackingToken(token string) (*core.Order, error)
+	// ListOpenOrders mengambil order yang belum COMPLETED/CANCELLED beserta item-nya (kitchen display).
+	// Pre-order yang belum dirilis tidak ikut.
+	ListOpenOrders(source string) ([]core.Order, error)
+	// ListScheduled mengambil antrean pre-order yang belum dirilis, urut waktu ambil terdekat.
+	ListScheduled() ([]OrderSummary, error)
+	// LockDueScheduledWithTx mengunci pre-order yang waktu rilisnya sudah tiba (FOR UPDATE SKIP LOCKED,
+	// aman jika job berjalan di beberapa instance sekaligus).
+	LockDueScheduledWithTx(tx *gorm.DB, releaseBefore time.Time, limit int) ([]core.Order, error)
+	MarkReleasedWithTx(tx *gorm.DB, orderIDs []uuid.UUID, releasedAt time.Time) error
+	// List mengambil proyeksi OrderSummary dengan filter + keyset pagination (created_at DESC, id DESC).
+	List(filter OrderListFilter) ([]OrderSummary, error)
+	// LockOrder mengambil order dengan FOR UPDATE agar perubahan status tidak saling menimpa.
+	LockOrder(tx *gorm.DB, id uuid.UUID) (*core.Order, error)
+	// UpdateOrderStatusWithTx memperbarui kolom order_status dalam transaksi yang sudah ada.
+	UpdateOrderStatusWithTx(tx *gorm.DB, orderID uuid.UUID, status string) error
+	// CreateStatusHistoryWithTx mencatat satu baris riwayat perubahan status.
+	CreateStatusHistoryWithTx(tx *gorm.DB, history *core.OrderStatusHistory) error
+	// FindItemsByOrderIDWithTx mengambil semua item order di dalam transaksi yang sedang berjalan.
+	FindItemsByOrderIDWithTx(tx *gorm.DB, orderID uuid.UUID) ([]core.OrderItem, error)
+	// CancelOrderWithTx menandai order CANCELLED beserta alasan dan waktu pembatalan.
+	CancelOrderWithTx(tx *gorm.DB, orderID uuid.UUID, reason string, cancelledAt time.Time) error
+	// CloseUnpaidPaymentsWithTx menutup semua payment UNPAID milik order dengan status FAILED (void) atau EXPIRED.
+	CloseUnpaidPaymentsWithTx(tx *gorm.DB, orderID uuid.UUID, status string) error
+	// FindExpiredUnpaidOrderIDs mencari kandidat order PENDING/UNPAID yang dibuat sebelum cutoff (tanpa lock).
+	FindExpiredUnpaidOrderIDs(cutoff time.Time, limit int) ([]uuid.UUID, error)
+	// CountUnpaidPaymentsWithTx menghitung payment UNPAID (link pembayaran yang masih aktif).
+	CountUnpaidPaymentsWithTx(tx *gorm.DB, orderID uuid.UUID) (int64, error)
+	// FindVoucherByID mengambil voucher yang sudah terpasang di order (tanpa cek masa berlaku).
+	FindVoucherByID(id uuid.UUID) (*core.Voucher, error)
+	// SaveItemWithTx meng-insert atau meng-update satu OrderItem.
+	SaveItemWithTx(tx *gorm.DB, item *core.OrderItem) error
+	DeleteItemWithTx(tx *gorm.DB, itemID uuid.UUID) error
+	// FindOpenTabByTableWithTx mencari tab yang masih terbuka pada sebuah meja.
+	FindOpenTabByTableWithTx(tx *gorm.DB, tableNumber string) (*core.Order, error)
+	// ListOpenTabs mengambil semua tab terbuka beserta item-nya, urut per nomor meja.
+	ListOpenTabs() ([]core.Order, error)
+	// CloseTabWithTx menutup tab sehingga order bisa dibayar.
+	CloseTabWithTx(tx *gorm.DB, orderID uuid.UUID, closedAt time.Time) error
+	// UpdateOrderTotalsWithTx menyimpan ulang VoucherID, TotalBasePrice, TotalDiscount (termasuk TotalPromoDiscount),
+	// PlatformFee, total service charge & pajak, TotalFinalAmount, serta mengganti semua baris Charges.
+	UpdateOrderTotalsWithTx(tx *gorm.DB, order *core.Order) error
+	// ListActiveChargeRules mengambil aturan service charge & pajak yang aktif beserta kategori yang dikecualikan.
+	ListActiveChargeRules() ([]core.ChargeRule, error)
+	FindProductCategories(productIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	// ListActivePromotions mengambil promo otomatis yang aktif beserta tingkatnya (jadwal dicek di core).
+	ListActivePromotions() ([]core.Promotion, error)
+	// FindPromoSchedules mengambil jadwal promo beberapa produk sekaligus (tanpa lock, baca biasa).
+	FindPromoSchedules(productIDs []uuid.UUID) (map[uuid.UUID][]core.PromoSchedule, error)
+	// MoveItemsWithTx memindahkan semua item dari satu order ke order lain (merge bill).
+	MoveItemsWithTx(tx *gorm.DB, fromOrderID, toOrderID uuid.UUID) error
+	// MarkMergedWithTx menutup order yang sudah digabung: CANCELLED tanpa restock, total nol, menunjuk order tujuan.
+	MarkMergedWithTx(tx *gorm.DB, orderID, mergedIntoID uuid.UUID, reason string, mergedAt time.Time) error
 	DB() *gorm.DB
 }
 
 // OrderService mendefinisikan kontrak business logic untuk Order.
 type OrderService interface {
+	// Checkout membuat order baru. Jika IdempotencyKey diisi, retry dengan body yang sama
+	// mengembalikan order yang sudah dibuat tanpa memotong stok lagi.
 	Checkout(req CheckoutRequest) (*core.Order, error)
-	GetAllOrders() ([]core.Order, error)
+	ListOrders(query OrderListQuery) (*OrderListResponse, error)
+	// QuoteCheckout & PublicQuote menghitung total keranjang dengan pipeline harga Checkout tanpa
+	// membuat order, mengunci baris, atau memotong stok.
+	QuoteCheckout(req CheckoutRequest) (*QuoteResponse, error)
+	PublicQuote(req PublicCheckoutRequest) (*QuoteResponse, error)
+	// PublicCheckout adalah checkout E-Menu: source dipaksa E_MENU dan meja diambil dari token QR.
+	PublicCheckout(req PublicCheckoutRequest) (*PublicOrderResponse, error)
+	// TrackOrder menampilkan progres order ke pelanggan berdasarkan tracking token.
+	TrackOrder(token string) (*PublicOrderResponse, error)
+	// GetKitchenOrders & GetKitchenOrder menyiapkan tampilan untuk kitchen display (SSE).
+	GetKitchenOrders(source string) ([]KitchenOrderResponse, error)
+	GetKitchenOrder(id uuid.UUID) (*KitchenOrderResponse, error)
+	// ListScheduledOrders menampilkan antrean pre-order yang akan datang.
+	ListScheduledOrders() ([]OrderSummary, error)
+	// ReleaseDueOrders mengirim pre-order yang sudah masuk lead time ke kitchen display (dipanggil job).
+	ReleaseDueOrders(now time.Time) (int, error)
+	// ExpireUnpaidOrders membatalkan order yang tidak dibayar melewati TTL toko dan mengembalikan stoknya (dipanggil job).
+	ExpireUnpaidOrders(now time.Time) (int, error)
+	// RenderReceipt merender struk pelanggan atau tiket dapur sesuai format yang diminta.
+	RenderReceipt(orderID uuid.UUID, query ReceiptQuery) (*RenderedReceipt, error)
+	// RenderPublicReceipt merender struk digital (HTML/PDF) untuk pelanggan berdasarkan tracking token.
+	RenderPublicReceipt(token string, query PublicReceiptQuery) (*RenderedReceipt, error)
+	// GenerateTableToken membuat token QR bertanda tangan untuk dicetak di meja.
+	GenerateTableToken(req TableTokenRequest) (string, error)
 	GetOrderByID(id uuid.UUID) (*core.Order, error)
+	// UpdateStatus memindahkan OrderStatus sesuai state machine dan mencatat riwayatnya.
+	UpdateStatus(id uuid.UUID, req UpdateOrderStatusRequest, changedBy *uuid.UUID) (*core.Order, error)
+	// CancelOrder membatalkan order UNPAID, mengembalikan stok, dan men-void payment yang belum dibayar.
+	CancelOrder(id uuid.UUID, req CancelOrderRequest, changedBy *uuid.UUID) (*core.Order, error)
+	// AddOrderItem, UpdateOrderItem, RemoveOrderItem mengubah isi order PENDING/UNPAID
+	// lalu menyesuaikan stok dan menghitung ulang harga, diskon voucher, dan total.
+	AddOrderItem(orderID uuid.UUID, req AddOrderItemRequest) (*core.Order, error)
+	UpdateOrderItem(orderID, itemID uuid.UUID, req UpdateOrderItemRequest) (*core.Order, error)
+	RemoveOrderItem(orderID, itemID uuid.UUID) (*core.Order, error)
+	// OpenTab, AddTabRound, CloseTab mengelola tab dine-in: satu order per meja yang diisi
+	// bertahap per ronde dan baru bisa dibayar setelah ditutup.
+	OpenTab(req OpenTabRequest) (*core.Order, error)
+	AddTabRound(orderID uuid.UUID, req AddTabRoundRequest) (*core.Order, error)
+	CloseTab(orderID uuid.UUID) (*core.Order, error)
+	ListOpenTabs() ([]TabSummary, error)
+	// SplitOrder memecah item order UNPAID ke bill anak; diskon voucher dan platform fee dibagi proporsional,
+	// service charge & pajak dihitung ulang per bill.
+	// Mengembalikan order induk diikuti semua bill anak.
+	SplitOrder(orderID uuid.UUID, req SplitOrderRequest, changedBy *uuid.UUID) ([]core.Order, error)
+	// MergeOrders menggabungkan beberapa order UNPAID ke order pertama dengan satu platform fee.
+	MergeOrders(req MergeOrdersRequest, changedBy *uuid.UUID) (*core.Order, error)
 }
@@ -2,6 +2,7 @@ package order
 
 import (
 	"errors"
+	"fmt"
 
 	"go-fiber-pos/internal/core"
 
@@ -23,34 +24,100 @@ func (ctrl *OrderController) Checkout(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
 	}
+	// Retry dari tablet kasir (Wi-Fi putus) memakai key yang sama → order tidak dobel
+	req.IdempotencyKey = c.Get("Idempotency-Key")
 
 	order, err := ctrl.service.Checkout(req)
+	if err != nil {
+		return checkoutError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Order berhasil dibuat",
+		"data":    order,
+	})
+}
+
+// Quote menghitung total keranjang kasir tanpa membuat order (body sama dengan Checkout).
+// Endpoint: POST /admin/orders/quote
+func (ctrl *OrderController) Quote(c *fiber.Ctx) error {
+	var req CheckoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	quote, err := ctrl.service.QuoteCheckout(req)
+	if err != nil {
+		return checkoutError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": quote})
+}
+
+// GetAll menampilkan daftar order (proyeksi ringkas) dengan filter dan cursor pagination.
+// Endpoint: GET /admin/orders?date_from=&date_to=&order_status=&payment_status=&order_source=&table_number=&queue_number=&cursor=&limit=
+func (ctrl *OrderController) GetAll(c *fiber.Ctx) error {
+	var query OrderListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter tidak valid"})
+	}
+
+	resp, err := ctrl.service.ListOrders(query)
 	if err != nil {
 		var valErr validator.ValidationErrors
 		if errors.As(err, &valErr) {
 			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
 		}
-		// Petakan sentinel errors ke HTTP status yang tepat
-		if errors.Is(err, core.ErrInsufficientStock) {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		if errors.Is(err, core.ErrInvalidDateFilter) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": err.Error()})
 		}
-		if errors.Is(err, core.ErrVoucherInvalid) || errors.Is(err, core.ErrVoucherMinOrder) {
+		if errors.Is(err, core.ErrInvalidCursor) {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data":        resp.Data,
+		"next_cursor": resp.NextCursor,
+	})
+}
+
+// Receipt merender struk/tiket dapur untuk printer thermal (escpos) atau sebagai dokumen digital (html/pdf).
+// Endpoint: GET /admin/orders/:id/receipt?format=escpos|html|pdf&type=receipt|kitchen&width=58|80&round=&drawer=
+func (ctrl *OrderController) Receipt(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	var query ReceiptQuery
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter tidak valid"})
+	}
+
+	receipt, err := ctrl.service.RenderReceipt(id, query)
+	if err != nil {
+		var valErr validator.ValidationErrors
+		if errors.As(err, &valErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+		}
 		if errors.Is(err, core.ErrNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 		}
+		if errors.Is(err, core.ErrReceiptFormat) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Order berhasil dibuat",
-		"data":    order,
-	})
+	c.Set(fiber.HeaderContentType, receipt.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, receipt.FileName))
+	return c.Status(fiber.StatusOK).Send(receipt.Body)
 }
 
-func (ctrl *OrderController) GetAll(c *fiber.Ctx) error {
-	orders, err := ctrl.service.GetAllOrders()
+// GetScheduled menampilkan antrean pre-order yang belum dikirim ke dapur.
+// Endpoint: GET /admin/orders/scheduled
+func (ctrl *OrderController) GetScheduled(c *fiber.Ctx) error {
+	orders, err := ctrl.service.ListScheduledOrders()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -72,3 +139,336 @@ func (ctrl *OrderController) GetByID(c *fiber.Ctx) error {
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": order})
 }
+
+// UpdateStatus memindahkan status order sesuai state machine.
+// Endpoint: PATCH /admin/orders/:id/status
+func (ctrl *OrderController) UpdateStatus(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	var req UpdateOrderStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.UpdateStatus(id, req, currentUserID(c))
+	if err != nil {
+		var valErr validator.ValidationErrors
+		if errors.As(err, &valErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+		}
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrInvalidTransition) || errors.Is(err, core.ErrOrderNotPaid) || errors.Is(err, core.ErrOrderAlreadyPaid) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Status order berhasil diperbarui",
+		"data":    order,
+	})
+}
+
+// Cancel membatalkan order yang belum dibayar dan mengembalikan stoknya.
+// Endpoint: POST /admin/orders/:id/cancel
+func (ctrl *OrderController) Cancel(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	var req CancelOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.CancelOrder(id, req, currentUserID(c))
+	if err != nil {
+		var valErr validator.ValidationErrors
+		if errors.As(err, &valErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+		}
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrInvalidTransition) || errors.Is(err, core.ErrOrderAlreadyPaid) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Order berhasil dibatalkan",
+		"data":    order,
+	})
+}
+
+// AddItem menambahkan item ke order yang belum dibayar.
+// Endpoint: POST /admin/orders/:id/items
+func (ctrl *OrderController) AddItem(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	var req AddOrderItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.AddOrderItem(id, req)
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Item berhasil ditambahkan",
+		"data":    order,
+	})
+}
+
+// UpdateItem mengubah qty/catatan satu item order.
+// Endpoint: PATCH /admin/orders/:id/items/:item_id
+func (ctrl *OrderController) UpdateItem(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+	itemID, err := uuid.Parse(c.Params("item_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID item tidak valid"})
+	}
+
+	var req UpdateOrderItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.UpdateOrderItem(id, itemID, req)
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Item berhasil diperbarui",
+		"data":    order,
+	})
+}
+
+// RemoveItem menghapus satu item dari order yang belum dibayar.
+// Endpoint: DELETE /admin/orders/:id/items/:item_id
+func (ctrl *OrderController) RemoveItem(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+	itemID, err := uuid.Parse(c.Params("item_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID item tidak valid"})
+	}
+
+	order, err := ctrl.service.RemoveOrderItem(id, itemID)
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Item berhasil dihapus",
+		"data":    order,
+	})
+}
+
+// OpenTab membuka tab dine-in untuk sebuah meja.
+// Endpoint: POST /admin/orders/tabs
+func (ctrl *OrderController) OpenTab(c *fiber.Ctx) error {
+	var req OpenTabRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.OpenTab(req)
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Tab berhasil dibuka",
+		"data":    order,
+	})
+}
+
+// GetOpenTabs menampilkan semua tab yang masih terbuka, per meja.
+// Endpoint: GET /admin/orders/tabs
+func (ctrl *OrderController) GetOpenTabs(c *fiber.Ctx) error {
+	tabs, err := ctrl.service.ListOpenTabs()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": tabs})
+}
+
+// AddTabRound menambahkan satu ronde pesanan ke tab dan meneruskannya ke dapur.
+// Endpoint: POST /admin/orders/tabs/:id/rounds
+func (ctrl *OrderController) AddTabRound(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	var req AddTabRoundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.AddTabRound(id, req)
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Ronde pesanan berhasil ditambahkan",
+		"data":    order,
+	})
+}
+
+// CloseTab menutup tab agar bisa dibayar.
+// Endpoint: POST /admin/orders/tabs/:id/close
+func (ctrl *OrderController) CloseTab(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	order, err := ctrl.service.CloseTab(id)
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Tab berhasil ditutup, silakan lanjutkan pembayaran",
+		"data":    order,
+	})
+}
+
+// Split memecah order menjadi beberapa bill (per item atau dibagi rata).
+// Endpoint: POST /admin/orders/:id/split
+func (ctrl *OrderController) Split(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	var req SplitOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	orders, err := ctrl.service.SplitOrder(id, req, currentUserID(c))
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Tagihan berhasil dipecah",
+		"data":    orders,
+	})
+}
+
+// Merge menggabungkan beberapa order yang belum dibayar ke order pertama.
+// Endpoint: POST /admin/orders/merge
+func (ctrl *OrderController) Merge(c *fiber.Ctx) error {
+	var req MergeOrdersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.MergeOrders(req, currentUserID(c))
+	if err != nil {
+		return editItemsError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Tagihan berhasil digabung",
+		"data":    order,
+	})
+}
+
+// editItemsError memetakan error perubahan item order (termasuk tab) ke HTTP status.
+func editItemsError(c *fiber.Ctx, err error) error {
+	var valErr validator.ValidationErrors
+	if errors.As(err, &valErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+	}
+	if errors.Is(err, core.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrInsufficientStock) || errors.Is(err, core.ErrOrderNotEditable) || errors.Is(err, core.ErrPaymentInProgress) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrTabAlreadyOpen) || errors.Is(err, core.ErrTabNotOpen) || errors.Is(err, core.ErrTabOpen) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrOrderAlreadyPaid) || errors.Is(err, core.ErrInvalidTransition) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrVoucherMinOrder) || errors.Is(err, core.ErrEmptyOrder) || errors.Is(err, core.ErrInvalidSplit) || errors.Is(err, core.ErrVoucherConflict) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrInvalidModifier) || errors.Is(err, core.ErrInvalidBundle) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+// currentUserID mengambil user_id yang disimpan middleware.Protected(), nil jika tidak ada.
+func currentUserID(c *fiber.Ctx) *uuid.UUID {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
+
+// GenerateTableToken membuat token QR untuk sebuah meja (dicetak dan ditempel di meja).
+// Endpoint: POST /admin/orders/table-token
+func (ctrl *OrderController) GenerateTableToken(c *fiber.Ctx) error {
+	var req TableTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	token, err := ctrl.service.GenerateTableToken(req)
+	if err != nil {
+		var valErr validator.ValidationErrors
+		if errors.As(err, &valErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": fiber.Map{"table_number": req.TableNumber, "table_token": token},
+	})
+}
+
+// checkoutError memetakan error Checkout ke HTTP status — dipakai oleh checkout kasir dan E-Menu.
+func checkoutError(c *fiber.Ctx, err error) error {
+	var valErr validator.ValidationErrors
+	if errors.As(err, &valErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+	}
+	// Petakan sentinel errors ke HTTP status yang tepat
+	if errors.Is(err, core.ErrInsufficientStock) || errors.Is(err, core.ErrIdempotencyReuse) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrVoucherInvalid) || errors.Is(err, core.ErrVoucherMinOrder) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrInvalidSchedule) || errors.Is(err, core.ErrStoreClosed) ||
+		errors.Is(err, core.ErrInvalidModifier) || errors.Is(err, core.ErrInvalidBundle) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrInvalidTableToken) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, core.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
@@ -1,12 +1,24 @@
 package order
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // CheckoutItemInput adalah DTO untuk satu item dalam request checkout.
 type CheckoutItemInput struct {
+	ProductID         uuid.UUID              `json:"product_id" validate:"required"`
+	Qty               int                    `json:"qty" validate:"required,min=1"`
+	Notes             string                 `json:"notes"`
+	ModifierOptionIDs []uuid.UUID            `json:"modifier_option_ids" validate:"max=20,dive,required"` // Opsi modifier (ukuran, level gula, extra shot)
+	BundleSelections  []BundleSelectionInput `json:"bundle_selections" validate:"max=20,dive"`            // Pilihan slot bundle; slot tetap boleh dikosongkan
+}
+
+// BundleSelectionInput memilih produk untuk satu slot bundle, misal slot "Pastry" diisi Croissant.
+type BundleSelectionInput struct {
+	SlotID    uuid.UUID `json:"slot_id" validate:"required"`
 	ProductID uuid.UUID `json:"product_id" validate:"required"`
-	Qty       int       `json:"qty" validate:"required,min=1"`
-	Notes     string    `json:"notes"`
 }
 
 // CheckoutRequest adalah DTO untuk request checkout order baru.
@@ -15,4 +27,313 @@ type CheckoutRequest struct {
 	TableNumber *string             `json:"table_number"`
 	VoucherCode string              `json:"voucher_code"`
 	Items       []CheckoutItemInput `json:"items" validate:"required,min=1,dive"`
+	// ScheduledFor mengubah order menjadi pre-order: stok langsung dipesan (dipotong),
+	// tetapi order baru muncul di dapur PreOrderLeadMinutes sebelum waktu ambil.
+	ScheduledFor *time.Time `json:"scheduled_for"`
+	// IdempotencyKey diisi controller dari header Idempotency-Key (bukan dari body).
+	IdempotencyKey string `json:"-" validate:"max=100"`
+}
+
+// AddOrderItemRequest adalah DTO untuk menambah item ke order yang belum dibayar (POST /orders/:id/items).
+type AddOrderItemRequest struct {
+	ProductID         uuid.UUID              `json:"product_id" validate:"required"`
+	Qty               int                    `json:"qty" validate:"required,min=1"`
+	Notes             string                 `json:"notes" validate:"max=255"`
+	ModifierOptionIDs []uuid.UUID            `json:"modifier_option_ids" validate:"max=20,dive,required"`
+	BundleSelections  []BundleSelectionInput `json:"bundle_selections" validate:"max=20,dive"`
+}
+
+// UpdateOrderItemRequest adalah DTO untuk mengubah qty/catatan satu item (PATCH /orders/:id/items/:item_id).
+// Notes nil = catatan lama dipertahankan.
+type UpdateOrderItemRequest struct {
+	Qty   int     `json:"qty" validate:"required,min=1"`
+	Notes *string `json:"notes" validate:"omitempty,max=255"`
+}
+
+// OpenTabRequest adalah DTO untuk membuka tab dine-in pada sebuah meja (POST /orders/tabs).
+type OpenTabRequest struct {
+	TableNumber string `json:"table_number" validate:"required,max=50"`
+	OrderSource string `json:"order_source" validate:"omitempty,oneof=CASHIER E_MENU"` // Default CASHIER
+}
+
+// AddTabRoundRequest adalah satu ronde pesanan yang ditambahkan ke tab (POST /orders/tabs/:id/rounds).
+type AddTabRoundRequest struct {
+	Items []CheckoutItemInput `json:"items" validate:"required,min=1,dive"`
+}
+
+// TabSummary adalah ringkasan satu tab terbuka untuk daftar tab per meja.
+type TabSummary struct {
+	ID               uuid.UUID `json:"id"`
+	TableNumber      string    `json:"table_number"`
+	QueueNumber      string    `json:"queue_number"`
+	OrderStatus      string    `json:"order_status"`
+	Rounds           int       `json:"rounds"`
+	ItemCount        int       `json:"item_count"`
+	TotalFinalAmount int       `json:"total_final_amount"`
+	OpenedAt         time.Time `json:"opened_at"`
+}
+
+// Mode split bill.
+const (
+	SplitModeItems = "ITEMS" // Pelanggan memilih item (dan qty) untuk setiap bill baru
+	SplitModeEven  = "EVEN"  // Item dibagi otomatis ke beberapa bill dengan nominal serata mungkin
+)
+
+// SplitItemInput memindahkan sejumlah qty dari satu OrderItem ke bill baru.
+type SplitItemInput struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Qty         int       `json:"qty" validate:"required,min=1"`
+}
+
+// SplitGroupInput adalah isi satu bill anak.
+type SplitGroupInput struct {
+	Items []SplitItemInput `json:"items" validate:"required,min=1,dive"`
+}
+
+// SplitOrderRequest adalah DTO untuk POST /orders/:id/split.
+// ITEMS: setiap group menjadi bill anak, sisa item tetap di order induk.
+// EVEN: order dibagi menjadi Ways bill (induk + Ways-1 anak); karena item tidak bisa dipecah,
+// nominalnya serata mungkin — untuk bagi rata persis gunakan split payment pada satu order.
+type SplitOrderRequest struct {
+	Mode   string            `json:"mode" validate:"required,oneof=ITEMS EVEN"`
+	Groups []SplitGroupInput `json:"groups" validate:"required_if=Mode ITEMS,max=20,dive"`
+	Ways   int               `json:"ways" validate:"required_if=Mode EVEN,omitempty,min=2,max=20"`
+}
+
+// MergeOrdersRequest adalah DTO untuk POST /orders/merge. Order pertama menjadi order tujuan
+// (nomor antrean dan tracking token-nya dipertahankan).
+type MergeOrdersRequest struct {
+	OrderIDs []uuid.UUID `json:"order_ids" validate:"required,min=2,max=10,unique,dive,required"`
+}
+
+// UpdateOrderStatusRequest adalah DTO untuk memindahkan status order (PATCH /orders/:id/status).
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=CONFIRMED PREPARING READY COMPLETED CANCELLED"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+// CancelOrderRequest adalah DTO untuk pembatalan order. Alasan wajib diisi untuk audit.
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+// OrderListQuery adalah query parameter untuk GET /orders (semua opsional).
+type OrderListQuery struct {
+	DateFrom      string `query:"date_from" validate:"omitempty,datetime=2006-01-02"` // Inklusif
+	DateTo        string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`   // Inklusif (s/d 23:59:59)
+	OrderStatus   string `query:"order_status" validate:"omitempty,oneof=PENDING CONFIRMED PREPARING READY COMPLETED CANCELLED"`
+	PaymentStatus string `query:"payment_status" validate:"omitempty,oneof=UNPAID PARTIALLY_PAID PAID FAILED EXPIRED REFUND_PENDING REFUNDED PARTIALLY_REFUNDED"`
+	OrderSource   string `query:"order_source" validate:"omitempty,oneof=CASHIER E_MENU"`
+	TableNumber   string `query:"table_number" validate:"max=50"`
+	QueueNumber   string `query:"queue_number" validate:"max=20"`
+	Cursor        string `query:"cursor"`                                   // Nilai next_cursor dari halaman sebelumnya
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=100"` // Default 20
+}
+
+// OrderListFilter adalah hasil parsing OrderListQuery yang siap dipakai repository.
+type OrderListFilter struct {
+	DateFrom      *time.Time
+	DateTo        *time.Time // Eksklusif: awal hari setelah date_to
+	OrderStatus   string
+	PaymentStatus string
+	OrderSource   string
+	TableNumber   string
+	QueueNumber   string
+	// Keyset cursor: ambil order yang lebih lama dari (CursorCreatedAt, CursorID)
+	CursorCreatedAt *time.Time
+	CursorID        *uuid.UUID
+	Limit           int
+}
+
+// OrderSummary adalah proyeksi ringan untuk daftar order (tanpa preload Items.Product).
+type OrderSummary struct {
+	ID                 uuid.UUID  `json:"id"`
+	OrderSource        string     `json:"order_source"`
+	QueueNumber        string     `json:"queue_number"`
+	TableNumber        *string    `json:"table_number"`
+	OrderStatus        string     `json:"order_status"`
+	PaymentStatus      string     `json:"payment_status"`
+	TotalServiceCharge int        `json:"total_service_charge"`
+	TotalTax           int        `json:"total_tax"`
+	TotalFinalAmount   int        `json:"total_final_amount"`
+	TotalPaid          int        `json:"total_paid"`
+	ItemCount          int        `json:"item_count"`
+	ScheduledFor       *time.Time `json:"scheduled_for,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// OrderListResponse adalah satu halaman daftar order. NextCursor kosong = halaman terakhir.
+type OrderListResponse struct {
+	Data       []OrderSummary `json:"data"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+// PublicCheckoutRequest adalah DTO checkout mandiri pelanggan via E-Menu (scan QR meja).
+// Nomor meja TIDAK diambil dari input bebas, melainkan dari TableToken yang ditandatangani server.
+type PublicCheckoutRequest struct {
+	TableToken  string              `json:"table_token" validate:"required"`
+	VoucherCode string              `json:"voucher_code"`
+	Items       []CheckoutItemInput `json:"items" validate:"required,min=1,max=50,dive"`
+}
+
+// Kode peringatan quote harga.
+const (
+	QuoteWarningInsufficientStock = "INSUFFICIENT_STOCK" // Checkout akan ditolak sampai qty dikurangi
+	QuoteWarningUnavailable       = "UNAVAILABLE"        // Produk sedang ditandai tidak tersedia di menu; tidak menahan checkout
+)
+
+// QuoteWarning adalah peringatan ketersediaan pada quote harga. Peringatan tidak mengubah angka quote.
+type QuoteWarning struct {
+	Code      string    `json:"code"`
+	ProductID uuid.UUID `json:"product_id"`
+	Name      string    `json:"name"`      // Nama produk, komponen bundle, atau opsi modifier yang bermasalah
+	Requested int       `json:"requested"` // Total qty yang dibutuhkan keranjang (INSUFFICIENT_STOCK)
+	Available int       `json:"available"` // Stok saat quote dibuat (INSUFFICIENT_STOCK)
+	Message   string    `json:"message"`
+}
+
+// QuoteItemResponse adalah rincian harga satu baris keranjang, dihitung sama persis dengan Checkout.
+type QuoteItemResponse struct {
+	ProductID     uuid.UUID                    `json:"product_id"`
+	ProductName   string                       `json:"product_name"`
+	Qty           int                          `json:"qty"`
+	BasePrice     int                          `json:"base_price"`     // Harga produk sebelum modifier (harga promo jika berlaku)
+	IsPromoPrice  bool                         `json:"is_promo_price"` // true = BasePrice adalah harga promo terjadwal
+	UnitPrice     int                          `json:"unit_price"`     // BasePrice + selisih modifier
+	Subtotal      int                          `json:"subtotal"`
+	PromotionName string                       `json:"promotion_name,omitempty"`
+	PromoDiscount int                          `json:"promo_discount"`
+	Notes         string                       `json:"notes"`
+	Modifiers     []OrderItemModifierResponse  `json:"modifiers"`
+	Components    []OrderItemComponentResponse `json:"components"`
+}
+
+// QuoteResponse adalah hasil quote harga (dry-run checkout): total yang akan tercatat jika keranjang
+// yang sama di-checkout saat ini. Stok tidak dipesan, jadi angka & ketersediaan bisa berubah sebelum checkout.
+type QuoteResponse struct {
+	Items              []QuoteItemResponse   `json:"items"`
+	TotalBasePrice     int                   `json:"total_base_price"`
+	TotalPromoDiscount int                   `json:"total_promo_discount"`
+	VoucherDiscount    int                   `json:"voucher_discount"`
+	TotalDiscount      int                   `json:"total_discount"` // TotalPromoDiscount + VoucherDiscount
+	PlatformFee        int                   `json:"platform_fee"`
+	Charges            []OrderChargeResponse `json:"charges"`
+	TotalServiceCharge int                   `json:"total_service_charge"`
+	TotalTax           int                   `json:"total_tax"`          // Termasuk bagian inklusif (TotalTaxIncluded)
+	TotalTaxIncluded   int                   `json:"total_tax_included"` // Sudah termasuk di harga, tidak menambah total
+	TotalFinalAmount   int                   `json:"total_final_amount"`
+	Warnings           []QuoteWarning        `json:"warnings"`
+	CanCheckout        bool                  `json:"can_checkout"` // false jika ada peringatan yang membuat Checkout ditolak
+	PricedAt           time.Time             `json:"priced_at"`    // Waktu evaluasi promo (zona waktu toko)
+}
+
+// TableTokenRequest adalah DTO admin untuk membuat token QR sebuah meja.
+type TableTokenRequest struct {
+	TableNumber string `json:"table_number" validate:"required,max=50"`
+}
+
+// OrderItemModifierResponse adalah satu opsi modifier yang dipilih pada item order.
+type OrderItemModifierResponse struct {
+	GroupName  string `json:"group_name"`
+	OptionName string `json:"option_name"`
+	PriceDelta int    `json:"price_delta"`
+}
+
+// OrderItemComponentResponse adalah satu komponen bundle pada item order.
+type OrderItemComponentResponse struct {
+	SlotName    string `json:"slot_name"`
+	ProductName string `json:"product_name"`
+	Qty         int    `json:"qty"` // Per satu unit bundle
+}
+
+// OrderChargeResponse adalah satu baris service charge/pajak pada order.
+type OrderChargeResponse struct {
+	Kind        string `json:"kind"` // SERVICE | TAX
+	Name        string `json:"name"`
+	RateBps     int    `json:"rate_bps"`
+	IsInclusive bool   `json:"is_inclusive"` // true = sudah termasuk di harga, tidak menambah total
+	Amount      int    `json:"amount"`
+}
+
+// PublicOrderItemResponse adalah item order yang aman ditampilkan ke pelanggan.
+type PublicOrderItemResponse struct {
+	ProductName   string                       `json:"product_name"`
+	Qty           int                          `json:"qty"`
+	UnitPrice     int                          `json:"unit_price"` // Sudah termasuk selisih harga modifier
+	Subtotal      int                          `json:"subtotal"`
+	Notes         string                       `json:"notes"`
+	Modifiers     []OrderItemModifierResponse  `json:"modifiers"`
+	Components    []OrderItemComponentResponse `json:"components"` // Isi bundle; kosong untuk produk biasa
+	PromotionName string                       `json:"promotion_name,omitempty"`
+	PromoDiscount int                          `json:"promo_discount"`
+}
+
+// PublicOrderResponse adalah tampilan order untuk pelanggan: tanpa ID internal, voucher detail, atau data payment gateway.
+type PublicOrderResponse struct {
+	TrackingToken      string                    `json:"tracking_token"` // Dipakai pelanggan untuk GET /public/orders/:token
+	QueueNumber        string                    `json:"queue_number"`
+	TableNumber        *string                   `json:"table_number"`
+	OrderStatus        string                    `json:"order_status"`
+	PaymentStatus      string                    `json:"payment_status"`
+	Items              []PublicOrderItemResponse `json:"items"`
+	TotalBasePrice     int                       `json:"total_base_price"`
+	TotalDiscount      int                       `json:"total_discount"`
+	TotalPromoDiscount int                       `json:"total_promo_discount"` // Bagian TotalDiscount dari promo otomatis
+	PlatformFee        int                       `json:"platform_fee"`
+	Charges            []OrderChargeResponse     `json:"charges"` // Service charge & pajak, urut seperti di struk
+	TotalFinalAmount   int                       `json:"total_final_amount"`
+	TotalPaid          int                       `json:"total_paid"`
+	OutstandingAmount  int                       `json:"outstanding_amount"`
+	CreatedAt          time.Time                 `json:"created_at"`
+}
+
+// KitchenOrderItemResponse adalah satu baris item di layar dapur.
+type KitchenOrderItemResponse struct {
+	ProductName string   `json:"product_name"`
+	Qty         int      `json:"qty"`
+	Notes       string   `json:"notes"`
+	Modifiers   []string `json:"modifiers"`  // Nama opsi, misal ["Large", "Less Sugar"]
+	Components  []string `json:"components"` // Isi bundle, misal ["Americano", "2x Croissant"]
+	Round       int      `json:"round"`      // Dapur menyiapkan ronde terbaru dari sebuah tab
+}
+
+// KitchenOrderResponse adalah tampilan order untuk kitchen display (tanpa harga).
+type KitchenOrderResponse struct {
+	ID            uuid.UUID                  `json:"id"`
+	QueueNumber   string                     `json:"queue_number"`
+	TableNumber   *string                    `json:"table_number"`
+	OrderSource   string                     `json:"order_source"`
+	OrderStatus   string                     `json:"order_status"`
+	PaymentStatus string                     `json:"payment_status"`
+	IsOpenTab     bool                       `json:"is_open_tab"`
+	Items         []KitchenOrderItemResponse `json:"items"`
+	CreatedAt     time.Time                  `json:"created_at"`
+}
+
+// ReceiptQuery adalah query parameter untuk GET /admin/orders/:id/receipt.
+// Width dan Drawer hanya berlaku untuk escpos; format html hanya untuk struk pelanggan.
+type ReceiptQuery struct {
+	Format string `query:"format" validate:"required,oneof=escpos html pdf"`
+	Type   string `query:"type" validate:"omitempty,oneof=receipt kitchen"` // Default receipt
+	Width  int    `query:"width" validate:"omitempty,oneof=58 80"`          // Lebar kertas (mm), default 80
+	Round  int    `query:"round" validate:"omitempty,min=1"`                // Tiket dapur: hanya ronde tertentu dari tab
+	Drawer *bool  `query:"drawer"`                                          // Buka laci kasir; default otomatis jika ada pembayaran CASH
+}
+
+// PublicReceiptQuery adalah query parameter untuk GET /public/orders/:token/receipt (struk digital pelanggan).
+type PublicReceiptQuery struct {
+	Format   string `query:"format" validate:"omitempty,oneof=html pdf"` // Default html
+	Download bool   `query:"download"`                                   // true = Content-Disposition attachment
+}
+
+// RenderedReceipt adalah hasil render struk yang siap dikirim apa adanya ke client/printer.
+type RenderedReceipt struct {
+	ContentType string
+	FileName    string
+	Body        []byte
+}
+
+// KitchenStreamQuery adalah query parameter untuk GET /kitchen/stream.
+type KitchenStreamQuery struct {
+	OrderSource string `query:"order_source" validate:"omitempty,oneof=CASHIER E_MENU"`
 }
@@ -0,0 +1,115 @@
+package order
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-fiber-pos/internal/core"
+	"go-fiber-pos/pkg/logger"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// kitchenHeartbeatInterval menjaga koneksi SSE tetap hidup di balik proxy/load balancer.
+const kitchenHeartbeatInterval = 15 * time.Second
+
+// KitchenController melayani kitchen display via Server-Sent Events.
+type KitchenController struct {
+	service OrderService
+	events  core.OrderEventSubscriber
+	v       *validator.Validate
+}
+
+func NewKitchenController(service OrderService, events core.OrderEventSubscriber, v *validator.Validate) *KitchenController {
+	return &KitchenController{service: service, events: events, v: v}
+}
+
+// Stream mengirim event order (dibuat, dibayar, status berubah) secara realtime.
+// Saat connect, semua order yang masih terbuka dikirim ulang sebagai "order.snapshot"
+// sehingga layar yang reconnect tidak pernah basi.
+// Endpoint: GET /admin/kitchen/stream?order_source=CASHIER|E_MENU
+func (ctrl *KitchenController) Stream(c *fiber.Ctx) error {
+	var query KitchenStreamQuery
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter tidak valid"})
+	}
+	if err := ctrl.v.Struct(query); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": err.Error()})
+	}
+	source := query.OrderSource
+
+	// Subscribe SEBELUM mengambil snapshot agar tidak ada event yang terlewat di antaranya
+	events, unsubscribe := ctrl.events.Subscribe()
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Matikan buffering Nginx
+
+	// Catatan: *fiber.Ctx tidak boleh dipakai di dalam stream writer (sudah dikembalikan ke pool)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		snapshot, err := ctrl.service.GetKitchenOrders(source)
+		if err != nil {
+			if logger.Log != nil {
+				logger.Log.Errorf("Gagal memuat snapshot kitchen display: %v", err)
+			}
+			return
+		}
+		for _, order := range snapshot {
+			if err := writeSSE(w, core.EventOrderSnapshot, order); err != nil {
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(kitchenHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if source != "" && event.OrderSource != source {
+					continue
+				}
+				order, err := ctrl.service.GetKitchenOrder(event.OrderID)
+				if err != nil {
+					continue
+				}
+				if err := writeSSE(w, event.Type, order); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+
+			// Flush gagal = client sudah menutup koneksi
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeSSE menulis satu pesan SSE: "event: <type>\ndata: <json>\n\n".
+func writeSSE(w *bufio.Writer, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+	return err
+}
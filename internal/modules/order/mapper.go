@@ -0,0 +1,231 @@
+package order
+
+import (
+	"strconv"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+)
+
+// ToPublicOrderResponse: Domain Order -> tampilan aman untuk pelanggan E-Menu
+func ToPublicOrderResponse(domain *core.Order) PublicOrderResponse {
+	items := []PublicOrderItemResponse{}
+	for _, item := range domain.Items {
+		modifiers := []OrderItemModifierResponse{}
+		for _, m := range item.Modifiers {
+			modifiers = append(modifiers, OrderItemModifierResponse{
+				GroupName:  m.GroupName,
+				OptionName: m.OptionName,
+				PriceDelta: m.PriceDelta,
+			})
+		}
+		components := []OrderItemComponentResponse{}
+		for _, c := range item.Components {
+			components = append(components, OrderItemComponentResponse{
+				SlotName:    c.SlotName,
+				ProductName: c.ProductName,
+				Qty:         c.Qty,
+			})
+		}
+		items = append(items, PublicOrderItemResponse{
+			ProductName:   item.Product.Name,
+			Qty:           item.Qty,
+			UnitPrice:     item.UnitPrice,
+			Subtotal:      item.Subtotal,
+			Notes:         item.Notes,
+			Modifiers:     modifiers,
+			Components:    components,
+			PromotionName: item.PromotionName,
+			PromoDiscount: item.PromoDiscount,
+		})
+	}
+
+	charges := []OrderChargeResponse{}
+	for _, c := range domain.Charges {
+		charges = append(charges, OrderChargeResponse{
+			Kind:        c.Kind,
+			Name:        c.Name,
+			RateBps:     c.RateBps,
+			IsInclusive: c.IsInclusive,
+			Amount:      c.Amount,
+		})
+	}
+
+	return PublicOrderResponse{
+		TrackingToken:      domain.TrackingToken,
+		QueueNumber:        domain.QueueNumber,
+		TableNumber:        domain.TableNumber,
+		OrderStatus:        domain.OrderStatus,
+		PaymentStatus:      domain.PaymentStatus,
+		Items:              items,
+		TotalBasePrice:     domain.TotalBasePrice,
+		TotalDiscount:      domain.TotalDiscount,
+		TotalPromoDiscount: domain.TotalPromoDiscount,
+		PlatformFee:        domain.PlatformFee,
+		Charges:            charges,
+		TotalFinalAmount:   domain.TotalFinalAmount,
+		TotalPaid:          domain.TotalPaid,
+		OutstandingAmount:  domain.OutstandingAmount,
+		CreatedAt:          domain.CreatedAt,
+	}
+}
+
+// ToKitchenOrderResponse: Domain Order -> tampilan kitchen display
+func ToKitchenOrderResponse(domain *core.Order) KitchenOrderResponse {
+	items := []KitchenOrderItemResponse{}
+	for _, item := range domain.Items {
+		modifiers := []string{}
+		for _, m := range item.Modifiers {
+			modifiers = append(modifiers, m.OptionName)
+		}
+		components := []string{}
+		for _, c := range item.Components {
+			components = append(components, componentLabel(c))
+		}
+		items = append(items, KitchenOrderItemResponse{
+			ProductName: item.Product.Name,
+			Qty:         item.Qty,
+			Notes:       item.Notes,
+			Modifiers:   modifiers,
+			Components:  components,
+			Round:       item.Round,
+		})
+	}
+
+	return KitchenOrderResponse{
+		ID:            domain.ID,
+		QueueNumber:   domain.QueueNumber,
+		TableNumber:   domain.TableNumber,
+		OrderSource:   domain.OrderSource,
+		OrderStatus:   domain.OrderStatus,
+		PaymentStatus: domain.PaymentStatus,
+		IsOpenTab:     domain.IsOpenTab,
+		Items:         items,
+		CreatedAt:     domain.CreatedAt,
+	}
+}
+
+// ToKitchenOrderResponseList: Array Domain -> Array tampilan kitchen display
+func ToKitchenOrderResponseList(domains []core.Order) []KitchenOrderResponse {
+	responses := []KitchenOrderResponse{}
+	for i := range domains {
+		responses = append(responses, ToKitchenOrderResponse(&domains[i]))
+	}
+	return responses
+}
+
+// ToTabSummary: Domain Order (tab terbuka, Items sudah di-preload) -> ringkasan tab per meja
+func ToTabSummary(domain *core.Order) TabSummary {
+	summary := TabSummary{
+		ID:               domain.ID,
+		QueueNumber:      domain.QueueNumber,
+		OrderStatus:      domain.OrderStatus,
+		ItemCount:        len(domain.Items),
+		TotalFinalAmount: domain.TotalFinalAmount,
+		OpenedAt:         domain.CreatedAt,
+	}
+	if domain.TableNumber != nil {
+		summary.TableNumber = *domain.TableNumber
+	}
+	for _, item := range domain.Items {
+		if item.Round > summary.Rounds {
+			summary.Rounds = item.Round
+		}
+	}
+	return summary
+}
+
+// ToTabSummaryList: Array Domain -> Array ringkasan tab
+func ToTabSummaryList(domains []core.Order) []TabSummary {
+	responses := []TabSummary{}
+	for i := range domains {
+		responses = append(responses, ToTabSummary(&domains[i]))
+	}
+	return responses
+}
+
+// ToQuoteResponse: Order hasil priceCheckout (belum disimpan) -> rincian quote harga.
+// CanCheckout false jika ada peringatan stok, karena Checkout dengan keranjang yang sama akan ditolak.
+func ToQuoteResponse(domain *core.Order, products map[uuid.UUID]*core.Product, pricedAt time.Time, warnings []QuoteWarning) QuoteResponse {
+	items := []QuoteItemResponse{}
+	for _, item := range domain.Items {
+		modifiers := []OrderItemModifierResponse{}
+		for _, m := range item.Modifiers {
+			modifiers = append(modifiers, OrderItemModifierResponse{
+				GroupName:  m.GroupName,
+				OptionName: m.OptionName,
+				PriceDelta: m.PriceDelta,
+			})
+		}
+		components := []OrderItemComponentResponse{}
+		for _, c := range item.Components {
+			components = append(components, OrderItemComponentResponse{
+				SlotName:    c.SlotName,
+				ProductName: c.ProductName,
+				Qty:         c.Qty,
+			})
+		}
+		line := QuoteItemResponse{
+			ProductID:     item.ProductID,
+			Qty:           item.Qty,
+			UnitPrice:     item.UnitPrice,
+			Subtotal:      item.Subtotal,
+			PromotionName: item.PromotionName,
+			PromoDiscount: item.PromoDiscount,
+			Notes:         item.Notes,
+			Modifiers:     modifiers,
+			Components:    components,
+		}
+		if product, ok := products[item.ProductID]; ok {
+			line.ProductName = product.Name
+			line.BasePrice = calculateUnitPrice(product, pricedAt)
+			line.IsPromoPrice = product.PromoActiveAt(pricedAt)
+		}
+		items = append(items, line)
+	}
+
+	charges := []OrderChargeResponse{}
+	for _, c := range domain.Charges {
+		charges = append(charges, OrderChargeResponse{
+			Kind:        c.Kind,
+			Name:        c.Name,
+			RateBps:     c.RateBps,
+			IsInclusive: c.IsInclusive,
+			Amount:      c.Amount,
+		})
+	}
+
+	canCheckout := true
+	for _, w := range warnings {
+		if w.Code == QuoteWarningInsufficientStock {
+			canCheckout = false
+		}
+	}
+
+	return QuoteResponse{
+		Items:              items,
+		TotalBasePrice:     domain.TotalBasePrice,
+		TotalPromoDiscount: domain.TotalPromoDiscount,
+		VoucherDiscount:    domain.TotalDiscount - domain.TotalPromoDiscount,
+		TotalDiscount:      domain.TotalDiscount,
+		PlatformFee:        domain.PlatformFee,
+		Charges:            charges,
+		TotalServiceCharge: domain.TotalServiceCharge,
+		TotalTax:           domain.TotalTax,
+		TotalTaxIncluded:   domain.TotalTaxIncluded,
+		TotalFinalAmount:   domain.TotalFinalAmount,
+		Warnings:           warnings,
+		CanCheckout:        canCheckout,
+		PricedAt:           pricedAt,
+	}
+}
+
+// componentLabel adalah teks komponen bundle untuk dapur & struk, contoh "2x Croissant".
+func componentLabel(c core.OrderItemComponent) string {
+	if c.Qty > 1 {
+		return strconv.Itoa(c.Qty) + "x " + c.ProductName
+	}
+	return c.ProductName
+}
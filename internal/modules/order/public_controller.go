@@ -0,0 +1,96 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PublicOrderController melayani pelanggan E-Menu (tanpa JWT).
+type PublicOrderController struct {
+	service OrderService
+}
+
+func NewPublicOrderController(service OrderService) *PublicOrderController {
+	return &PublicOrderController{service: service}
+}
+
+// Checkout membuat order E-Menu dari hasil scan QR meja.
+// Endpoint: POST /public/orders/checkout
+func (ctrl *PublicOrderController) Checkout(c *fiber.Ctx) error {
+	var req PublicCheckoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	order, err := ctrl.service.PublicCheckout(req)
+	if err != nil {
+		return checkoutError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Pesanan berhasil dibuat",
+		"data":    order,
+	})
+}
+
+// Quote menampilkan total keranjang E-Menu sebelum pesanan dikirim (body sama dengan Checkout).
+// Endpoint: POST /public/orders/quote
+func (ctrl *PublicOrderController) Quote(c *fiber.Ctx) error {
+	var req PublicCheckoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	quote, err := ctrl.service.PublicQuote(req)
+	if err != nil {
+		return checkoutError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": quote})
+}
+
+// Track menampilkan antrean, status, item, dan status pembayaran order ke pelanggan.
+// Endpoint: GET /public/orders/:token
+func (ctrl *PublicOrderController) Track(c *fiber.Ctx) error {
+	order, err := ctrl.service.TrackOrder(c.Params("token"))
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Pesanan tidak ditemukan"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": order})
+}
+
+// Receipt menampilkan struk digital pelanggan (HTML untuk dibuka/dikirim via email, PDF untuk diunduh).
+// Endpoint: GET /public/orders/:token/receipt?format=html|pdf&download=true
+func (ctrl *PublicOrderController) Receipt(c *fiber.Ctx) error {
+	var query PublicReceiptQuery
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Query parameter tidak valid"})
+	}
+
+	receipt, err := ctrl.service.RenderPublicReceipt(c.Params("token"), query)
+	if err != nil {
+		var valErr validator.ValidationErrors
+		if errors.As(err, &valErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+		}
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Pesanan tidak ditemukan"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	disposition := "inline"
+	if query.Download {
+		disposition = "attachment"
+	}
+	c.Set(fiber.HeaderContentType, receipt.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`%s; filename="%s"`, disposition, receipt.FileName))
+	return c.Status(fiber.StatusOK).Send(receipt.Body)
+}
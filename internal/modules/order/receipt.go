@@ -0,0 +1,272 @@
+package order
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fiber-pos/internal/core"
+)
+
+// Format & jenis struk yang didukung GET /admin/orders/:id/receipt.
+const (
+	ReceiptFormatESCPOS = "escpos"
+	ReceiptFormatHTML   = "html"
+	ReceiptFormatPDF    = "pdf"
+
+	ReceiptTypeReceipt = "receipt" // Struk pelanggan (harga, pembayaran, kembalian)
+	ReceiptTypeKitchen = "kitchen" // Tiket dapur (tanpa harga, nomor antrean besar)
+)
+
+// receiptData adalah isi struk yang sudah dihitung dari order + profil toko.
+// Satu sumber data ini dipakai oleh semua renderer agar angka di setiap format selalu sama.
+type receiptData struct {
+	StoreName    string
+	StoreAddress string
+	StorePhone   string
+
+	QueueNumber string
+	TableNumber string
+	OrderSource string
+	IsOpenTab   bool
+	CreatedAt   time.Time
+
+	Items []receiptItem
+
+	TotalBasePrice     int
+	VoucherCode        string
+	TotalDiscount      int
+	TotalPromoDiscount int
+	Charges            []receiptCharge
+	PlatformFee        int
+	TotalFinalAmount   int
+	TotalPaid          int
+	TotalRefunded      int
+	OutstandingAmount  int
+	Payments           []receiptPayment
+}
+
+type receiptItem struct {
+	Name          string
+	Qty           int
+	UnitPrice     int
+	Subtotal      int
+	Notes         string
+	Round         int
+	Modifiers     []receiptModifier
+	Components    []string // Isi bundle per unit, contoh "2x Croissant"
+	PromoName     string
+	PromoDiscount int
+}
+
+type receiptModifier struct {
+	Name       string
+	PriceDelta int
+}
+
+// Label adalah teks modifier di struk, contoh "Large (+5.000)"; opsi tanpa selisih harga hanya nama.
+func (m receiptModifier) Label() string {
+	switch {
+	case m.PriceDelta > 0:
+		return m.Name + " (+" + formatRupiah(m.PriceDelta) + ")"
+	case m.PriceDelta < 0:
+		return m.Name + " (" + formatRupiah(m.PriceDelta) + ")"
+	}
+	return m.Name
+}
+
+// receiptCharge adalah satu baris service charge/pajak. Pajak inklusif dicetak sebagai keterangan
+// karena sudah termasuk di harga item.
+type receiptCharge struct {
+	Name        string
+	RateBps     int
+	Amount      int
+	IsInclusive bool
+}
+
+// Label contoh: "PB1 10%", "Service 5,5%", atau "Termasuk PPN 11%" untuk pajak inklusif.
+func (c receiptCharge) Label() string {
+	label := c.Name + " " + formatRate(c.RateBps)
+	if c.IsInclusive {
+		return "Termasuk " + label
+	}
+	return label
+}
+
+type receiptPayment struct {
+	Method         string
+	Amount         int
+	Rounding       int // Selisih pembulatan, dicetak terpisah dari Amount
+	AmountTendered int
+	ChangeAmount   int
+	PaidAt         *time.Time
+}
+
+// buildReceiptData menyusun receiptData dari order hasil FindByID (Items.Product, Voucher, Payments
+// sudah di-preload). Hanya payment PAID yang dicetak. store boleh nil jika profil belum dibuat.
+func buildReceiptData(order *core.Order, store *core.StoreProfile) receiptData {
+	data := receiptData{
+		QueueNumber:       order.QueueNumber,
+		OrderSource:       order.OrderSource,
+		IsOpenTab:         order.IsOpenTab,
+		CreatedAt:         order.CreatedAt,
+		TotalBasePrice:    order.TotalBasePrice,
+		TotalDiscount:     order.TotalDiscount,
+		PlatformFee:       order.PlatformFee,
+		TotalFinalAmount:  order.TotalFinalAmount,
+		TotalPaid:         order.TotalPaid,
+		TotalRefunded:     order.TotalRefunded,
+		OutstandingAmount: order.OutstandingAmount,
+	}
+	if store != nil {
+		data.StoreName = store.Name
+		data.StoreAddress = store.Address
+		data.StorePhone = store.Phone
+	}
+	if order.TableNumber != nil {
+		data.TableNumber = *order.TableNumber
+	}
+	if order.Voucher != nil {
+		data.VoucherCode = order.Voucher.Code
+	}
+	data.TotalPromoDiscount = order.TotalPromoDiscount
+
+	for _, item := range order.Items {
+		name := item.Product.Name
+		if name == "" {
+			name = "Produk tidak tersedia"
+		}
+		line := receiptItem{
+			Name:      name,
+			Qty:       item.Qty,
+			UnitPrice: item.UnitPrice,
+			Subtotal:  item.Subtotal,
+			Notes:     item.Notes,
+			Round:     item.Round,
+		}
+		for _, m := range item.Modifiers {
+			line.Modifiers = append(line.Modifiers, receiptModifier{Name: m.OptionName, PriceDelta: m.PriceDelta})
+		}
+		for _, c := range item.Components {
+			line.Components = append(line.Components, componentLabel(c))
+		}
+		if item.PromoDiscount > 0 {
+			line.PromoName = item.PromotionName
+			line.PromoDiscount = item.PromoDiscount
+		}
+		data.Items = append(data.Items, line)
+	}
+
+	for _, c := range order.Charges {
+		data.Charges = append(data.Charges, receiptCharge{
+			Name:        c.Name,
+			RateBps:     c.RateBps,
+			Amount:      c.Amount,
+			IsInclusive: c.IsInclusive,
+		})
+	}
+
+	for _, p := range order.Payments {
+		if p.PaymentStatus != core.PaymentStatusPaid {
+			continue
+		}
+		data.Payments = append(data.Payments, receiptPayment{
+			Method:         p.PaymentMethod,
+			Amount:         p.AmountPaid,
+			Rounding:       p.RoundingAdjustment,
+			AmountTendered: p.AmountTendered,
+			ChangeAmount:   p.ChangeAmount,
+			PaidAt:         p.PaidAt,
+		})
+	}
+
+	return data
+}
+
+// hasCashPayment menentukan apakah laci kasir perlu dibuka saat struk dicetak.
+func (d receiptData) hasCashPayment() bool {
+	for _, p := range d.Payments {
+		if p.Method == core.PaymentMethodCash {
+			return true
+		}
+	}
+	return false
+}
+
+// discountLabel contoh: "Diskon (HEMAT10)".
+func (d receiptData) discountLabel() string {
+	if d.VoucherCode == "" {
+		return "Diskon"
+	}
+	return "Diskon (" + d.VoucherCode + ")"
+}
+
+// voucherDiscount adalah bagian TotalDiscount di luar promo otomatis (voucher).
+func (d receiptData) voucherDiscount() int {
+	return d.TotalDiscount - d.TotalPromoDiscount
+}
+
+// itemsForRound mengembalikan item pada ronde tertentu (tab dine-in); round 0 = semua item.
+func (d receiptData) itemsForRound(round int) []receiptItem {
+	if round == 0 {
+		return d.Items
+	}
+	var items []receiptItem
+	for _, item := range d.Items {
+		if item.Round == round {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// exclusiveCharges & inclusiveCharges memisahkan baris yang menambah total dari yang hanya keterangan.
+func (d receiptData) exclusiveCharges() []receiptCharge {
+	var charges []receiptCharge
+	for _, c := range d.Charges {
+		if !c.IsInclusive {
+			charges = append(charges, c)
+		}
+	}
+	return charges
+}
+
+func (d receiptData) inclusiveCharges() []receiptCharge {
+	var charges []receiptCharge
+	for _, c := range d.Charges {
+		if c.IsInclusive {
+			charges = append(charges, c)
+		}
+	}
+	return charges
+}
+
+// formatRate memformat tarif basis poin sebagai persen, contoh 1000 → "10%", 550 → "5,5%".
+func formatRate(bps int) string {
+	whole := strconv.Itoa(bps / 100)
+	frac := strings.TrimRight(fmt.Sprintf("%02d", bps%100), "0")
+	if frac == "" {
+		return whole + "%"
+	}
+	return whole + "," + frac + "%"
+}
+
+// formatRupiah memformat nominal dengan pemisah ribuan titik, contoh 1250000 → "1.250.000".
+func formatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
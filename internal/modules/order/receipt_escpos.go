@@ -0,0 +1,119 @@
+package order
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Perintah ESC/POS yang dipakai (kompatibel dengan printer thermal Epson/Xprinter umum).
+var (
+	escposInit        = []byte{0x1B, 0x40}                   // ESC @ — reset printer
+	escposAlignLeft   = []byte{0x1B, 0x61, 0x00}             // ESC a 0
+	escposAlignCenter = []byte{0x1B, 0x61, 0x01}             // ESC a 1
+	escposBoldOn      = []byte{0x1B, 0x45, 0x01}             // ESC E 1
+	escposBoldOff     = []byte{0x1B, 0x45, 0x00}             // ESC E 0
+	escposDoubleOn    = []byte{0x1D, 0x21, 0x11}             // GS ! 0x11 — tinggi & lebar 2x
+	escposDoubleOff   = []byte{0x1D, 0x21, 0x00}             // GS ! 0
+	escposCut         = []byte{0x1D, 0x56, 0x42, 0x00}       // GS V 66 0 — feed lalu partial cut
+	escposKickDrawer  = []byte{0x1B, 0x70, 0x00, 0x19, 0xFA} // ESC p 0 — pulsa ke pin 2 laci kasir
+)
+
+// Jumlah kolom Font A per lebar kertas.
+const (
+	escposColumns58 = 32
+	escposColumns80 = 48
+)
+
+// escposWriter adalah receiptCanvas yang menghasilkan byte stream ESC/POS.
+type escposWriter struct {
+	buf   bytes.Buffer
+	cols  int
+	large bool
+}
+
+func newEscposWriter(paperWidth int) *escposWriter {
+	cols := escposColumns80
+	if paperWidth == 58 {
+		cols = escposColumns58
+	}
+	w := &escposWriter{cols: cols}
+	w.buf.Write(escposInit)
+	return w
+}
+
+func (w *escposWriter) raw(cmd []byte) {
+	w.buf.Write(cmd)
+}
+
+// width mengembalikan jumlah kolom efektif; huruf 2x lebar memakan dua kolom.
+func (w *escposWriter) width() int {
+	if w.large {
+		return w.cols / 2
+	}
+	return w.cols
+}
+
+func (w *escposWriter) line(text string) {
+	for _, part := range layoutLines(text, w.width()) {
+		w.buf.WriteString(part + "\n")
+	}
+}
+
+func (w *escposWriter) columns(left, right string) {
+	for _, part := range layoutColumns(left, right, w.width()) {
+		w.buf.WriteString(part + "\n")
+	}
+}
+
+func (w *escposWriter) separator() {
+	w.buf.WriteString(strings.Repeat("-", w.width()) + "\n")
+}
+
+func (w *escposWriter) setBold(on bool) {
+	if on {
+		w.raw(escposBoldOn)
+		return
+	}
+	w.raw(escposBoldOff)
+}
+
+func (w *escposWriter) setCenter(on bool) {
+	if on {
+		w.raw(escposAlignCenter)
+		return
+	}
+	w.raw(escposAlignLeft)
+}
+
+func (w *escposWriter) setLarge(on bool) {
+	w.large = on
+	if on {
+		w.raw(escposDoubleOn)
+		return
+	}
+	w.raw(escposDoubleOff)
+}
+
+func (w *escposWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+// renderReceiptESCPOS mencetak struk pelanggan lalu memotong kertas.
+// kickDrawer membuka laci kasir setelah struk dicetak.
+func renderReceiptESCPOS(data receiptData, paperWidth int, kickDrawer bool) []byte {
+	w := newEscposWriter(paperWidth)
+	layoutReceipt(w, data)
+	w.raw(escposCut)
+	if kickDrawer {
+		w.raw(escposKickDrawer)
+	}
+	return w.bytes()
+}
+
+// renderKitchenTicketESCPOS mencetak tiket dapur lalu memotong kertas.
+func renderKitchenTicketESCPOS(data receiptData, paperWidth int, round int) []byte {
+	w := newEscposWriter(paperWidth)
+	layoutKitchenTicket(w, data, round)
+	w.raw(escposCut)
+	return w.bytes()
+}
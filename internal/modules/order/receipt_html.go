@@ -0,0 +1,101 @@
+package order
+
+import (
+	"bytes"
+	"html/template"
+	"time"
+)
+
+// receiptHTMLTemplate adalah struk digital untuk email/unduhan. CSS ditulis inline
+// karena sebagian besar klien email mengabaikan stylesheet eksternal.
+var receiptHTMLTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"rupiah": formatRupiah,
+	"neg":    func(v int) int { return -v },
+	"datetime": func(t time.Time) string {
+		return t.Local().Format("02/01/2006 15:04")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Struk {{.QueueNumber}}{{if .StoreName}} - {{.StoreName}}{{end}}</title>
+</head>
+<body style="margin:0;padding:16px;background:#f4f4f4;font-family:Arial,Helvetica,sans-serif;color:#222;">
+<div style="max-width:420px;margin:0 auto;background:#fff;padding:20px;border-radius:6px;">
+  <div style="text-align:center;">
+    {{if .StoreName}}<h2 style="margin:0 0 4px;">{{.StoreName}}</h2>{{end}}
+    {{if .StoreAddress}}<div style="font-size:13px;">{{.StoreAddress}}</div>{{end}}
+    {{if .StorePhone}}<div style="font-size:13px;">Telp. {{.StorePhone}}</div>{{end}}
+  </div>
+  <hr style="border:none;border-top:1px dashed #999;margin:12px 0;">
+  <table style="width:100%;font-size:13px;border-collapse:collapse;">
+    <tr><td>No. Antrean</td><td style="text-align:right;font-weight:bold;">{{.QueueNumber}}</td></tr>
+    {{if .TableNumber}}<tr><td>Meja</td><td style="text-align:right;">{{.TableNumber}}</td></tr>{{end}}
+    <tr><td>Tanggal</td><td style="text-align:right;">{{datetime .CreatedAt}}</td></tr>
+  </table>
+  <hr style="border:none;border-top:1px dashed #999;margin:12px 0;">
+  <table style="width:100%;font-size:13px;border-collapse:collapse;">
+    {{range .Items}}
+    <tr><td colspan="2" style="padding-top:6px;">{{.Name}}</td></tr>
+    {{range .Components}}<tr><td colspan="2" style="color:#555;">&nbsp;&nbsp;- {{.}}</td></tr>{{end}}
+    {{range .Modifiers}}<tr><td colspan="2" style="color:#555;">&nbsp;&nbsp;+ {{.Label}}</td></tr>{{end}}
+    <tr>
+      <td style="color:#555;">{{.Qty}} x {{rupiah .UnitPrice}}</td>
+      <td style="text-align:right;">{{rupiah .Subtotal}}</td>
+    </tr>
+    {{if gt .PromoDiscount 0}}<tr style="color:#555;"><td>&nbsp;&nbsp;Promo {{.PromoName}}</td><td style="text-align:right;">{{rupiah (neg .PromoDiscount)}}</td></tr>{{end}}
+    {{if .Notes}}<tr><td colspan="2" style="color:#777;font-style:italic;">* {{.Notes}}</td></tr>{{end}}
+    {{end}}
+  </table>
+  <hr style="border:none;border-top:1px dashed #999;margin:12px 0;">
+  <table style="width:100%;font-size:13px;border-collapse:collapse;">
+    <tr><td>Subtotal</td><td style="text-align:right;">{{rupiah .TotalBasePrice}}</td></tr>
+    {{if gt .TotalPromoDiscount 0}}<tr><td>Promo</td><td style="text-align:right;">{{rupiah (neg .TotalPromoDiscount)}}</td></tr>{{end}}
+    {{if gt .VoucherDiscount 0}}<tr><td>{{.DiscountLabel}}</td><td style="text-align:right;">{{rupiah (neg .VoucherDiscount)}}</td></tr>{{end}}
+    {{range .ExclusiveCharges}}<tr><td>{{.Label}}</td><td style="text-align:right;">{{rupiah .Amount}}</td></tr>{{end}}
+    {{if gt .PlatformFee 0}}<tr><td>Biaya layanan</td><td style="text-align:right;">{{rupiah .PlatformFee}}</td></tr>{{end}}
+    <tr style="font-weight:bold;font-size:15px;"><td style="padding-top:6px;">TOTAL</td><td style="text-align:right;padding-top:6px;">Rp {{rupiah .TotalFinalAmount}}</td></tr>
+    {{range .InclusiveCharges}}<tr style="color:#555;"><td>{{.Label}}</td><td style="text-align:right;">{{rupiah .Amount}}</td></tr>{{end}}
+    {{range .Payments}}
+    <tr><td style="padding-top:6px;">{{.Method}}</td><td style="text-align:right;padding-top:6px;">{{rupiah .Amount}}</td></tr>
+    {{if ne .Rounding 0}}<tr style="color:#555;"><td>&nbsp;&nbsp;Pembulatan</td><td style="text-align:right;">{{rupiah .Rounding}}</td></tr>{{end}}
+    {{if gt .AmountTendered 0}}
+    <tr style="color:#555;"><td>&nbsp;&nbsp;Tunai</td><td style="text-align:right;">{{rupiah .AmountTendered}}</td></tr>
+    <tr style="color:#555;"><td>&nbsp;&nbsp;Kembali</td><td style="text-align:right;">{{rupiah .ChangeAmount}}</td></tr>
+    {{end}}
+    {{end}}
+    {{if gt .OutstandingAmount 0}}<tr style="color:#b00;"><td>Sisa tagihan</td><td style="text-align:right;">{{rupiah .OutstandingAmount}}</td></tr>{{end}}
+    {{if gt .TotalRefunded 0}}<tr><td>Dikembalikan</td><td style="text-align:right;">{{rupiah (neg .TotalRefunded)}}</td></tr>{{end}}
+  </table>
+  <hr style="border:none;border-top:1px dashed #999;margin:12px 0;">
+  <div style="text-align:center;font-size:13px;">Terima kasih atas kunjungan Anda</div>
+</div>
+</body>
+</html>
+`))
+
+// receiptHTMLView menambahkan nilai turunan yang dibutuhkan template.
+type receiptHTMLView struct {
+	receiptData
+	DiscountLabel    string
+	VoucherDiscount  int
+	ExclusiveCharges []receiptCharge
+	InclusiveCharges []receiptCharge
+}
+
+// renderReceiptHTML merender struk pelanggan sebagai halaman HTML (email/unduhan).
+func renderReceiptHTML(data receiptData) ([]byte, error) {
+	var buf bytes.Buffer
+	view := receiptHTMLView{
+		receiptData:      data,
+		DiscountLabel:    data.discountLabel(),
+		VoucherDiscount:  data.voucherDiscount(),
+		ExclusiveCharges: data.exclusiveCharges(),
+		InclusiveCharges: data.inclusiveCharges(),
+	}
+	if err := receiptHTMLTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
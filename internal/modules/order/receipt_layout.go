@@ -0,0 +1,212 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+)
+
+// receiptCanvas adalah permukaan cetak teks monospace berbasis kolom. Isi struk disusun sekali
+// (layoutReceipt / layoutKitchenTicket) lalu dicetak oleh ESC/POS maupun PDF.
+type receiptCanvas interface {
+	line(text string)
+	columns(left, right string)
+	separator()
+	setBold(on bool)
+	setCenter(on bool)
+	setLarge(on bool) // Huruf 2x lebar & tinggi (nomor antrean di tiket dapur)
+}
+
+// layoutReceipt menyusun struk pelanggan: header toko, item, voucher, fee, dan pembayaran.
+func layoutReceipt(c receiptCanvas, data receiptData) {
+	c.setCenter(true)
+	if data.StoreName != "" {
+		c.setBold(true)
+		c.line(data.StoreName)
+		c.setBold(false)
+	}
+	if data.StoreAddress != "" {
+		c.line(data.StoreAddress)
+	}
+	if data.StorePhone != "" {
+		c.line("Telp. " + data.StorePhone)
+	}
+	c.setCenter(false)
+	c.separator()
+
+	c.columns("No. Antrean", data.QueueNumber)
+	if data.TableNumber != "" {
+		c.columns("Meja", data.TableNumber)
+	}
+	c.columns("Tanggal", data.CreatedAt.Local().Format("02/01/2006 15:04"))
+	c.separator()
+
+	for _, item := range data.Items {
+		c.line(item.Name)
+		for _, component := range item.Components {
+			c.line("  - " + component)
+		}
+		for _, m := range item.Modifiers {
+			c.line("  + " + m.Label())
+		}
+		c.columns(fmt.Sprintf("  %d x %s", item.Qty, formatRupiah(item.UnitPrice)), formatRupiah(item.Subtotal))
+		if item.PromoDiscount > 0 {
+			c.columns("  Promo "+item.PromoName, formatRupiah(-item.PromoDiscount))
+		}
+		if item.Notes != "" {
+			c.line("  * " + item.Notes)
+		}
+	}
+	c.separator()
+
+	c.columns("Subtotal", formatRupiah(data.TotalBasePrice))
+	if data.TotalPromoDiscount > 0 {
+		c.columns("Promo", formatRupiah(-data.TotalPromoDiscount))
+	}
+	if voucher := data.voucherDiscount(); voucher > 0 {
+		c.columns(data.discountLabel(), formatRupiah(-voucher))
+	}
+	for _, charge := range data.exclusiveCharges() {
+		c.columns(charge.Label(), formatRupiah(charge.Amount))
+	}
+	if data.PlatformFee > 0 {
+		c.columns("Biaya layanan", formatRupiah(data.PlatformFee))
+	}
+	c.setBold(true)
+	c.columns("TOTAL", formatRupiah(data.TotalFinalAmount))
+	c.setBold(false)
+	for _, charge := range data.inclusiveCharges() {
+		c.columns(charge.Label(), formatRupiah(charge.Amount))
+	}
+
+	if len(data.Payments) > 0 {
+		c.separator()
+		for _, p := range data.Payments {
+			c.columns(p.Method, formatRupiah(p.Amount))
+			if p.Rounding != 0 {
+				c.columns("  Pembulatan", formatRupiah(p.Rounding))
+			}
+			if p.AmountTendered > 0 {
+				c.columns("  Tunai", formatRupiah(p.AmountTendered))
+				c.columns("  Kembali", formatRupiah(p.ChangeAmount))
+			}
+		}
+	}
+	if data.OutstandingAmount > 0 {
+		c.columns("Sisa tagihan", formatRupiah(data.OutstandingAmount))
+	}
+	if data.TotalRefunded > 0 {
+		c.columns("Dikembalikan", formatRupiah(-data.TotalRefunded))
+	}
+
+	c.separator()
+	c.setCenter(true)
+	c.line("Terima kasih atas kunjungan Anda")
+	c.setCenter(false)
+}
+
+// layoutKitchenTicket menyusun tiket dapur: nomor antrean besar, meja, dan item tanpa harga.
+// round > 0 hanya mencetak ronde tersebut (tab dine-in).
+func layoutKitchenTicket(c receiptCanvas, data receiptData, round int) {
+	c.setCenter(true)
+	c.setLarge(true)
+	c.line(data.QueueNumber)
+	c.setLarge(false)
+	if data.TableNumber != "" {
+		c.setBold(true)
+		c.line("MEJA " + data.TableNumber)
+		c.setBold(false)
+	}
+	c.line(data.OrderSource + " - " + data.CreatedAt.Local().Format("15:04"))
+	if round > 0 {
+		c.line(fmt.Sprintf("Ronde %d", round))
+	}
+	c.setCenter(false)
+	c.separator()
+
+	for _, item := range data.itemsForRound(round) {
+		c.setBold(true)
+		c.line(fmt.Sprintf("%dx %s", item.Qty, item.Name))
+		c.setBold(false)
+		for _, component := range item.Components {
+			c.line("   - " + component)
+		}
+		for _, m := range item.Modifiers {
+			c.line("   + " + m.Name)
+		}
+		if item.Notes != "" {
+			c.line("   * " + item.Notes)
+		}
+	}
+	c.separator()
+}
+
+// layoutLines memecah teks menjadi baris selebar cols; indentasi awal dipertahankan di setiap
+// baris lanjutan. Karakter non-ASCII diganti "?" karena code page printer/font PDF standar terbatas.
+func layoutLines(text string, cols int) []string {
+	text = toPrintable(text)
+	trimmed := strings.TrimLeft(text, " ")
+	indent := text[:len(text)-len(trimmed)]
+
+	parts := wrapText(trimmed, cols-len(indent))
+	for i := range parts {
+		parts[i] = indent + parts[i]
+	}
+	return parts
+}
+
+// layoutColumns menyusun teks kiri dan kanan pada satu baris selebar cols
+// (contoh: "Subtotal        50.000"). Jika tidak muat, teks kiri turun ke baris sendiri.
+func layoutColumns(left, right string, cols int) []string {
+	left, right = toPrintable(left), toPrintable(right)
+	space := cols - len(left) - len(right)
+	if space >= 1 {
+		return []string{left + strings.Repeat(" ", space) + right}
+	}
+	lines := layoutLines(left, cols)
+	return append(lines, strings.Repeat(" ", max(cols-len(right), 0))+right)
+}
+
+// toPrintable mengganti karakter di luar ASCII cetak dengan "?".
+func toPrintable(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return '?'
+		}
+		return r
+	}, text)
+}
+
+// wrapText memecah teks per kata agar muat di width kolom; kata yang lebih panjang dipotong paksa.
+func wrapText(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 || width <= 0 {
+		return []string{text}
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		for len(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			lines = append(lines, word[:width])
+			word = word[width:]
+		}
+		switch {
+		case word == "":
+		case current == "":
+			current = word
+		case len(current)+1+len(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
@@ -0,0 +1,159 @@
+package order
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ukuran halaman PDF struk: selebar kertas thermal 80mm, tinggi mengikuti isi.
+// Font Courier (monospace bawaan PDF, tanpa embed) selebar 0,6 em per karakter.
+const (
+	pdfPageWidth     = 226.77 // 80mm dalam point
+	pdfMargin        = 12.0
+	pdfFontSize      = 8.0
+	pdfLargeFontSize = 16.0
+	pdfLeading       = 11.0
+	pdfLargeLeading  = 20.0
+	pdfCharWidth     = 0.6
+	pdfColumns       = 42 // floor((pdfPageWidth - 2*pdfMargin) / (pdfFontSize * pdfCharWidth))
+)
+
+type pdfLine struct {
+	text   string
+	bold   bool
+	center bool
+	large  bool
+}
+
+// pdfCanvas adalah receiptCanvas yang mengumpulkan baris lalu menulisnya sebagai dokumen PDF satu halaman.
+type pdfCanvas struct {
+	lines  []pdfLine
+	bold   bool
+	center bool
+	large  bool
+}
+
+func (p *pdfCanvas) width() int {
+	if p.large {
+		return pdfColumns / 2
+	}
+	return pdfColumns
+}
+
+func (p *pdfCanvas) add(parts []string) {
+	for _, text := range parts {
+		p.lines = append(p.lines, pdfLine{text: text, bold: p.bold, center: p.center, large: p.large})
+	}
+}
+
+func (p *pdfCanvas) line(text string) {
+	p.add(layoutLines(text, p.width()))
+}
+
+func (p *pdfCanvas) columns(left, right string) {
+	p.add(layoutColumns(left, right, p.width()))
+}
+
+func (p *pdfCanvas) separator() {
+	p.add([]string{strings.Repeat("-", p.width())})
+}
+
+func (p *pdfCanvas) setBold(on bool)   { p.bold = on }
+func (p *pdfCanvas) setCenter(on bool) { p.center = on }
+func (p *pdfCanvas) setLarge(on bool)  { p.large = on }
+
+// contentStream menulis operator teks PDF per baris dari atas ke bawah halaman setinggi height.
+func (p *pdfCanvas) contentStream(height float64) []byte {
+	var buf bytes.Buffer
+	y := height - pdfMargin
+	for _, l := range p.lines {
+		size, leading := pdfFontSize, pdfLeading
+		if l.large {
+			size, leading = pdfLargeFontSize, pdfLargeLeading
+		}
+		y -= leading
+
+		x := pdfMargin
+		if l.center {
+			textWidth := float64(len(strings.TrimRight(l.text, " "))) * size * pdfCharWidth
+			x = (pdfPageWidth - textWidth) / 2
+		}
+		font := "F1"
+		if l.bold || l.large {
+			font = "F2"
+		}
+		fmt.Fprintf(&buf, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+			font, pdfNumber(size), pdfNumber(x), pdfNumber(y), pdfEscape(l.text))
+	}
+	return buf.Bytes()
+}
+
+func (p *pdfCanvas) height() float64 {
+	h := 2 * pdfMargin
+	for _, l := range p.lines {
+		if l.large {
+			h += pdfLargeLeading
+		} else {
+			h += pdfLeading
+		}
+	}
+	return h
+}
+
+// bytes menyusun dokumen PDF 1.4 minimal: catalog, pages, satu page, dua font standar, dan content stream.
+func (p *pdfCanvas) bytes() []byte {
+	height := p.height()
+	content := p.contentStream(height)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>",
+			pdfNumber(pdfPageWidth), pdfNumber(height)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfEscape meng-escape karakter khusus string literal PDF.
+func pdfEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(text)
+}
+
+func pdfNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// renderReceiptPDF merender struk pelanggan sebagai PDF untuk diunduh atau dilampirkan di email.
+func renderReceiptPDF(data receiptData) []byte {
+	p := &pdfCanvas{}
+	layoutReceipt(p, data)
+	return p.bytes()
+}
+
+// renderKitchenTicketPDF merender tiket dapur sebagai PDF (untuk printer non-thermal).
+func renderKitchenTicketPDF(data receiptData, round int) []byte {
+	p := &pdfCanvas{}
+	layoutKitchenTicket(p, data, round)
+	return p.bytes()
+}
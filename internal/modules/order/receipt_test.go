@@ -0,0 +1,469 @@
+package order_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"go-fiber-pos/internal/modules/order"
+	"go-fiber-pos/internal/modules/order/mocks"
+
+	"github.com/google/uuid"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// receiptOrder adalah order lunas tunai dengan promo, voucher, service charge & PB1 eksklusif,
+// PPN inklusif, platform fee, dan pembulatan kas:
+//
+//	Subtotal 70.000 - promo 5.000 - voucher 6.500 = 58.500
+//	+ Service 5% 2.925 + PB1 10% 6.143 + biaya layanan 1.000 = 68.568 → dibulatkan 68.600 (+32)
+//	Tunai 100.000, kembali 31.400
+func receiptOrder() *core.Order {
+	paidAt := time.Date(2026, 1, 2, 12, 30, 0, 0, time.Local)
+	orderID := uuid.New()
+	voucherID := uuid.New()
+	return &core.Order{
+		ID:                 orderID,
+		QueueNumber:        "K-021",
+		OrderSource:        core.OrderSourceCashier,
+		OrderStatus:        core.OrderStatusCompleted,
+		PaymentStatus:      core.PaymentStatusPaid,
+		VoucherID:          &voucherID,
+		Voucher:            &core.Voucher{ID: voucherID, Code: "HEMAT10"},
+		TotalBasePrice:     70000,
+		TotalPromoDiscount: 5000,
+		TotalDiscount:      11500,
+		TotalServiceCharge: 2925,
+		TotalTax:           11940,
+		TotalTaxIncluded:   5797,
+		PlatformFee:        1000,
+		TotalFinalAmount:   68568,
+		TotalPaid:          68568,
+		CreatedAt:          paidAt.Add(-20 * time.Minute),
+		Items: []core.OrderItem{
+			{
+				ID: uuid.New(), Product: core.Product{Name: "Kopi Susu"}, Qty: 2, UnitPrice: 25000, Subtotal: 50000, Round: 1,
+				Notes:         "Es sedikit",
+				PromotionName: "Happy Hour", PromoDiscount: 5000,
+				Modifiers: []core.OrderItemModifier{{OptionName: "Less Sugar"}},
+			},
+			{ID: uuid.New(), Product: core.Product{Name: "Croissant"}, Qty: 1, UnitPrice: 20000, Subtotal: 20000, Round: 2},
+		},
+		Charges: []core.OrderCharge{
+			{Kind: core.ChargeKindService, Name: "Service", RateBps: 500, Amount: 2925},
+			{Kind: core.ChargeKindTax, Name: "PB1", RateBps: 1000, Amount: 6143},
+			{Kind: core.ChargeKindTax, Name: "PPN", RateBps: 1100, IsInclusive: true, Amount: 5797},
+		},
+		Payments: []core.Payment{
+			{
+				ID: uuid.New(), OrderID: orderID, PaymentMethod: core.PaymentMethodCash, PaymentStatus: core.PaymentStatusPaid,
+				AmountPaid: 68568, RoundingAdjustment: 32, AmountTendered: 100000, ChangeAmount: 31400, PaidAt: &paidAt,
+			},
+			// Link QRIS yang di-void tidak ikut dicetak
+			{ID: uuid.New(), OrderID: orderID, PaymentMethod: core.PaymentMethodQRIS, PaymentStatus: core.PaymentStatusFailed, AmountPaid: 68568},
+		},
+	}
+}
+
+func receiptStore() *core.StoreProfile {
+	return &core.StoreProfile{Name: "Kopi & Roti", Address: "Jl. Merdeka 1", Phone: "0211234567"}
+}
+
+// escposCommands adalah perintah ESC/POS yang dibuang sebelum teks struk dibandingkan.
+var escposCommands = [][]byte{
+	{0x1B, 0x40},
+	{0x1B, 0x61, 0x00}, {0x1B, 0x61, 0x01},
+	{0x1B, 0x45, 0x00}, {0x1B, 0x45, 0x01},
+	{0x1D, 0x21, 0x00}, {0x1D, 0x21, 0x11},
+	{0x1D, 0x56, 0x42, 0x00},
+	{0x1B, 0x70, 0x00, 0x19, 0xFA},
+}
+
+var escposKickDrawer = []byte{0x1B, 0x70, 0x00, 0x19, 0xFA}
+
+// printedLines mengembalikan baris teks yang tercetak dari byte stream ESC/POS.
+func printedLines(body []byte) []string {
+	for _, cmd := range escposCommands {
+		body = bytes.ReplaceAll(body, cmd, nil)
+	}
+	return strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+}
+
+// assertColumns memastikan ada baris "left ... right" selebar width dan mengembalikan indeksnya.
+func assertColumns(t *testing.T, lines []string, left, right string, width int) int {
+	t.Helper()
+	for i, line := range lines {
+		if len(line) == width && strings.HasPrefix(line, left+" ") && strings.HasSuffix(line, " "+right) &&
+			strings.TrimSpace(line[len(left):len(line)-len(right)]) == "" {
+			return i
+		}
+	}
+	t.Errorf("baris %q ... %q (lebar %d) tidak ditemukan di:\n%s", left, right, width, strings.Join(lines, "\n"))
+	return -1
+}
+
+func newReceiptService(t *testing.T, o *core.Order) order.OrderService {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockRepo.EXPECT().FindByID(o.ID).Return(o, nil).Times(1)
+	mockRepo.EXPECT().GetStoreProfile().Return(receiptStore(), nil).Times(1)
+	return order.NewOrderService(mockRepo, validator.New(), nil)
+}
+
+func TestRenderReceipt_ESCPOS(t *testing.T) {
+	testCases := []struct {
+		name  string
+		width int
+		cols  int
+	}{
+		{name: "Sukses - Kertas 80mm", width: 80, cols: 48},
+		{name: "Sukses - Kertas 58mm", width: 58, cols: 32},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := receiptOrder()
+			service := newReceiptService(t, o)
+
+			receipt, err := service.RenderReceipt(o.ID, order.ReceiptQuery{Format: order.ReceiptFormatESCPOS, Width: tc.width})
+			assert.NoError(t, err)
+			assert.Equal(t, "application/octet-stream", receipt.ContentType)
+			assert.Equal(t, "receipt-K-021.bin", receipt.FileName)
+
+			// Ada pembayaran tunai: laci kasir dibuka setelah kertas dipotong
+			assert.True(t, bytes.HasSuffix(receipt.Body, escposKickDrawer))
+
+			lines := printedLines(receipt.Body)
+			assert.Contains(t, lines, "Kopi & Roti")
+			assert.Contains(t, lines, "  + Less Sugar")
+			assert.Contains(t, lines, "  * Es sedikit")
+			assertColumns(t, lines, "  2 x 25.000", "50.000", tc.cols)
+			assertColumns(t, lines, "  Promo Happy Hour", "-5.000", tc.cols)
+
+			subtotal := assertColumns(t, lines, "Subtotal", "70.000", tc.cols)
+			promo := assertColumns(t, lines, "Promo", "-5.000", tc.cols)
+			voucher := assertColumns(t, lines, "Diskon (HEMAT10)", "-6.500", tc.cols)
+			serviceCharge := assertColumns(t, lines, "Service 5%", "2.925", tc.cols)
+			pb1 := assertColumns(t, lines, "PB1 10%", "6.143", tc.cols)
+			fee := assertColumns(t, lines, "Biaya layanan", "1.000", tc.cols)
+			total := assertColumns(t, lines, "TOTAL", "68.568", tc.cols)
+			ppn := assertColumns(t, lines, "Termasuk PPN 11%", "5.797", tc.cols)
+			cash := assertColumns(t, lines, "CASH", "68.568", tc.cols)
+			rounding := assertColumns(t, lines, "  Pembulatan", "32", tc.cols)
+			tendered := assertColumns(t, lines, "  Tunai", "100.000", tc.cols)
+			change := assertColumns(t, lines, "  Kembali", "31.400", tc.cols)
+
+			// Charge eksklusif menambah total (sebelum TOTAL), pajak inklusif hanya keterangan (sesudahnya)
+			assert.Equal(t, []int{subtotal, promo, voucher, serviceCharge, pb1, fee, total, ppn, cash, rounding, tendered, change},
+				[]int{subtotal, subtotal + 1, subtotal + 2, subtotal + 3, subtotal + 4, subtotal + 5, subtotal + 6, subtotal + 7, subtotal + 9, subtotal + 10, subtotal + 11, subtotal + 12})
+			assert.NotContains(t, string(receipt.Body), "QRIS")
+			for _, line := range lines {
+				assert.LessOrEqual(t, len(line), tc.cols)
+			}
+		})
+	}
+}
+
+func TestRenderReceipt_ESCPOSDrawer(t *testing.T) {
+	closed := false
+
+	o := receiptOrder()
+	service := newReceiptService(t, o)
+
+	receipt, err := service.RenderReceipt(o.ID, order.ReceiptQuery{Format: order.ReceiptFormatESCPOS, Drawer: &closed})
+
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(receipt.Body, escposKickDrawer))
+}
+
+func TestRenderReceipt_KitchenTicket(t *testing.T) {
+	testCases := []struct {
+		name          string
+		round         int
+		expectedItems []string
+		missingItems  []string
+	}{
+		{
+			name:          "Sukses - Semua ronde",
+			expectedItems: []string{"2x Kopi Susu", "1x Croissant"},
+		},
+		{
+			name:          "Sukses - Hanya ronde tab yang diminta",
+			round:         2,
+			expectedItems: []string{"Ronde 2", "1x Croissant"},
+			missingItems:  []string{"2x Kopi Susu"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := receiptOrder()
+			service := newReceiptService(t, o)
+
+			receipt, err := service.RenderReceipt(o.ID, order.ReceiptQuery{Format: order.ReceiptFormatESCPOS, Type: order.ReceiptTypeKitchen, Round: tc.round})
+			assert.NoError(t, err)
+			assert.Equal(t, "kitchen-K-021.bin", receipt.FileName)
+
+			lines := printedLines(receipt.Body)
+			assert.Contains(t, lines, "K-021")
+			for _, item := range tc.expectedItems {
+				assert.Contains(t, lines, item)
+			}
+			for _, item := range tc.missingItems {
+				assert.NotContains(t, lines, item)
+			}
+
+			// Tiket dapur tanpa harga, charge, maupun pembayaran — dan laci kasir tidak dibuka
+			body := string(receipt.Body)
+			for _, text := range []string{"25.000", "TOTAL", "PB1", "Kembali"} {
+				assert.NotContains(t, body, text)
+			}
+			assert.False(t, bytes.Contains(receipt.Body, escposKickDrawer))
+		})
+	}
+}
+
+// pdfTextLine mengambil string literal dari operator "(...) Tj" di content stream PDF.
+var pdfTextLine = regexp.MustCompile(`\((.*)\) Tj ET`)
+
+// pdfLines mengembalikan baris teks yang tercetak di PDF struk (escape string literal dibuka).
+func pdfLines(body []byte) []string {
+	unescape := strings.NewReplacer(`\\`, `\`, `\(`, "(", `\)`, ")")
+	var lines []string
+	for _, match := range pdfTextLine.FindAllSubmatch(body, -1) {
+		lines = append(lines, unescape.Replace(string(match[1])))
+	}
+	return lines
+}
+
+// htmlRow adalah baris ringkasan di struk HTML, contoh <td>Subtotal</td><td ...>70.000</td>.
+func htmlRow(label, amount string) string {
+	return "<td>" + label + `</td><td style="text-align:right;">` + amount + "</td>"
+}
+
+func TestRenderReceipt_HTML(t *testing.T) {
+	o := receiptOrder()
+	service := newReceiptService(t, o)
+
+	receipt, err := service.RenderReceipt(o.ID, order.ReceiptQuery{Format: order.ReceiptFormatHTML})
+	assert.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", receipt.ContentType)
+	assert.Equal(t, "receipt-K-021.html", receipt.FileName)
+
+	body := string(receipt.Body)
+	// html/template meng-escape nama toko
+	assert.Contains(t, body, "Kopi &amp; Roti")
+	assert.Contains(t, body, "&nbsp;&nbsp;+ Less Sugar")
+	assert.Contains(t, body, "&nbsp;&nbsp;Promo Happy Hour")
+
+	rows := []string{
+		htmlRow("Subtotal", "70.000"),
+		htmlRow("Promo", "-5.000"),
+		htmlRow("Diskon (HEMAT10)", "-6.500"),
+		htmlRow("Service 5%", "2.925"),
+		htmlRow("PB1 10%", "6.143"),
+		htmlRow("Biaya layanan", "1.000"),
+		"Rp 68.568",
+		htmlRow("Termasuk PPN 11%", "5.797"),
+		htmlRow("&nbsp;&nbsp;Pembulatan", "32"),
+		htmlRow("&nbsp;&nbsp;Tunai", "100.000"),
+		htmlRow("&nbsp;&nbsp;Kembali", "31.400"),
+	}
+	// Urutan sama dengan struk ESC/POS: charge eksklusif sebelum TOTAL, pajak inklusif sesudahnya
+	last := -1
+	for _, row := range rows {
+		idx := strings.Index(body, row)
+		if assert.NotEqual(t, -1, idx, "baris %q tidak ditemukan", row) {
+			assert.Greater(t, idx, last, "baris %q tidak berurutan", row)
+			last = idx
+		}
+	}
+	assert.NotContains(t, body, "QRIS")
+	assert.NotContains(t, body, "Sisa tagihan")
+}
+
+func TestRenderReceipt_PDF(t *testing.T) {
+	testCases := []struct {
+		name          string
+		receiptType   string
+		fileName      string
+		expectedLines []string
+		missingText   []string
+	}{
+		{
+			name:     "Sukses - Struk pelanggan",
+			fileName: "receipt-K-021.pdf",
+			expectedLines: []string{
+				"Kopi & Roti",
+				"Subtotal                            70.000",
+				"Promo                               -5.000",
+				"Diskon (HEMAT10)                    -6.500",
+				"Service 5%                           2.925",
+				"PB1 10%                              6.143",
+				"Biaya layanan                        1.000",
+				"TOTAL                               68.568",
+				"Termasuk PPN 11%                     5.797",
+				"CASH                                68.568",
+				"  Pembulatan                            32",
+				"  Tunai                            100.000",
+				"  Kembali                           31.400",
+			},
+		},
+		{
+			name:          "Sukses - Tiket dapur tanpa harga",
+			receiptType:   order.ReceiptTypeKitchen,
+			fileName:      "kitchen-K-021.pdf",
+			expectedLines: []string{"K-021", "2x Kopi Susu", "   + Less Sugar", "1x Croissant"},
+			missingText:   []string{"25.000", "TOTAL", "Kembali"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := receiptOrder()
+			service := newReceiptService(t, o)
+
+			receipt, err := service.RenderReceipt(o.ID, order.ReceiptQuery{Format: order.ReceiptFormatPDF, Type: tc.receiptType})
+			assert.NoError(t, err)
+			assert.Equal(t, "application/pdf", receipt.ContentType)
+			assert.Equal(t, tc.fileName, receipt.FileName)
+			assert.True(t, bytes.HasPrefix(receipt.Body, []byte("%PDF-1.4\n")))
+			assert.True(t, bytes.HasSuffix(receipt.Body, []byte("%%EOF\n")))
+
+			lines := pdfLines(receipt.Body)
+			for _, line := range tc.expectedLines {
+				assert.Contains(t, lines, line)
+			}
+			for _, text := range tc.missingText {
+				assert.NotContains(t, string(receipt.Body), text)
+			}
+			// Kurung di label voucher di-escape agar string literal PDF tetap valid
+			if tc.receiptType == "" {
+				assert.Contains(t, string(receipt.Body), `(Diskon \(HEMAT10\)`)
+			}
+		})
+	}
+}
+
+func TestRenderReceipt_HTMLKitchenTicket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockRepo.EXPECT().FindByID(gomock.Any()).Times(0)
+	service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+	receipt, err := service.RenderReceipt(uuid.New(), order.ReceiptQuery{Format: order.ReceiptFormatHTML, Type: order.ReceiptTypeKitchen})
+
+	assert.Nil(t, receipt)
+	assert.ErrorIs(t, err, core.ErrReceiptFormat)
+}
+
+func TestRenderPublicReceipt_Gomock(t *testing.T) {
+	o := receiptOrder()
+	o.TrackingToken = "trk-abc123"
+
+	testCases := []struct {
+		name          string
+		token         string
+		query         order.PublicReceiptQuery
+		buildStubs    func(repo *mocks.MockOrderRepository)
+		expectedType  string
+		expectedError error
+		checkBody     func(t *testing.T, body []byte)
+	}{
+		{
+			name:  "Sukses - Default HTML lewat tracking token",
+			token: "trk-abc123",
+			buildStubs: func(repo *mocks.MockOrderRepository) {
+				repo.EXPECT().FindByTrackingToken("trk-abc123").Return(o, nil).Times(1)
+				repo.EXPECT().FindByID(o.ID).Return(o, nil).Times(1)
+				repo.EXPECT().GetStoreProfile().Return(receiptStore(), nil).Times(1)
+			},
+			expectedType: "text/html; charset=utf-8",
+			checkBody: func(t *testing.T, body []byte) {
+				assert.Contains(t, string(body), htmlRow("Termasuk PPN 11%", "5.797"))
+				assert.Contains(t, string(body), htmlRow("&nbsp;&nbsp;Pembulatan", "32"))
+				assert.Contains(t, string(body), htmlRow("&nbsp;&nbsp;Kembali", "31.400"))
+				// ID internal order tidak pernah terekspos ke pelanggan
+				assert.NotContains(t, string(body), o.ID.String())
+			},
+		},
+		{
+			name:  "Sukses - PDF tanpa profil toko",
+			token: "trk-abc123",
+			query: order.PublicReceiptQuery{Format: order.ReceiptFormatPDF},
+			buildStubs: func(repo *mocks.MockOrderRepository) {
+				repo.EXPECT().FindByTrackingToken("trk-abc123").Return(o, nil).Times(1)
+				repo.EXPECT().FindByID(o.ID).Return(o, nil).Times(1)
+				repo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).Times(1)
+			},
+			expectedType: "application/pdf",
+			checkBody: func(t *testing.T, body []byte) {
+				lines := pdfLines(body)
+				assert.NotContains(t, lines, "Kopi & Roti")
+				assert.Contains(t, lines, "TOTAL                               68.568")
+				assert.Contains(t, lines, "  Kembali                           31.400")
+			},
+		},
+		{
+			name:  "Gagal - Token tidak dikenal",
+			token: "trk-unknown",
+			buildStubs: func(repo *mocks.MockOrderRepository) {
+				repo.EXPECT().FindByTrackingToken("trk-unknown").Return(nil, gorm.ErrRecordNotFound).Times(1)
+				repo.EXPECT().FindByID(gomock.Any()).Times(0)
+			},
+			expectedError: core.ErrNotFound,
+		},
+		{
+			name:  "Gagal - Token kosong",
+			token: "",
+			buildStubs: func(repo *mocks.MockOrderRepository) {
+				repo.EXPECT().FindByTrackingToken(gomock.Any()).Times(0)
+			},
+			expectedError: core.ErrNotFound,
+		},
+		{
+			name:  "Gagal - Database error",
+			token: "trk-abc123",
+			buildStubs: func(repo *mocks.MockOrderRepository) {
+				repo.EXPECT().FindByTrackingToken("trk-abc123").Return(nil, errors.New("connection reset")).Times(1)
+			},
+			expectedError: core.ErrInternalServer,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			tc.buildStubs(mockRepo)
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			receipt, err := service.RenderPublicReceipt(tc.token, tc.query)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, receipt)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedType, receipt.ContentType)
+			tc.checkBody(t, receipt.Body)
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package order
 
 import (
-	"fmt"
 	"time"
 
 	"go-fiber-pos/internal/core"
@@ -41,21 +40,97 @@ func (r *orderRepository) LockAndGetProduct(tx *gorm.DB, productID uuid.UUID) (*
 	return &product, nil
 }
 
+func (r *orderRepository) FindProducts(productIDs []uuid.UUID) (map[uuid.UUID]*core.Product, error) {
+	var products []core.Product
+	if err := r.db.Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*core.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	return byID, nil
+}
+
 // DeductStockWithTx menyimpan perubahan stok produk dalam transaksi yang ada.
 func (r *orderRepository) DeductStockWithTx(tx *gorm.DB, product *core.Product) error {
 	return tx.Save(product).Error
 }
 
+func (r *orderRepository) FindModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error) {
+	var groups []core.ModifierGroup
+	err := r.db.
+		Preload("Options").
+		Where("product_id = ?", productID).
+		Order("sort_order ASC").
+		Find(&groups).Error
+	return groups, err
+}
+
+func (r *orderRepository) FindBundleSlots(productID uuid.UUID) ([]core.BundleSlot, error) {
+	var slots []core.BundleSlot
+	err := r.db.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Preload("Options.Product").
+		Where("bundle_id = ?", productID).
+		Order("sort_order ASC").
+		Find(&slots).Error
+	return slots, err
+}
+
+func (r *orderRepository) FindModifierOptions(optionIDs []uuid.UUID) (map[uuid.UUID]*core.ModifierOption, error) {
+	var options []core.ModifierOption
+	if err := r.db.Where("id IN ?", optionIDs).Find(&options).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*core.ModifierOption, len(options))
+	for i := range options {
+		byID[options[i].ID] = &options[i]
+	}
+	return byID, nil
+}
+
+func (r *orderRepository) LockModifierOption(tx *gorm.DB, optionID uuid.UUID) (*core.ModifierOption, error) {
+	var option core.ModifierOption
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&option, "id = ?", optionID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+func (r *orderRepository) SaveModifierOptionWithTx(tx *gorm.DB, option *core.ModifierOption) error {
+	return tx.Save(option).Error
+}
+
+func (r *orderRepository) CreateItemModifiersWithTx(tx *gorm.DB, modifiers []core.OrderItemModifier) error {
+	if len(modifiers) == 0 {
+		return nil
+	}
+	return tx.Create(&modifiers).Error
+}
+
+func (r *orderRepository) CreateItemComponentsWithTx(tx *gorm.DB, components []core.OrderItemComponent) error {
+	if len(components) == 0 {
+		return nil
+	}
+	return tx.Create(&components).Error
+}
+
 // GetNextQueueNumber menghasilkan nomor antrean yang dijamin unik dan atomic.
 // Menggunakan DailyCounter dengan FOR UPDATE untuk mencegah race condition.
 func (r *orderRepository) GetNextQueueNumber(tx *gorm.DB, source string) (string, error) {
-	prefix := "K"
-	if source == core.OrderSourceEMenu {
-		prefix = "E"
+	// Skema penomoran dibaca dari profil toko; tanpa profil dipakai default (K-001 / E-001, reset harian).
+	var profile core.StoreProfile
+	if err := tx.First(&profile).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return "", err
 	}
 
-	today := time.Now().Format("20060102") // "20260221"
-	counterID := source + "-" + today      // "CASHIER-20260221"
+	period := profile.QueuePeriod(time.Now().In(profile.Location())) // "20260221" | "20260221-2" | "ALL"
+	counterID := source + "-" + period                               // "CASHIER-20260221"
 
 	var counter core.DailyCounter
 
@@ -68,10 +143,10 @@ func (r *orderRepository) GetNextQueueNumber(tx *gorm.DB, source string) (string
 		if err != gorm.ErrRecordNotFound {
 			return "", err
 		}
-		// Baris belum ada untuk hari ini — buat baru dengan LastCount = 1
+		// Baris belum ada untuk periode ini — buat baru dengan LastCount = 1
 		counter = core.DailyCounter{
 			ID:        counterID,
-			Date:      today,
+			Date:      period,
 			Source:    source,
 			LastCount: 1,
 		}
@@ -86,7 +161,7 @@ func (r *orderRepository) GetNextQueueNumber(tx *gorm.DB, source string) (string
 		}
 	}
 
-	return fmt.Sprintf("%s-%03d", prefix, counter.LastCount), nil
+	return profile.FormatQueueNumber(source, counter.LastCount), nil
 }
 
 // FindVoucherByCode mencari voucher berdasarkan kode (baca biasa tanpa lock).
@@ -108,12 +183,129 @@ func (r *orderRepository) GetStoreMarkupFee() int {
 	return profile.MarkupFee
 }
 
+func (r *orderRepository) GetStoreProfile() (*core.StoreProfile, error) {
+	var profile core.StoreProfile
+	if err := r.db.First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// preloadOrderDetail memuat semua relasi detail order. Dipakai FindByID dan replay idempotency
+// agar response replay sama dengan detail order biasa.
+func preloadOrderDetail(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Items.Product").
+		Preload("Items.Modifiers").
+		Preload("Items.Components").
+		Preload("Voucher").
+		Preload("Payments").
+		Preload("StatusHistory", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Refunds.Items").
+		Preload("Charges")
+}
+
 func (r *orderRepository) FindByID(id uuid.UUID) (*core.Order, error) {
+	var order core.Order
+	err := preloadOrderDetail(r.db).First(&order, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// orderSummaryColumns adalah proyeksi kolom untuk OrderSummary (tanpa preload relasi).
+const orderSummaryColumns = "orders.id, orders.order_source, orders.queue_number, orders.table_number, " +
+	"orders.order_status, orders.payment_status, orders.total_service_charge, orders.total_tax, " +
+	"orders.total_final_amount, orders.total_paid, " +
+	"orders.scheduled_for, orders.created_at, " +
+	"(SELECT COUNT(*) FROM order_items oi WHERE oi.order_id = orders.id) AS item_count"
+
+// List menggunakan keyset pagination, bukan OFFSET, agar performa tetap stabil
+// meskipun tabel orders sudah berisi data berbulan-bulan.
+func (r *orderRepository) List(filter OrderListFilter) ([]OrderSummary, error) {
+	query := r.db.Model(&core.Order{}).Select(orderSummaryColumns)
+
+	if filter.DateFrom != nil {
+		query = query.Where("orders.created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("orders.created_at < ?", *filter.DateTo)
+	}
+	if filter.OrderStatus != "" {
+		query = query.Where("orders.order_status = ?", filter.OrderStatus)
+	}
+	if filter.PaymentStatus != "" {
+		query = query.Where("orders.payment_status = ?", filter.PaymentStatus)
+	}
+	if filter.OrderSource != "" {
+		query = query.Where("orders.order_source = ?", filter.OrderSource)
+	}
+	if filter.TableNumber != "" {
+		query = query.Where("orders.table_number = ?", filter.TableNumber)
+	}
+	if filter.QueueNumber != "" {
+		query = query.Where("orders.queue_number = ?", filter.QueueNumber)
+	}
+	if filter.CursorCreatedAt != nil && filter.CursorID != nil {
+		query = query.Where("(orders.created_at, orders.id) < (?, ?)", *filter.CursorCreatedAt, *filter.CursorID)
+	}
+
+	var summaries []OrderSummary
+	err := query.
+		Order("orders.created_at DESC").
+		Order("orders.id DESC").
+		Limit(filter.Limit).
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+func (r *orderRepository) FindByIdempotencyKey(key string) (*core.Order, error) {
+	var order core.Order
+	err := preloadOrderDetail(r.db).Where("idempotency_key = ?", key).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) FindByTrackingToken(token string) (*core.Order, error) {
 	var order core.Order
 	err := r.db.
 		Preload("Items.Product").
-		Preload("Voucher").
-		Preload("Payments").
+		Preload("Items.Modifiers").
+		Preload("Items.Components").
+		Preload("Charges").
+		Where("tracking_token = ?", token).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) ListOpenOrders(source string) ([]core.Order, error) {
+	query := r.db.
+		Preload("Items.Product").
+		Preload("Items.Modifiers").
+		Preload("Items.Components").
+		Where("order_status NOT IN ?", []string{core.OrderStatusCompleted, core.OrderStatusCancelled}).
+		Where("scheduled_for IS NULL OR released_at IS NOT NULL")
+	if source != "" {
+		query = query.Where("order_source = ?", source)
+	}
+
+	var orders []core.Order
+	err := query.Order("created_at ASC").Find(&orders).Error
+	return orders, err
+}
+
+// LockOrder mengambil order dengan FOR UPDATE — perubahan status concurrent akan antre.
+func (r *orderRepository) LockOrder(tx *gorm.DB, id uuid.UUID) (*core.Order, error) {
+	var order core.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 		First(&order, "id = ?", id).Error
 	if err != nil {
 		return nil, err
@@ -121,12 +313,235 @@ func (r *orderRepository) FindByID(id uuid.UUID) (*core.Order, error) {
 	return &order, nil
 }
 
-func (r *orderRepository) GetAll() ([]core.Order, error) {
+func (r *orderRepository) UpdateOrderStatusWithTx(tx *gorm.DB, orderID uuid.UUID, status string) error {
+	return tx.Model(&core.Order{}).
+		Where("id = ?", orderID).
+		Update("order_status", status).Error
+}
+
+func (r *orderRepository) CreateStatusHistoryWithTx(tx *gorm.DB, history *core.OrderStatusHistory) error {
+	return tx.Create(history).Error
+}
+
+func (r *orderRepository) FindItemsByOrderIDWithTx(tx *gorm.DB, orderID uuid.UUID) ([]core.OrderItem, error) {
+	var items []core.OrderItem
+	err := tx.Preload("Modifiers").Preload("Components").Where("order_id = ?", orderID).Find(&items).Error
+	return items, err
+}
+
+func (r *orderRepository) CancelOrderWithTx(tx *gorm.DB, orderID uuid.UUID, reason string, cancelledAt time.Time) error {
+	return tx.Model(&core.Order{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"order_status":  core.OrderStatusCancelled,
+			"cancel_reason": reason,
+			"cancelled_at":  cancelledAt,
+			"is_open_tab":   false, // Membebaskan meja untuk tab baru
+		}).Error
+}
+
+func (r *orderRepository) CloseUnpaidPaymentsWithTx(tx *gorm.DB, orderID uuid.UUID, status string) error {
+	return tx.Model(&core.Payment{}).
+		Where("order_id = ? AND payment_status = ?", orderID, core.PaymentStatusUnpaid).
+		Update("payment_status", status).Error
+}
+
+// FindExpiredUnpaidOrderIDs tidak mengikutkan tab terbuka dan pre-order yang waktu ambilnya belum lewat TTL.
+func (r *orderRepository) FindExpiredUnpaidOrderIDs(cutoff time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&core.Order{}).
+		Where("order_status = ? AND payment_status = ?", core.OrderStatusPending, core.PaymentStatusUnpaid).
+		Where("is_open_tab = ?", false).
+		Where("created_at < ?", cutoff).
+		Where("scheduled_for IS NULL OR scheduled_for < ?", cutoff).
+		Order("created_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *orderRepository) CountUnpaidPaymentsWithTx(tx *gorm.DB, orderID uuid.UUID) (int64, error) {
+	var count int64
+	err := tx.Model(&core.Payment{}).
+		Where("order_id = ? AND payment_status = ?", orderID, core.PaymentStatusUnpaid).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *orderRepository) FindVoucherByID(id uuid.UUID) (*core.Voucher, error) {
+	var voucher core.Voucher
+	err := r.db.First(&voucher, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *orderRepository) SaveItemWithTx(tx *gorm.DB, item *core.OrderItem) error {
+	// Snapshot modifier & komponen bundle tidak pernah berubah setelah item dibuat;
+	// item baru memakai CreateItemModifiersWithTx dan CreateItemComponentsWithTx
+	return tx.Omit("Product", "Modifiers", "Components").Save(item).Error
+}
+
+func (r *orderRepository) DeleteItemWithTx(tx *gorm.DB, itemID uuid.UUID) error {
+	if err := tx.Delete(&core.OrderItemModifier{}, "order_item_id = ?", itemID).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(&core.OrderItemComponent{}, "order_item_id = ?", itemID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&core.OrderItem{}, "id = ?", itemID).Error
+}
+
+func (r *orderRepository) UpdateOrderTotalsWithTx(tx *gorm.DB, order *core.Order) error {
+	err := tx.Model(&core.Order{}).
+		Where("id = ?", order.ID).
+		Updates(map[string]interface{}{
+			"voucher_id":           order.VoucherID,
+			"total_base_price":     order.TotalBasePrice,
+			"total_discount":       order.TotalDiscount,
+			"total_promo_discount": order.TotalPromoDiscount,
+			"platform_fee":         order.PlatformFee,
+			"total_service_charge": order.TotalServiceCharge,
+			"total_tax":            order.TotalTax,
+			"total_tax_included":   order.TotalTaxIncluded,
+			"total_final_amount":   order.TotalFinalAmount,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	// Baris charge selalu dihitung ulang bersama total, jadi cukup diganti seluruhnya
+	if err := tx.Delete(&core.OrderCharge{}, "order_id = ?", order.ID).Error; err != nil {
+		return err
+	}
+	if len(order.Charges) == 0 {
+		return nil
+	}
+	return tx.Create(&order.Charges).Error
+}
+
+func (r *orderRepository) ListActiveChargeRules() ([]core.ChargeRule, error) {
+	var rules []core.ChargeRule
+	err := r.db.
+		Preload("ExemptCategories").
+		Where("is_active = ?", true).
+		Order("sort_order ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// FindProductCategories memetakan ProductID → CategoryID, termasuk produk yang sudah dihapus (soft delete).
+func (r *orderRepository) FindProductCategories(productIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	var products []core.Product
+	err := r.db.Unscoped().Select("id", "category_id").Where("id IN ?", productIDs).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[uuid.UUID]uuid.UUID, len(products))
+	for _, p := range products {
+		categories[p.ID] = p.CategoryID
+	}
+	return categories, nil
+}
+
+func (r *orderRepository) ListActivePromotions() ([]core.Promotion, error) {
+	var promotions []core.Promotion
+	err := r.db.Preload("Tiers").Where("is_active = ?", true).Order("priority ASC, name ASC").Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *orderRepository) FindPromoSchedules(productIDs []uuid.UUID) (map[uuid.UUID][]core.PromoSchedule, error) {
+	var schedules []core.PromoSchedule
+	if err := r.db.Where("product_id IN ?", productIDs).Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	byProduct := make(map[uuid.UUID][]core.PromoSchedule)
+	for _, schedule := range schedules {
+		byProduct[schedule.ProductID] = append(byProduct[schedule.ProductID], schedule)
+	}
+	return byProduct, nil
+}
+
+func (r *orderRepository) MoveItemsWithTx(tx *gorm.DB, fromOrderID, toOrderID uuid.UUID) error {
+	return tx.Model(&core.OrderItem{}).
+		Where("order_id = ?", fromOrderID).
+		Update("order_id", toOrderID).Error
+}
+
+func (r *orderRepository) MarkMergedWithTx(tx *gorm.DB, orderID, mergedIntoID uuid.UUID, reason string, mergedAt time.Time) error {
+	return tx.Model(&core.Order{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"order_status":         core.OrderStatusCancelled,
+			"cancel_reason":        reason,
+			"cancelled_at":         mergedAt,
+			"merged_into_id":       mergedIntoID,
+			"is_open_tab":          false,
+			"total_base_price":     0,
+			"total_discount":       0,
+			"total_promo_discount": 0,
+			"platform_fee":         0,
+			"total_service_charge": 0,
+			"total_tax":            0,
+			"total_tax_included":   0,
+			"total_final_amount":   0,
+		}).Error
+}
+
+func (r *orderRepository) FindOpenTabByTableWithTx(tx *gorm.DB, tableNumber string) (*core.Order, error) {
+	var order core.Order
+	err := tx.Where("table_number = ? AND is_open_tab = ?", tableNumber, true).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) ListOpenTabs() ([]core.Order, error) {
 	var orders []core.Order
 	err := r.db.
-		Preload("Items.Product").
-		Preload("Voucher").
-		Order("created_at DESC").
+		Preload("Items").
+		Where("is_open_tab = ?", true).
+		Order("table_number ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) CloseTabWithTx(tx *gorm.DB, orderID uuid.UUID, closedAt time.Time) error {
+	return tx.Model(&core.Order{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"is_open_tab":   false,
+			"tab_closed_at": closedAt,
+		}).Error
+}
+
+func (r *orderRepository) ListScheduled() ([]OrderSummary, error) {
+	var summaries []OrderSummary
+	err := r.db.Model(&core.Order{}).
+		Select(orderSummaryColumns).
+		Where("orders.scheduled_for IS NOT NULL AND orders.released_at IS NULL").
+		Where("orders.order_status <> ?", core.OrderStatusCancelled).
+		Order("orders.scheduled_for ASC").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+func (r *orderRepository) LockDueScheduledWithTx(tx *gorm.DB, releaseBefore time.Time, limit int) ([]core.Order, error) {
+	var orders []core.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("scheduled_for IS NOT NULL AND released_at IS NULL").
+		Where("scheduled_for <= ?", releaseBefore).
+		Where("order_status <> ?", core.OrderStatusCancelled).
+		Order("scheduled_for ASC").
+		Limit(limit).
 		Find(&orders).Error
 	return orders, err
 }
+
+func (r *orderRepository) MarkReleasedWithTx(tx *gorm.DB, orderIDs []uuid.UUID, releasedAt time.Time) error {
+	return tx.Model(&core.Order{}).
+		Where("id IN ?", orderIDs).
+		Update("released_at", releasedAt).Error
+}
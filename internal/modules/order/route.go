@@ -1,18 +1,57 @@
 package order
 
 import (
+	"go-fiber-pos/internal/core"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(adminGroup fiber.Router, db *gorm.DB, v *validator.Validate) {
+// SetupRoutes mendaftarkan endpoint order dan mengembalikan background job modul ini. Job tidak
+// dijalankan di sini: caller (main) menjalankannya dengan context yang dibatalkan saat server shutdown.
+func SetupRoutes(adminGroup fiber.Router, publicGroup fiber.Router, db *gorm.DB, v *validator.Validate, events core.OrderEventBus) []*BackgroundJob {
 	repo := NewOrderRepository(db)
-	service := NewOrderService(repo, v)
+	service := NewOrderService(repo, v, events)
 	ctrl := NewOrderController(service)
+	publicCtrl := NewPublicOrderController(service)
+	kitchenCtrl := NewKitchenController(service, events, v)
 
-	// Semua order endpoint membutuhkan autentikasi
+	// Semua order endpoint admin membutuhkan autentikasi
 	adminGroup.Post("/orders/checkout", ctrl.Checkout)
+	adminGroup.Post("/orders/quote", ctrl.Quote)
+	adminGroup.Post("/orders/table-token", ctrl.GenerateTableToken)
+
+	// Tab dine-in — didaftarkan sebelum /orders/:id agar "tabs" tidak dianggap ID
+	adminGroup.Get("/orders/tabs", ctrl.GetOpenTabs)
+	adminGroup.Post("/orders/tabs", ctrl.OpenTab)
+	adminGroup.Post("/orders/tabs/:id/rounds", ctrl.AddTabRound)
+	adminGroup.Post("/orders/tabs/:id/close", ctrl.CloseTab)
+
+	adminGroup.Post("/orders/merge", ctrl.Merge)
+	adminGroup.Get("/orders/scheduled", ctrl.GetScheduled)
 	adminGroup.Get("/orders", ctrl.GetAll)
 	adminGroup.Get("/orders/:id", ctrl.GetByID)
+	adminGroup.Get("/orders/:id/receipt", ctrl.Receipt)
+	adminGroup.Patch("/orders/:id/status", ctrl.UpdateStatus)
+	adminGroup.Post("/orders/:id/cancel", ctrl.Cancel)
+	adminGroup.Post("/orders/:id/items", ctrl.AddItem)
+	adminGroup.Patch("/orders/:id/items/:item_id", ctrl.UpdateItem)
+	adminGroup.Delete("/orders/:id/items/:item_id", ctrl.RemoveItem)
+	adminGroup.Post("/orders/:id/split", ctrl.Split)
+
+	// Kitchen display (Server-Sent Events)
+	adminGroup.Get("/kitchen/stream", kitchenCtrl.Stream)
+
+	// Rute Public (E-Menu — pelanggan scan QR meja)
+	publicGroup.Post("/orders/checkout", publicCtrl.Checkout)
+	publicGroup.Post("/orders/quote", publicCtrl.Quote)
+	publicGroup.Get("/orders/:token", publicCtrl.Track)
+	publicGroup.Get("/orders/:token/receipt", publicCtrl.Receipt)
+
+	// Background job: rilis pre-order ke dapur sesuai lead time & kedaluwarsa order yang tidak dibayar
+	return []*BackgroundJob{
+		NewPreOrderReleaseJob(service, preOrderReleaseInterval),
+		NewUnpaidOrderExpiryJob(service, unpaidOrderExpiryInterval),
+	}
 }
@@ -0,0 +1,57 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"go-fiber-pos/pkg/logger"
+)
+
+const (
+	// preOrderReleaseInterval adalah seberapa sering job mengecek pre-order yang jatuh tempo.
+	preOrderReleaseInterval = time.Minute
+	// unpaidOrderExpiryInterval adalah seberapa sering job mencari order yang tidak dibayar melewati TTL.
+	unpaidOrderExpiryInterval = time.Minute
+)
+
+// BackgroundJob menjalankan satu pekerjaan order secara berkala (rilis pre-order, kedaluwarsa order).
+// Setiap pekerjaan aman dijalankan di beberapa instance sekaligus karena berbasis row lock.
+type BackgroundJob struct {
+	name     string
+	interval time.Duration
+	run      func(now time.Time) (int, error)
+}
+
+// NewPreOrderReleaseJob merilis pre-order ke kitchen display sesuai lead time toko.
+func NewPreOrderReleaseJob(service OrderService, interval time.Duration) *BackgroundJob {
+	return &BackgroundJob{name: "rilis pre-order", interval: interval, run: service.ReleaseDueOrders}
+}
+
+// NewUnpaidOrderExpiryJob membatalkan order yang tidak dibayar melewati TTL toko dan mengembalikan stoknya.
+func NewUnpaidOrderExpiryJob(service OrderService, interval time.Duration) *BackgroundJob {
+	return &BackgroundJob{name: "kedaluwarsa order", interval: interval, run: service.ExpireUnpaidOrders}
+}
+
+// Start menjalankan job sampai ctx dibatalkan. Panggil di goroutine terpisah.
+func (job *BackgroundJob) Start(ctx context.Context) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			processed, err := job.run(now)
+			if err != nil {
+				if logger.Log != nil {
+					logger.Log.Errorf("Job %s gagal: %v", job.name, err)
+				}
+				continue
+			}
+			if processed > 0 && logger.Log != nil {
+				logger.Log.Infof("Job %s: %d order diproses", job.name, processed)
+			}
+		}
+	}
+}
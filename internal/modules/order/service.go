@@ -1,6 +1,11 @@
 package order
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
@@ -8,19 +13,31 @@ import (
 	"time"
 
 	"go-fiber-pos/internal/core"
+	"go-fiber-pos/pkg/jwt"
+	"go-fiber-pos/pkg/logger"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// defaultOrderListLimit adalah jumlah order per halaman jika query limit tidak diisi.
+const defaultOrderListLimit = 20
+
+// maxPreOrderAhead adalah batas terjauh waktu ambil pre-order.
+const maxPreOrderAhead = 7 * 24 * time.Hour
+
+// releaseBatchSize membatasi jumlah pre-order yang dirilis per putaran job.
+const releaseBatchSize = 100
+
 type orderService struct {
-	repo OrderRepository
-	v    *validator.Validate
+	repo   OrderRepository
+	v      *validator.Validate
+	events core.OrderEventPublisher
 }
 
-func NewOrderService(repo OrderRepository, v *validator.Validate) OrderService {
-	return &orderService{repo: repo, v: v}
+func NewOrderService(repo OrderRepository, v *validator.Validate, events core.OrderEventPublisher) OrderService {
+	return &orderService{repo: repo, v: v, events: events}
 }
 
 func (s *orderService) Checkout(req CheckoutRequest) (*core.Order, error) {
@@ -29,6 +46,26 @@ func (s *orderService) Checkout(req CheckoutRequest) (*core.Order, error) {
 		return nil, err
 	}
 
+	// Idempotency: hash tidak bergantung pada urutan item (lihat hashCheckoutRequest)
+	var idempotencyKey *string
+	var requestHash string
+	if req.IdempotencyKey != "" {
+		key := req.IdempotencyKey
+		idempotencyKey = &key
+		requestHash = hashCheckoutRequest(req)
+
+		if existing, err := s.replayCheckout(key, requestHash); err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	// Pre-order: waktu ambil harus masuk akal dan jatuh di jam operasional toko
+	if req.ScheduledFor != nil {
+		if err := s.validateSchedule(*req.ScheduledFor); err != nil {
+			return nil, err
+		}
+	}
+
 	// 2. Buka transaksi database
 	tx := s.repo.DB().Begin()
 	if tx.Error != nil {
@@ -42,27 +79,16 @@ func (s *orderService) Checkout(req CheckoutRequest) (*core.Order, error) {
 	}()
 
 	// 3. Resolve Voucher (baca biasa, tanpa lock — tidak perlu)
-	var voucher *core.Voucher
-	var voucherID *uuid.UUID
-	if req.VoucherCode != "" {
-		v, err := s.repo.FindVoucherByCode(req.VoucherCode)
-		if err != nil {
-			tx.Rollback()
-			return nil, core.ErrVoucherInvalid
-		}
-		if !v.IsActive || v.ValidUntil.Before(time.Now()) {
-			tx.Rollback()
-			return nil, core.ErrVoucherInvalid
-		}
-		voucher = v
-		voucherID = &v.ID
+	voucher, err := s.resolveVoucher(req.VoucherCode)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	// 4. ⭐ ANTI-DEADLOCK: Sort items berdasarkan ProductID ascending SEBELUM akuisisi lock.
-	// Ini memastikan semua transaksi concurrent mengunci baris dalam urutan yang sama,
-	// sehingga tidak ada circular wait → tidak ada deadlock.
+	// 4. Sort items berdasarkan ProductID ascending agar urutan baris order konsisten.
+	// Urutan kunci (anti-deadlock) dijamin adjustProductStock, termasuk untuk komponen bundle.
 	sort.Slice(req.Items, func(i, j int) bool {
-		return strings.Compare(req.Items[i].ProductID.String(), req.Items[j].ProductID.String()) < 0
+		return core.LessProductID(req.Items[i].ProductID, req.Items[j].ProductID)
 	})
 
 	// 5. Generate nomor antrean menggunakan DailyCounter + FOR UPDATE (atomic)
@@ -72,99 +98,398 @@ func (s *orderService) Checkout(req CheckoutRequest) (*core.Order, error) {
 		return nil, core.ErrInternalServer
 	}
 
-	// 6. Loop setiap item — akuisisi lock dan potong stok
-	var orderItems []core.OrderItem
-	var totalBasePrice int
+	// 6. Susun item, akuisisi lock, dan potong stok
+	// a. Validasi pilihan modifier & isi bundle (data menu, baca biasa)
+	orderItems, err := s.buildCheckoutItems(req.Items)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
-	for _, item := range req.Items {
-		// a. Kunci baris produk dengan FOR UPDATE
-		product, err := s.repo.LockAndGetProduct(tx, item.ProductID)
-		if err != nil {
-			tx.Rollback()
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, fmt.Errorf("produk dengan ID %s tidak ditemukan", item.ProductID)
+	// b. ⭐ ANTI-DEADLOCK: kunci semua produk (termasuk komponen bundle) dengan urutan ProductID ascending,
+	// validasi stok SETELAH lock diperoleh, lalu kurangi stok — masih dalam tx dan lock.
+	// Bundle tidak punya stok sendiri: yang dipotong adalah stok setiap komponennya.
+	stockQty := make(map[uuid.UUID]int)
+	addStockQty(stockQty, orderItems, 1)
+	products, err := s.adjustProductStock(tx, stockQty)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// c. Stok opsi modifier dipotong setelah semua produk dikunci
+	optionQty := make(map[uuid.UUID]int)
+	addModifierQty(optionQty, orderItems, 1)
+	if err := s.adjustModifierStock(tx, optionQty); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 7. Buat entity Order
+	trackingToken, err := generateTrackingToken()
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	order := &core.Order{
+		ID:             uuid.New(),
+		OrderSource:    req.OrderSource,
+		QueueNumber:    queueNumber,
+		TrackingToken:  trackingToken,
+		TableNumber:    req.TableNumber,
+		OrderStatus:    core.OrderStatusPending,
+		PaymentStatus:  core.PaymentStatusUnpaid,
+		ScheduledFor:   req.ScheduledFor,
+		IdempotencyKey: idempotencyKey,
+		RequestHash:    requestHash,
+		Items:          orderItems,
+	}
+	if voucher != nil {
+		order.VoucherID = &voucher.ID
+	}
+
+	// 8. Hitung harga satuan, promo, diskon voucher, platform fee, serta service charge & pajak
+	// (pipeline yang sama dengan QuoteCheckout)
+	if _, err := s.priceCheckout(order, products, voucher); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 9. Simpan dalam transaksi
+
+	if err := s.repo.CreateWithTx(tx, order); err != nil {
+		tx.Rollback()
+		// Request kembar yang berjalan bersamaan: unique index menolak salah satunya,
+		// yang kalah mengembalikan hasil pemenang (stoknya ikut di-rollback di atas).
+		if idempotencyKey != nil {
+			if existing, replayErr := s.replayCheckout(*idempotencyKey, requestHash); replayErr != nil || existing != nil {
+				return existing, replayErr
 			}
-			return nil, core.ErrInternalServer
 		}
+		return nil, core.ErrInternalServer
+	}
 
-		// b. Validasi stok SETELAH lock diperoleh (bukan sebelum!)
-		if product.Stock < item.Qty {
-			tx.Rollback()
-			return nil, fmt.Errorf("%w: %s (tersisa %d)", core.ErrInsufficientStock, product.Name, product.Stock)
+	// 10. Commit — semua lock dilepas, semua perubahan permanen
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	// 11. Beritahu kitchen display (setelah commit, bukan sebelumnya).
+	// Pre-order baru dikirim ke dapur oleh ReleaseDueOrders saat lead time tiba.
+	if order.ScheduledFor == nil {
+		s.publish(core.EventOrderCreated, order)
+	}
+
+	return order, nil
+}
+
+// replayCheckout mengembalikan order lama jika key sudah pernah dipakai dengan body yang sama,
+// ErrIdempotencyReuse jika body berbeda, atau (nil, nil) jika key belum pernah dipakai.
+func (s *orderService) replayCheckout(key, requestHash string) (*core.Order, error) {
+	existing, err := s.repo.FindByIdempotencyKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
+		return nil, core.ErrInternalServer
+	}
+	if existing.RequestHash != requestHash {
+		return nil, core.ErrIdempotencyReuse
+	}
+	return existing, nil
+}
 
-		// c. Kurangi stok — masih dalam tx dan lock
-		product.Stock -= item.Qty
-		if err := s.repo.DeductStockWithTx(tx, product); err != nil {
-			tx.Rollback()
-			return nil, core.ErrInternalServer
+// PublicCheckout menjalankan pipeline Checkout yang sama untuk pelanggan E-Menu.
+func (s *orderService) PublicCheckout(req PublicCheckoutRequest) (*PublicOrderResponse, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	tableNumber, err := parseTableToken(req.TableToken)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.Checkout(CheckoutRequest{
+		OrderSource: core.OrderSourceEMenu,
+		TableNumber: &tableNumber,
+		VoucherCode: req.VoucherCode,
+		Items:       req.Items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Muat ulang agar nama produk tersedia untuk tampilan pelanggan
+	created, err := s.GetOrderByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := ToPublicOrderResponse(created)
+	return &resp, nil
+}
+
+// QuoteCheckout menghitung total keranjang tanpa membuat order: pipeline harga sama dengan Checkout
+// (priceCheckout), tetapi tanpa transaksi, lock, nomor antrean, maupun pemotongan stok. Error validasi
+// (modifier, bundle, voucher, jadwal pre-order) sama dengan Checkout; kekurangan stok dilaporkan sebagai
+// peringatan agar keranjang tetap bisa ditampilkan.
+func (s *orderService) QuoteCheckout(req CheckoutRequest) (*QuoteResponse, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.ScheduledFor != nil {
+		if err := s.validateSchedule(*req.ScheduledFor); err != nil {
+			return nil, err
 		}
+	}
 
-		// d. Tentukan harga satuan (promo jika aktif dan dalam rentang waktu)
-		unitPrice := calculateUnitPrice(product)
-		subtotal := unitPrice * item.Qty
-		totalBasePrice += subtotal
+	voucher, err := s.resolveVoucher(req.VoucherCode)
+	if err != nil {
+		return nil, err
+	}
 
-		orderItems = append(orderItems, core.OrderItem{
-			ID:        uuid.New(),
-			ProductID: product.ID,
-			Qty:       item.Qty,
-			UnitPrice: unitPrice,
-			Subtotal:  subtotal,
-			Notes:     item.Notes,
-		})
+	// Urutan baris sama dengan Checkout agar promo yang bergantung urutan menghasilkan angka yang sama
+	sort.Slice(req.Items, func(i, j int) bool {
+		return core.LessProductID(req.Items[i].ProductID, req.Items[j].ProductID)
+	})
+	items, err := s.buildCheckoutItems(req.Items)
+	if err != nil {
+		return nil, err
 	}
 
-	// 7. Hitung diskon voucher
-	totalDiscount := 0
-	if voucher != nil {
-		if totalBasePrice < voucher.MinOrderAmount {
-			tx.Rollback()
-			return nil, core.ErrVoucherMinOrder
+	stockQty := make(map[uuid.UUID]int)
+	addStockQty(stockQty, items, 1)
+	productIDs := make([]uuid.UUID, 0, len(stockQty))
+	for productID := range stockQty {
+		productIDs = append(productIDs, productID)
+	}
+	products, err := s.repo.FindProducts(productIDs)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	for _, productID := range productIDs {
+		if _, ok := products[productID]; !ok && stockQty[productID] > 0 {
+			return nil, fmt.Errorf("produk dengan ID %s tidak ditemukan", productID)
 		}
-		totalDiscount = calculateDiscount(voucher, totalBasePrice)
 	}
 
-	// 8. Ambil platform fee dari profil toko
-	platformFee := s.repo.GetStoreMarkupFee()
+	order := &core.Order{OrderSource: req.OrderSource, Items: items}
+	priceAt, err := s.priceCheckout(order, products, voucher)
+	if err != nil {
+		return nil, err
+	}
 
-	totalFinalAmount := totalBasePrice - totalDiscount + platformFee
+	warnings, err := s.quoteWarnings(items, products, stockQty)
+	if err != nil {
+		return nil, err
+	}
+	resp := ToQuoteResponse(order, products, priceAt, warnings)
+	return &resp, nil
+}
 
-	// 9. Buat entity Order dan simpan dalam transaksi
-	order := &core.Order{
-		ID:               uuid.New(),
-		VoucherID:        voucherID,
-		OrderSource:      req.OrderSource,
-		QueueNumber:      queueNumber,
-		TableNumber:      req.TableNumber,
-		OrderStatus:      core.OrderStatusPending,
-		PaymentStatus:    core.PaymentStatusUnpaid,
-		TotalBasePrice:   totalBasePrice,
-		TotalDiscount:    totalDiscount,
-		PlatformFee:      platformFee,
-		TotalFinalAmount: totalFinalAmount,
-		Items:            orderItems,
+// PublicQuote adalah quote harga untuk keranjang E-Menu, dengan body yang sama seperti PublicCheckout.
+func (s *orderService) PublicQuote(req PublicCheckoutRequest) (*QuoteResponse, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.CreateWithTx(tx, order); err != nil {
-		tx.Rollback()
+	tableNumber, err := parseTableToken(req.TableToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.QuoteCheckout(CheckoutRequest{
+		OrderSource: core.OrderSourceEMenu,
+		TableNumber: &tableNumber,
+		VoucherCode: req.VoucherCode,
+		Items:       req.Items,
+	})
+}
+
+// quoteWarnings membandingkan kebutuhan stok keranjang (produk, komponen bundle, opsi modifier) dengan
+// stok saat ini tanpa lock, serta menandai produk yang sedang tidak tersedia di menu. Opsi modifier &
+// komponen bundle yang tidak tersedia sudah ditolak saat item disusun.
+func (s *orderService) quoteWarnings(items []core.OrderItem, products map[uuid.UUID]*core.Product, stockQty map[uuid.UUID]int) ([]QuoteWarning, error) {
+	warnings := []QuoteWarning{}
+
+	productIDs := make([]uuid.UUID, 0, len(stockQty))
+	for productID := range stockQty {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool {
+		return core.LessProductID(productIDs[i], productIDs[j])
+	})
+	for _, productID := range productIDs {
+		product, ok := products[productID]
+		if !ok {
+			continue
+		}
+		if !product.IsAvailable {
+			warnings = append(warnings, QuoteWarning{
+				Code:      QuoteWarningUnavailable,
+				ProductID: productID,
+				Name:      product.Name,
+				Message:   fmt.Sprintf("%s sedang tidak tersedia", product.Name),
+			})
+		}
+		if need := stockQty[productID]; need > 0 && product.Stock < need {
+			warnings = append(warnings, QuoteWarning{
+				Code:      QuoteWarningInsufficientStock,
+				ProductID: productID,
+				Name:      product.Name,
+				Requested: need,
+				Available: product.Stock,
+				Message:   fmt.Sprintf("%s: %s (tersisa %d)", core.ErrInsufficientStock, product.Name, product.Stock),
+			})
+		}
+	}
+
+	optionQty := make(map[uuid.UUID]int)
+	addModifierQty(optionQty, items, 1)
+	if len(optionQty) == 0 {
+		return warnings, nil
+	}
+	optionProduct := make(map[uuid.UUID]uuid.UUID, len(optionQty))
+	optionIDs := make([]uuid.UUID, 0, len(optionQty))
+	for _, item := range items {
+		for _, m := range item.Modifiers {
+			if _, ok := optionProduct[m.ModifierOptionID]; !ok {
+				optionProduct[m.ModifierOptionID] = item.ProductID
+				optionIDs = append(optionIDs, m.ModifierOptionID)
+			}
+		}
+	}
+	options, err := s.repo.FindModifierOptions(optionIDs)
+	if err != nil {
 		return nil, core.ErrInternalServer
 	}
+	for _, optionID := range optionIDs {
+		option, ok := options[optionID]
+		if !ok {
+			continue
+		}
+		if option.TrackStock && option.Stock < optionQty[optionID] {
+			warnings = append(warnings, QuoteWarning{
+				Code:      QuoteWarningInsufficientStock,
+				ProductID: optionProduct[optionID],
+				Name:      option.Name,
+				Requested: optionQty[optionID],
+				Available: option.Stock,
+				Message:   fmt.Sprintf("%s: %s (tersisa %d)", core.ErrInsufficientStock, option.Name, option.Stock),
+			})
+		}
+	}
+	return warnings, nil
+}
 
-	// 10. Commit — semua lock dilepas, semua perubahan permanen
-	if err := tx.Commit().Error; err != nil {
+// TrackOrder mengembalikan status order untuk pelanggan. Token yang salah diperlakukan sama
+// dengan order yang tidak ada agar tidak membocorkan informasi apa pun.
+func (s *orderService) TrackOrder(token string) (*PublicOrderResponse, error) {
+	if token == "" {
+		return nil, core.ErrNotFound
+	}
+
+	order, err := s.repo.FindByTrackingToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
 		return nil, core.ErrInternalServer
 	}
 
-	return order, nil
+	resp := ToPublicOrderResponse(order)
+	return &resp, nil
+}
+
+// parseTableToken membaca nomor meja dari token QR. TABLE_TOKEN_SECRET yang belum di-set adalah
+// kesalahan konfigurasi server, bukan QR pelanggan yang salah.
+func parseTableToken(token string) (string, error) {
+	tableNumber, err := jwt.ParseTableToken(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTableSecretMissing) {
+			if logger.Log != nil {
+				logger.Log.Errorf("Token meja tidak bisa diverifikasi: %v", err)
+			}
+			return "", core.ErrInternalServer
+		}
+		return "", core.ErrInvalidTableToken
+	}
+	return tableNumber, nil
+}
+
+func (s *orderService) GenerateTableToken(req TableTokenRequest) (string, error) {
+	if err := s.v.Struct(req); err != nil {
+		return "", err
+	}
+	token, err := jwt.GenerateTableToken(req.TableNumber)
+	if err != nil {
+		return "", core.ErrInternalServer
+	}
+	return token, nil
 }
 
-func (s *orderService) GetAllOrders() ([]core.Order, error) {
-	orders, err := s.repo.GetAll()
+// ListOrders mengembalikan satu halaman order sesuai filter. Repository diminta Limit+1 baris
+// untuk mengetahui apakah masih ada halaman berikutnya tanpa query COUNT tambahan.
+func (s *orderService) ListOrders(query OrderListQuery) (*OrderListResponse, error) {
+	if err := s.v.Struct(query); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultOrderListLimit
+	}
+
+	filter := OrderListFilter{
+		OrderStatus:   query.OrderStatus,
+		PaymentStatus: query.PaymentStatus,
+		OrderSource:   query.OrderSource,
+		TableNumber:   query.TableNumber,
+		QueueNumber:   query.QueueNumber,
+		Limit:         limit + 1,
+	}
+	loc := s.storeLocation()
+	if query.DateFrom != "" {
+		from, err := time.ParseInLocation("2006-01-02", query.DateFrom, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date_from %q", core.ErrInvalidDateFilter, query.DateFrom)
+		}
+		filter.DateFrom = &from
+	}
+	if query.DateTo != "" {
+		to, err := time.ParseInLocation("2006-01-02", query.DateTo, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date_to %q", core.ErrInvalidDateFilter, query.DateTo)
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.DateTo = &to
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := decodeOrderCursor(query.Cursor)
+		if err != nil {
+			return nil, core.ErrInvalidCursor
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = &id
+	}
+
+	summaries, err := s.repo.List(filter)
 	if err != nil {
 		return nil, core.ErrInternalServer
 	}
-	return orders, nil
+
+	resp := &OrderListResponse{Data: summaries}
+	if len(summaries) > limit {
+		resp.Data = summaries[:limit]
+		last := resp.Data[limit-1]
+		resp.NextCursor = encodeOrderCursor(last.CreatedAt, last.ID)
+	}
+	if resp.Data == nil {
+		resp.Data = []OrderSummary{}
+	}
+	return resp, nil
 }
 
 func (s *orderService) GetOrderByID(id uuid.UUID) (*core.Order, error) {
@@ -178,27 +503,1899 @@ func (s *orderService) GetOrderByID(id uuid.UUID) (*core.Order, error) {
 	return order, nil
 }
 
-// ===========================================
-// HELPER FUNCTIONS (private)
-// ===========================================
+// UpdateStatus memindahkan OrderStatus sesuai state machine di core dan mencatat riwayatnya
+// dalam satu transaksi. Order dikunci FOR UPDATE agar dua kasir tidak menimpa status satu sama lain.
+func (s *orderService) UpdateStatus(id uuid.UUID, req UpdateOrderStatusRequest, changedBy *uuid.UUID) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
 
-// calculateUnitPrice menentukan harga jual berdasarkan kondisi promo.
-func calculateUnitPrice(product *core.Product) int {
-	if !product.IsPromoActive || product.PromoPrice <= 0 {
-		return product.NormalPrice
+	// Pembatalan wajib lewat CancelOrder agar stok dikembalikan dan payment di-void
+	if req.Status == core.OrderStatusCancelled {
+		return s.CancelOrder(id, CancelOrderRequest{Reason: req.Note}, changedBy)
 	}
 
-	// Cek apakah saat ini berada dalam rentang waktu promo
-	now := time.Now()
-	currentTime := fmt.Sprintf("%02d:%02d", now.Hour(), now.Minute())
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
 
-	if product.PromoStartTime != "" && product.PromoEndTime != "" {
-		if currentTime >= product.PromoStartTime && currentTime <= product.PromoEndTime {
-			return product.PromoPrice
+	order, err := s.repo.LockOrder(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
 		}
+		return nil, core.ErrInternalServer
+	}
+
+	if !core.CanTransitionOrderStatus(order.OrderStatus, req.Status) {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: %s → %s", core.ErrInvalidTransition, order.OrderStatus, req.Status)
+	}
+
+	// Pre-order baru boleh dimasak setelah dirilis ke dapur oleh ReleaseDueOrders
+	if req.Status == core.OrderStatusPreparing && order.ScheduledFor != nil && order.ReleasedAt == nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: pre-order %s belum dirilis ke dapur", core.ErrInvalidTransition, order.QueueNumber)
+	}
+
+	// Pesanan hanya boleh diselesaikan jika sudah lunas; refund sebagian item tidak membatalkan pelunasan
+	if req.Status == core.OrderStatusCompleted && order.PaymentStatus != core.PaymentStatusPaid && order.PaymentStatus != core.PaymentStatusPartiallyRefunded {
+		tx.Rollback()
+		return nil, core.ErrOrderNotPaid
+	}
+
+	if err := s.repo.UpdateOrderStatusWithTx(tx, order.ID, req.Status); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	history := &core.OrderStatusHistory{
+		ID:         uuid.New(),
+		OrderID:    order.ID,
+		FromStatus: order.OrderStatus,
+		ToStatus:   req.Status,
+		ChangedBy:  changedBy,
+		Note:       req.Note,
+	}
+	if err := s.repo.CreateStatusHistoryWithTx(tx, history); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	updated, err := s.GetOrderByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(core.EventOrderStatusChanged, updated)
+	return updated, nil
+}
+
+// CancelOrder membatalkan order dalam satu transaksi: stok dikembalikan, payment UNPAID digagalkan,
+// dan riwayat status dicatat beserta alasannya. Order yang sudah lunas harus melalui refund.
+func (s *orderService) CancelOrder(id uuid.UUID, req CancelOrderRequest, changedBy *uuid.UUID) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.repo.LockOrder(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+
+	if err := s.cancelLockedOrder(tx, order, req.Reason, changedBy, core.PaymentStatusFailed); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	updated, err := s.GetOrderByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(core.EventOrderStatusChanged, updated)
+	return updated, nil
+}
+
+// AddOrderItem menambahkan baris item baru (misal minuman susulan) ke order yang belum dibayar.
+func (s *orderService) AddOrderItem(orderID uuid.UUID, req AddOrderItemRequest) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	modifiers, err := s.resolveModifiers(req.ProductID, req.ModifierOptionIDs)
+	if err != nil {
+		return nil, err
+	}
+	components, err := s.resolveBundle(req.ProductID, req.BundleSelections)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.editOrderItems(orderID, func(items []core.OrderItem) ([]core.OrderItem, error) {
+		return append(items, core.OrderItem{
+			ID:         uuid.New(),
+			OrderID:    orderID,
+			ProductID:  req.ProductID,
+			Qty:        req.Qty,
+			Notes:      req.Notes,
+			Round:      lastRound(items),
+			Modifiers:  modifiers,
+			Components: components,
+		}), nil
+	})
+}
+
+// UpdateOrderItem mengubah qty (dan opsional catatan) satu item.
+func (s *orderService) UpdateOrderItem(orderID, itemID uuid.UUID, req UpdateOrderItemRequest) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	return s.editOrderItems(orderID, func(items []core.OrderItem) ([]core.OrderItem, error) {
+		for i := range items {
+			if items[i].ID == itemID {
+				items[i].Qty = req.Qty
+				if req.Notes != nil {
+					items[i].Notes = *req.Notes
+				}
+				return items, nil
+			}
+		}
+		return nil, core.ErrNotFound
+	})
+}
+
+// RemoveOrderItem menghapus satu item. Item terakhir tidak boleh dihapus — gunakan CancelOrder.
+func (s *orderService) RemoveOrderItem(orderID, itemID uuid.UUID) (*core.Order, error) {
+	return s.editOrderItems(orderID, func(items []core.OrderItem) ([]core.OrderItem, error) {
+		for i := range items {
+			if items[i].ID == itemID {
+				return append(items[:i], items[i+1:]...), nil
+			}
+		}
+		return nil, core.ErrNotFound
+	})
+}
+
+// editOrderItems menjalankan satu perubahan isi order di dalam transaksi: order dikunci,
+// edit menghasilkan daftar item baru, lalu repriceLockedOrder menyesuaikan stok dan total.
+func (s *orderService) editOrderItems(orderID uuid.UUID, edit func(items []core.OrderItem) ([]core.OrderItem, error)) (*core.Order, error) {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.repo.LockOrder(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+
+	if order.OrderStatus != core.OrderStatusPending || order.PaymentStatus != core.PaymentStatusUnpaid {
+		tx.Rollback()
+		return nil, core.ErrOrderNotEditable
+	}
+
+	// Link pembayaran yang masih aktif dibuat dengan nominal lama — harus di-void dulu
+	pending, err := s.repo.CountUnpaidPaymentsWithTx(tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	if pending > 0 {
+		tx.Rollback()
+		return nil, core.ErrPaymentInProgress
+	}
+
+	oldItems, err := s.repo.FindItemsByOrderIDWithTx(tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	working := make([]core.OrderItem, len(oldItems))
+	copy(working, oldItems)
+	newItems, err := edit(working)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(newItems) == 0 {
+		tx.Rollback()
+		return nil, core.ErrEmptyOrder
+	}
+
+	if err := s.repriceLockedOrder(tx, order, oldItems, newItems); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	updated, err := s.GetOrderByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(core.EventOrderItemsChanged, updated)
+	return updated, nil
+}
+
+// repriceLockedOrder menyimpan daftar item baru sebuah order yang SUDAH dikunci oleh caller:
+//   - stok hanya disesuaikan sebesar selisih qty per produk (lama vs baru; komponen untuk bundle),
+//     dikunci dengan urutan ProductID yang sama seperti Checkout (anti-deadlock);
+//   - stok opsi modifier disesuaikan dengan cara yang sama, setelah semua produk dikunci;
+//   - item baru atau yang qty-nya berubah dihargai dengan harga promo yang berlaku saat ini
+//     (ditambah selisih modifier yang tersimpan di item), item yang tidak disentuh tetap memakai
+//     harga saat dipesan (mis. ronde tab saat happy hour);
+//   - promo otomatis dievaluasi ulang atas SEMUA item pada waktu sekarang (item lama bisa
+//     kehilangan atau mendapat promo), lalu voucher yang terpasang dicek ulang terhadap
+//     MinOrderAmount dan diskonnya dihitung atas subtotal setelah promo;
+//   - service charge & pajak dihitung ulang dengan aturan yang aktif saat ini.
+func (s *orderService) repriceLockedOrder(tx *gorm.DB, order *core.Order, oldItems, newItems []core.OrderItem) error {
+	delta := make(map[uuid.UUID]int)
+	addStockQty(delta, oldItems, -1)
+	addStockQty(delta, newItems, 1)
+	products, err := s.adjustProductStock(tx, delta)
+	if err != nil {
+		return err
+	}
+
+	optionDelta := make(map[uuid.UUID]int)
+	addModifierQty(optionDelta, oldItems, -1)
+	addModifierQty(optionDelta, newItems, 1)
+	if err := s.adjustModifierStock(tx, optionDelta); err != nil {
+		return err
+	}
+
+	oldQty := make(map[uuid.UUID]int, len(oldItems))
+	for _, item := range oldItems {
+		oldQty[item.ID] = item.Qty
+	}
+
+	priceAt, err := s.promoClock(products)
+	if err != nil {
+		return err
+	}
+	totalBasePrice := 0
+	for i := range newItems {
+		qty, existed := oldQty[newItems[i].ID]
+		if product, ok := products[newItems[i].ProductID]; ok && (!existed || qty != newItems[i].Qty) {
+			newItems[i].UnitPrice = calculateItemUnitPrice(product, &newItems[i], priceAt)
+		}
+		newItems[i].Subtotal = newItems[i].UnitPrice * newItems[i].Qty
+		totalBasePrice += newItems[i].Subtotal
+	}
+
+	promoDiscount, err := s.applyPromotions(newItems, priceAt, order.VoucherID != nil)
+	if err != nil {
+		return err
+	}
+	totalDiscount := promoDiscount
+	if order.VoucherID != nil {
+		voucher, err := s.repo.FindVoucherByID(*order.VoucherID)
+		if err != nil {
+			return core.ErrInternalServer
+		}
+		if totalBasePrice-promoDiscount < voucher.MinOrderAmount {
+			return core.ErrVoucherMinOrder
+		}
+		totalDiscount += calculateDiscount(voucher, totalBasePrice-promoDiscount)
+	}
+
+	kept := make(map[uuid.UUID]bool, len(newItems))
+	for i := range newItems {
+		kept[newItems[i].ID] = true
+		if err := s.repo.SaveItemWithTx(tx, &newItems[i]); err != nil {
+			return core.ErrInternalServer
+		}
+		if _, existed := oldQty[newItems[i].ID]; !existed {
+			for j := range newItems[i].Modifiers {
+				newItems[i].Modifiers[j].OrderItemID = newItems[i].ID
+			}
+			if err := s.repo.CreateItemModifiersWithTx(tx, newItems[i].Modifiers); err != nil {
+				return core.ErrInternalServer
+			}
+			for j := range newItems[i].Components {
+				newItems[i].Components[j].OrderItemID = newItems[i].ID
+			}
+			if err := s.repo.CreateItemComponentsWithTx(tx, newItems[i].Components); err != nil {
+				return core.ErrInternalServer
+			}
+		}
+	}
+	for _, item := range oldItems {
+		if !kept[item.ID] {
+			if err := s.repo.DeleteItemWithTx(tx, item.ID); err != nil {
+				return core.ErrInternalServer
+			}
+		}
+	}
+
+	order.TotalBasePrice = totalBasePrice
+	order.TotalPromoDiscount = promoDiscount
+	order.TotalDiscount = totalDiscount
+	if err := s.applyCharges(order, newItems); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateOrderTotalsWithTx(tx, order); err != nil {
+		return core.ErrInternalServer
+	}
+
+	return nil
+}
+
+// OpenTab membuka tab dine-in kosong untuk sebuah meja. Nomor antrean dan tracking token
+// diberikan sekarang; item menyusul lewat AddTabRound.
+func (s *orderService) OpenTab(req OpenTabRequest) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+	source := req.OrderSource
+	if source == "" {
+		source = core.OrderSourceCashier
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Satu meja hanya boleh punya satu tab terbuka (dijaga juga oleh partial unique index)
+	if _, err := s.repo.FindOpenTabByTableWithTx(tx, req.TableNumber); err == nil {
+		tx.Rollback()
+		return nil, core.ErrTabAlreadyOpen
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	queueNumber, err := s.repo.GetNextQueueNumber(tx, source)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	trackingToken, err := generateTrackingToken()
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	tableNumber := req.TableNumber
+	platformFee := s.repo.GetStoreMarkupFee()
+	order := &core.Order{
+		ID:               uuid.New(),
+		OrderSource:      source,
+		QueueNumber:      queueNumber,
+		TrackingToken:    trackingToken,
+		TableNumber:      &tableNumber,
+		OrderStatus:      core.OrderStatusPending,
+		PaymentStatus:    core.PaymentStatusUnpaid,
+		PlatformFee:      platformFee,
+		TotalFinalAmount: platformFee,
+		IsOpenTab:        true,
+	}
+	if err := s.repo.CreateWithTx(tx, order); err != nil {
+		tx.Rollback()
+		// Dua kasir membuka tab meja yang sama bersamaan: partial unique index menolak yang kalah
+		if _, findErr := s.repo.FindOpenTabByTableWithTx(s.repo.DB(), req.TableNumber); findErr == nil {
+			return nil, core.ErrTabAlreadyOpen
+		}
+		return nil, core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return order, nil
+}
+
+// AddTabRound menambahkan satu ronde item ke tab yang masih terbuka. Stok dipotong dan total
+// dihitung ulang seperti Checkout, lalu ronde baru dikirim ke kitchen display.
+// Ronde hanya diterima selama tab belum READY — READY berarti semua pesanan meja sudah diantar.
+func (s *orderService) AddTabRound(orderID uuid.UUID, req AddTabRoundRequest) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+	// Pilihan modifier & isi bundle divalidasi sebelum tab dikunci (data menu, tidak perlu lock)
+	roundModifiers := make([][]core.OrderItemModifier, len(req.Items))
+	roundComponents := make([][]core.OrderItemComponent, len(req.Items))
+	for i, item := range req.Items {
+		modifiers, err := s.resolveModifiers(item.ProductID, item.ModifierOptionIDs)
+		if err != nil {
+			return nil, err
+		}
+		components, err := s.resolveBundle(item.ProductID, item.BundleSelections)
+		if err != nil {
+			return nil, err
+		}
+		roundModifiers[i] = modifiers
+		roundComponents[i] = components
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.repo.LockOrder(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if !order.IsOpenTab {
+		tx.Rollback()
+		return nil, core.ErrTabNotOpen
+	}
+	switch order.OrderStatus {
+	case core.OrderStatusPending, core.OrderStatusConfirmed, core.OrderStatusPreparing:
+	default:
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: tab berstatus %s", core.ErrOrderNotEditable, order.OrderStatus)
+	}
+
+	oldItems, err := s.repo.FindItemsByOrderIDWithTx(tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	round := lastRound(oldItems) + 1
+	if len(oldItems) == 0 {
+		round = 1
+	}
+	newItems := make([]core.OrderItem, len(oldItems), len(oldItems)+len(req.Items))
+	copy(newItems, oldItems)
+	for i, item := range req.Items {
+		newItems = append(newItems, core.OrderItem{
+			ID:         uuid.New(),
+			OrderID:    order.ID,
+			ProductID:  item.ProductID,
+			Qty:        item.Qty,
+			Notes:      item.Notes,
+			Round:      round,
+			Modifiers:  roundModifiers[i],
+			Components: roundComponents[i],
+		})
+	}
+
+	if err := s.repriceLockedOrder(tx, order, oldItems, newItems); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	updated, err := s.GetOrderByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(core.EventOrderRoundAdded, updated)
+	return updated, nil
+}
+
+// CloseTab menutup tab: tidak ada ronde baru, dan order kini bisa dibayar sekali lewat payment.
+func (s *orderService) CloseTab(orderID uuid.UUID) (*core.Order, error) {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.repo.LockOrder(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if !order.IsOpenTab {
+		tx.Rollback()
+		return nil, core.ErrTabNotOpen
+	}
+
+	items, err := s.repo.FindItemsByOrderIDWithTx(tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	if len(items) == 0 {
+		tx.Rollback()
+		return nil, core.ErrEmptyOrder
+	}
+
+	if err := s.repo.CloseTabWithTx(tx, order.ID, time.Now()); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return s.GetOrderByID(order.ID)
+}
+
+func (s *orderService) ListOpenTabs() ([]TabSummary, error) {
+	orders, err := s.repo.ListOpenTabs()
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return ToTabSummaryList(orders), nil
+}
+
+// SplitOrder memecah satu order UNPAID menjadi beberapa bill. Item hanya berpindah order,
+// sehingga stok tidak disentuh dan harga satuan tetap seperti saat dipesan. Diskon promo ikut
+// item-nya (dibagi per qty), diskon voucher dan platform fee order asal dibagi proporsional
+// terhadap subtotal tiap bill, sehingga jumlahnya sama persis dengan sebelum dipecah. Service
+// charge & pajak dihitung ulang per bill dari item bill itu sendiri (setiap bill adalah dokumen
+// pajak terpisah), jadi totalnya bisa berbeda beberapa rupiah karena pembulatan.
+// Bill anak dari pre-order mewarisi ScheduledFor/ReleasedAt, sehingga baru muncul di dapur
+// bersama order induknya.
+func (s *orderService) SplitOrder(orderID uuid.UUID, req SplitOrderRequest, changedBy *uuid.UUID) ([]core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	parent, err := s.repo.LockOrder(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if err := s.ensureBillAdjustable(tx, parent); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	items, err := s.repo.FindItemsByOrderIDWithTx(tx, parent.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	// parts[0] = item yang tetap di order induk, parts[1:] = isi setiap bill anak
+	var parts [][]core.OrderItem
+	if req.Mode == SplitModeEven {
+		parts, err = partitionItemsEvenly(items, req.Ways)
+	} else {
+		parts, err = partitionItemsByGroups(items, req.Groups)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(parts[0]) == 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: order induk harus menyisakan minimal satu item", core.ErrInvalidSplit)
+	}
+
+	bases := make([]int, len(parts))
+	for i, part := range parts {
+		for _, item := range part {
+			bases[i] += item.Subtotal
+		}
+	}
+	// Diskon promo ikut item-nya (sudah dibagi per qty saat partisi); diskon voucher dibagi proporsional
+	promos := make([]int, len(parts))
+	voucherBases := make([]int, len(parts))
+	for i, part := range parts {
+		for _, item := range part {
+			promos[i] += item.PromoDiscount
+		}
+		voucherBases[i] = bases[i] - promos[i]
+	}
+	discounts := core.AllocateProportionally(parent.TotalDiscount-parent.TotalPromoDiscount, voucherBases)
+	for i := range discounts {
+		discounts[i] += promos[i]
+	}
+	fees := core.AllocateProportionally(parent.PlatformFee, bases)
+
+	// 1. Order induk: simpan sisa item, hapus baris yang pindah seluruhnya
+	kept := make(map[uuid.UUID]bool, len(parts[0]))
+	for i := range parts[0] {
+		kept[parts[0][i].ID] = true
+		if err := s.repo.SaveItemWithTx(tx, &parts[0][i]); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+	}
+	for _, item := range items {
+		if !kept[item.ID] {
+			if err := s.repo.DeleteItemWithTx(tx, item.ID); err != nil {
+				tx.Rollback()
+				return nil, core.ErrInternalServer
+			}
+		}
+	}
+	parent.TotalBasePrice = bases[0]
+	parent.TotalPromoDiscount = promos[0]
+	parent.TotalDiscount = discounts[0]
+	parent.PlatformFee = fees[0]
+	if err := s.applyCharges(parent, parts[0]); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.repo.UpdateOrderTotalsWithTx(tx, parent); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	// 2. Bill anak: order baru dengan antrean & tracking token sendiri, menunjuk order induk
+	var childIDs []uuid.UUID
+	for i := 1; i < len(parts); i++ {
+		queueNumber, err := s.repo.GetNextQueueNumber(tx, parent.OrderSource)
+		if err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+		trackingToken, err := generateTrackingToken()
+		if err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+
+		child := &core.Order{
+			ID:                 uuid.New(),
+			VoucherID:          parent.VoucherID,
+			OrderSource:        parent.OrderSource,
+			QueueNumber:        queueNumber,
+			TrackingToken:      trackingToken,
+			TableNumber:        parent.TableNumber,
+			OrderStatus:        parent.OrderStatus, // Progres dapur ikut order asal
+			PaymentStatus:      core.PaymentStatusUnpaid,
+			TotalBasePrice:     bases[i],
+			TotalDiscount:      discounts[i],
+			PlatformFee:        fees[i],
+			TotalPromoDiscount: promos[i],
+			ScheduledFor:       parent.ScheduledFor,
+			ReleasedAt:         parent.ReleasedAt,
+			ParentOrderID:      &parent.ID,
+			Items:              parts[i],
+		}
+		for j := range child.Items {
+			child.Items[j].OrderID = child.ID
+		}
+		if err := s.applyCharges(child, child.Items); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := s.repo.CreateWithTx(tx, child); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+		history := &core.OrderStatusHistory{
+			ID:         uuid.New(),
+			OrderID:    child.ID,
+			FromStatus: parent.OrderStatus,
+			ToStatus:   child.OrderStatus,
+			ChangedBy:  changedBy,
+			Note:       fmt.Sprintf("Dipecah dari pesanan %s (%s)", parent.QueueNumber, parent.ID),
+		}
+		if err := s.repo.CreateStatusHistoryWithTx(tx, history); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+		childIDs = append(childIDs, child.ID)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	updated, err := s.GetOrderByID(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	// Pre-order yang belum dirilis dikirim ke dapur oleh ReleaseDueOrders, bukan sekarang
+	released := updated.ScheduledFor == nil || updated.ReleasedAt != nil
+	if released {
+		s.publish(core.EventOrderItemsChanged, updated)
+	}
+	result := []core.Order{*updated}
+	for _, childID := range childIDs {
+		child, err := s.GetOrderByID(childID)
+		if err != nil {
+			return nil, err
+		}
+		if released {
+			s.publish(core.EventOrderCreated, child)
+		}
+		result = append(result, *child)
+	}
+	return result, nil
+}
+
+// MergeOrders menggabungkan beberapa order UNPAID (mis. dua meja yang bergabung) ke order pertama.
+// Item dipindahkan tanpa menyentuh stok; order lain ditutup sebagai CANCELLED tanpa restock
+// dengan MergedIntoID sebagai jejak audit. Order gabungan hanya dikenai SATU platform fee
+// dan diskon voucher dihitung ulang terhadap subtotal gabungan setelah promo item.
+// Pre-order yang belum dirilis ke dapur tidak bisa digabung karena jadwal rilisnya bisa berbeda.
+// Satu order hanya bisa memakai satu voucher, jadi order dengan voucher berbeda ditolak.
+func (s *orderService) MergeOrders(req MergeOrdersRequest, changedBy *uuid.UUID) (*core.Order, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// ⭐ ANTI-DEADLOCK: kunci semua order dalam urutan ID yang sama untuk setiap merge concurrent
+	lockOrder := make([]uuid.UUID, len(req.OrderIDs))
+	copy(lockOrder, req.OrderIDs)
+	sort.Slice(lockOrder, func(i, j int) bool {
+		return core.LessOrderID(lockOrder[i], lockOrder[j])
+	})
+
+	locked := make(map[uuid.UUID]*core.Order, len(lockOrder))
+	for _, id := range lockOrder {
+		order, err := s.repo.LockOrder(tx, id)
+		if err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: order %s", core.ErrNotFound, id)
+			}
+			return nil, core.ErrInternalServer
+		}
+		if err := s.ensureBillAdjustable(tx, order); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if order.ScheduledFor != nil && order.ReleasedAt == nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: pre-order %s belum dirilis ke dapur", core.ErrOrderNotEditable, order.QueueNumber)
+		}
+		locked[id] = order
+	}
+
+	// Dicek sebelum item dipindahkan: voucher yang kalah tidak boleh hilang diam-diam
+	var voucherID *uuid.UUID
+	for _, order := range locked {
+		if order.VoucherID == nil {
+			continue
+		}
+		if voucherID != nil && *voucherID != *order.VoucherID {
+			tx.Rollback()
+			return nil, core.ErrVoucherConflict
+		}
+		voucherID = order.VoucherID
+	}
+
+	target := locked[req.OrderIDs[0]]
+	now := time.Now()
+	for _, id := range req.OrderIDs[1:] {
+		source := locked[id]
+		// Order yang sudah READY sudah diantar sebagai pesanan sendiri — tidak bisa ditutup lagi
+		if !core.CanTransitionOrderStatus(source.OrderStatus, core.OrderStatusCancelled) {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: order %s berstatus %s", core.ErrInvalidTransition, source.QueueNumber, source.OrderStatus)
+		}
+		if target.VoucherID == nil && source.VoucherID != nil {
+			target.VoucherID = source.VoucherID
+		}
+
+		if err := s.repo.MoveItemsWithTx(tx, source.ID, target.ID); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+		note := fmt.Sprintf("Digabung ke pesanan %s", target.QueueNumber)
+		if err := s.repo.MarkMergedWithTx(tx, source.ID, target.ID, note, now); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+		history := &core.OrderStatusHistory{
+			ID:         uuid.New(),
+			OrderID:    source.ID,
+			FromStatus: source.OrderStatus,
+			ToStatus:   core.OrderStatusCancelled,
+			ChangedBy:  changedBy,
+			Note:       note,
+		}
+		if err := s.repo.CreateStatusHistoryWithTx(tx, history); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+	}
+
+	items, err := s.repo.FindItemsByOrderIDWithTx(tx, target.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	totalBasePrice, promoDiscount := 0, 0
+	for _, item := range items {
+		totalBasePrice += item.Subtotal
+		promoDiscount += item.PromoDiscount
+	}
+
+	// Promo otomatis tetap milik item masing-masing (tidak dievaluasi ulang atas gabungan).
+	// Subtotal gabungan selalu >= subtotal order asal voucher, sehingga MinOrderAmount tetap terpenuhi
+	totalDiscount := promoDiscount
+	if target.VoucherID != nil {
+		voucher, err := s.repo.FindVoucherByID(*target.VoucherID)
+		if err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+		totalDiscount += calculateDiscount(voucher, totalBasePrice-promoDiscount)
+	}
+
+	target.TotalBasePrice = totalBasePrice
+	target.TotalPromoDiscount = promoDiscount
+	target.TotalDiscount = totalDiscount
+	if err := s.applyCharges(target, items); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.repo.UpdateOrderTotalsWithTx(tx, target); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	for _, id := range req.OrderIDs[1:] {
+		if merged, err := s.GetOrderByID(id); err == nil {
+			s.publish(core.EventOrderStatusChanged, merged)
+		}
+	}
+	updated, err := s.GetOrderByID(target.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(core.EventOrderItemsChanged, updated)
+	return updated, nil
+}
+
+// ensureBillAdjustable memastikan order yang SUDAH dikunci boleh di-split/merge:
+// belum final, belum ada dana masuk, tidak ada link pembayaran aktif, dan bukan tab yang masih terbuka.
+func (s *orderService) ensureBillAdjustable(tx *gorm.DB, order *core.Order) error {
+	if core.IsFinalOrderStatus(order.OrderStatus) {
+		return fmt.Errorf("%w: order %s berstatus %s", core.ErrOrderNotEditable, order.QueueNumber, order.OrderStatus)
+	}
+	if order.PaymentStatus != core.PaymentStatusUnpaid {
+		return core.ErrOrderAlreadyPaid
+	}
+	if order.IsOpenTab {
+		return core.ErrTabOpen
+	}
+	pending, err := s.repo.CountUnpaidPaymentsWithTx(tx, order.ID)
+	if err != nil {
+		return core.ErrInternalServer
+	}
+	if pending > 0 {
+		return core.ErrPaymentInProgress
+	}
+	return nil
+}
+
+// GetKitchenOrders mengambil semua order yang masih terbuka (belum COMPLETED/CANCELLED),
+// dipakai untuk replay saat layar dapur (re)connect. source kosong = semua source.
+func (s *orderService) GetKitchenOrders(source string) ([]KitchenOrderResponse, error) {
+	orders, err := s.repo.ListOpenOrders(source)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return ToKitchenOrderResponseList(orders), nil
+}
+
+func (s *orderService) GetKitchenOrder(id uuid.UUID) (*KitchenOrderResponse, error) {
+	order, err := s.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Pre-order yang belum dirilis belum boleh tampil di dapur
+	if order.ScheduledFor != nil && order.ReleasedAt == nil {
+		return nil, core.ErrNotFound
+	}
+	resp := ToKitchenOrderResponse(order)
+	return &resp, nil
+}
+
+func (s *orderService) ListScheduledOrders() ([]OrderSummary, error) {
+	summaries, err := s.repo.ListScheduled()
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	if summaries == nil {
+		summaries = []OrderSummary{}
+	}
+	return summaries, nil
+}
+
+// ReleaseDueOrders merilis pre-order yang waktu ambilnya sudah dalam jangkauan lead time toko.
+// Baris dikunci dengan SKIP LOCKED sehingga satu pre-order tidak pernah dirilis dua kali.
+func (s *orderService) ReleaseDueOrders(now time.Time) (int, error) {
+	lead := core.DefaultPreOrderLead
+	if profile, err := s.repo.GetStoreProfile(); err == nil {
+		lead = profile.PreOrderLeadTime()
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return 0, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	due, err := s.repo.LockDueScheduledWithTx(tx, now.Add(lead), releaseBatchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, core.ErrInternalServer
+	}
+	if len(due) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(due))
+	for _, order := range due {
+		ids = append(ids, order.ID)
+	}
+	if err := s.repo.MarkReleasedWithTx(tx, ids, now); err != nil {
+		tx.Rollback()
+		return 0, core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, core.ErrInternalServer
+	}
+
+	for i := range due {
+		s.publish(core.EventOrderReleased, &due[i])
+	}
+	return len(due), nil
+}
+
+// ExpireUnpaidOrders membatalkan order PENDING/UNPAID yang melewati TTL toko. Setiap order diproses
+// dalam transaksinya sendiri dan dicek ulang SETELAH dikunci: jika webhook settlement yang terlambat
+// sudah lebih dulu mengunci dan melunasi order, order tersebut dilewati. Sebaliknya jika order sudah
+// kedaluwarsa, webhook yang datang belakangan akan melihat order CANCELLED dan me-refund dananya.
+// Kegagalan satu order hanya dicatat ke log; order lain dalam batch tetap diproses.
+func (s *orderService) ExpireUnpaidOrders(now time.Time) (int, error) {
+	profile, err := s.repo.GetStoreProfile()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, core.ErrInternalServer
+	}
+	ttl := profile.UnpaidOrderTTL()
+	if ttl == 0 {
+		return 0, nil
+	}
+	cutoff := now.Add(-ttl)
+
+	ids, err := s.repo.FindExpiredUnpaidOrderIDs(cutoff, releaseBatchSize)
+	if err != nil {
+		return 0, core.ErrInternalServer
+	}
+
+	reason := fmt.Sprintf("Kedaluwarsa: tidak dibayar dalam %d menit", profile.UnpaidOrderTTLMinutes)
+	expired := 0
+	for _, id := range ids {
+		order, err := s.expireOrder(id, cutoff, reason)
+		if err != nil {
+			// Satu order yang gagal (mis. lock timeout) tidak boleh menahan order lain; dicoba lagi di tick berikutnya
+			if logger.Log != nil {
+				logger.Log.Errorf("Gagal meng-expire order %s: %v", id, err)
+			}
+			continue
+		}
+		if order != nil {
+			s.publish(core.EventOrderStatusChanged, order)
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// expireOrder membatalkan satu order kedaluwarsa. Mengembalikan nil tanpa error jika order
+// ternyata sudah berubah (dibayar, diproses dapur, atau dibatalkan) sejak dipilih sebagai kandidat.
+func (s *orderService) expireOrder(id uuid.UUID, cutoff time.Time, reason string) (*core.Order, error) {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.repo.LockOrder(tx, id)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, core.ErrInternalServer
+	}
+	if order.OrderStatus != core.OrderStatusPending || order.PaymentStatus != core.PaymentStatusUnpaid ||
+		order.IsOpenTab || !order.CreatedAt.Before(cutoff) {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	if err := s.cancelLockedOrder(tx, order, reason, nil, core.PaymentStatusExpired); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	return s.GetOrderByID(order.ID)
+}
+
+// validateSchedule memastikan waktu ambil pre-order di masa depan, tidak terlalu jauh,
+// dan berada di dalam jam operasional toko.
+func (s *orderService) validateSchedule(scheduledFor time.Time) error {
+	now := time.Now()
+	if !scheduledFor.After(now) || scheduledFor.After(now.Add(maxPreOrderAhead)) {
+		return core.ErrInvalidSchedule
+	}
+
+	profile, err := s.repo.GetStoreProfile()
+	if err != nil {
+		// Profil toko belum dibuat = jam operasional belum dibatasi
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return core.ErrInternalServer
+	}
+	if !profile.IsOpenAt(scheduledFor.In(profile.Location())) {
+		return fmt.Errorf("%w (%s–%s)", core.ErrStoreClosed, profile.OpeningTime, profile.ClosingTime)
+	}
+	return nil
+}
+
+// RenderReceipt memuat order lengkap (FindByID) dan profil toko, lalu merendernya.
+func (s *orderService) RenderReceipt(orderID uuid.UUID, query ReceiptQuery) (*RenderedReceipt, error) {
+	if err := s.v.Struct(query); err != nil {
+		return nil, err
+	}
+	if query.Format == ReceiptFormatHTML && query.Type == ReceiptTypeKitchen {
+		return nil, fmt.Errorf("%w: tiket dapur hanya tersedia dalam format escpos atau pdf", core.ErrReceiptFormat)
+	}
+
+	data, err := s.loadReceiptData(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Type == ReceiptTypeKitchen {
+		name := fmt.Sprintf("kitchen-%s", data.QueueNumber)
+		if query.Format == ReceiptFormatPDF {
+			return &RenderedReceipt{
+				ContentType: "application/pdf",
+				FileName:    name + ".pdf",
+				Body:        renderKitchenTicketPDF(data, query.Round),
+			}, nil
+		}
+		return &RenderedReceipt{
+			ContentType: "application/octet-stream",
+			FileName:    name + ".bin",
+			Body:        renderKitchenTicketESCPOS(data, receiptPaperWidth(query.Width), query.Round),
+		}, nil
+	}
+
+	if query.Format != ReceiptFormatESCPOS {
+		return renderDigitalReceipt(data, query.Format)
+	}
+	kickDrawer := data.hasCashPayment()
+	if query.Drawer != nil {
+		kickDrawer = *query.Drawer
+	}
+	return &RenderedReceipt{
+		ContentType: "application/octet-stream",
+		FileName:    fmt.Sprintf("receipt-%s.bin", data.QueueNumber),
+		Body:        renderReceiptESCPOS(data, receiptPaperWidth(query.Width), kickDrawer),
+	}, nil
+}
+
+// RenderPublicReceipt memakai data yang sama dengan RenderReceipt; tracking token hanya
+// dipakai untuk menemukan ID order sehingga ID internal tidak pernah terekspos ke pelanggan.
+func (s *orderService) RenderPublicReceipt(token string, query PublicReceiptQuery) (*RenderedReceipt, error) {
+	if err := s.v.Struct(query); err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, core.ErrNotFound
+	}
+
+	order, err := s.repo.FindByTrackingToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+
+	data, err := s.loadReceiptData(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	format := query.Format
+	if format == "" {
+		format = ReceiptFormatHTML
+	}
+	return renderDigitalReceipt(data, format)
+}
+
+// loadReceiptData memuat order lengkap (FindByID) dan profil toko menjadi receiptData.
+func (s *orderService) loadReceiptData(orderID uuid.UUID) (receiptData, error) {
+	order, err := s.GetOrderByID(orderID)
+	if err != nil {
+		return receiptData{}, err
+	}
+	// Profil toko opsional: tanpa profil, header struk dikosongkan
+	store, err := s.repo.GetStoreProfile()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return receiptData{}, core.ErrInternalServer
+	}
+	return buildReceiptData(order, store), nil
+}
+
+// renderDigitalReceipt merender struk pelanggan sebagai HTML atau PDF.
+func renderDigitalReceipt(data receiptData, format string) (*RenderedReceipt, error) {
+	name := fmt.Sprintf("receipt-%s", data.QueueNumber)
+	if format == ReceiptFormatPDF {
+		return &RenderedReceipt{
+			ContentType: "application/pdf",
+			FileName:    name + ".pdf",
+			Body:        renderReceiptPDF(data),
+		}, nil
+	}
+
+	body, err := renderReceiptHTML(data)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return &RenderedReceipt{
+		ContentType: "text/html; charset=utf-8",
+		FileName:    name + ".html",
+		Body:        body,
+	}, nil
+}
+
+// receiptPaperWidth mengembalikan lebar kertas thermal (mm), default 80.
+func receiptPaperWidth(width int) int {
+	if width == 0 {
+		return 80
+	}
+	return width
+}
+
+// publish menyiarkan event order ke subscriber realtime (kitchen display).
+func (s *orderService) publish(eventType string, order *core.Order) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(core.OrderEvent{
+		Type:          eventType,
+		OrderID:       order.ID,
+		OrderSource:   order.OrderSource,
+		OrderStatus:   order.OrderStatus,
+		PaymentStatus: order.PaymentStatus,
+		OccurredAt:    time.Now(),
+	})
+}
+
+// cancelLockedOrder berisi inti pembatalan. Order HARUS sudah dikunci FOR UPDATE oleh caller
+// di dalam tx yang sama; commit/rollback tetap menjadi tanggung jawab caller.
+// paymentStatus menentukan status akhir payment UNPAID: FAILED (void manual) atau EXPIRED (TTL).
+func (s *orderService) cancelLockedOrder(tx *gorm.DB, order *core.Order, reason string, changedBy *uuid.UUID, paymentStatus string) error {
+	if !core.CanTransitionOrderStatus(order.OrderStatus, core.OrderStatusCancelled) {
+		return fmt.Errorf("%w: %s → %s", core.ErrInvalidTransition, order.OrderStatus, core.OrderStatusCancelled)
+	}
+	// Order yang sudah (sebagian) dibayar harus melalui refund, bukan pembatalan
+	if order.PaymentStatus != core.PaymentStatusUnpaid {
+		return core.ErrOrderAlreadyPaid
+	}
+
+	items, err := s.repo.FindItemsByOrderIDWithTx(tx, order.ID)
+	if err != nil {
+		return core.ErrInternalServer
+	}
+
+	// Gabungkan qty per produk (komponen untuk bundle), lalu kunci dengan urutan ProductID yang SAMA
+	// seperti Checkout (anti-deadlock). Produk yang sudah dihapus dari katalog dilewati.
+	restoreQty := make(map[uuid.UUID]int)
+	addStockQty(restoreQty, items, -1)
+	if _, err := s.adjustProductStock(tx, restoreQty); err != nil {
+		return err
+	}
+
+	optionDelta := make(map[uuid.UUID]int)
+	addModifierQty(optionDelta, items, -1)
+	if err := s.adjustModifierStock(tx, optionDelta); err != nil {
+		return err
+	}
+
+	if err := s.repo.CancelOrderWithTx(tx, order.ID, reason, time.Now()); err != nil {
+		return core.ErrInternalServer
+	}
+	if err := s.repo.CloseUnpaidPaymentsWithTx(tx, order.ID, paymentStatus); err != nil {
+		return core.ErrInternalServer
+	}
+
+	history := &core.OrderStatusHistory{
+		ID:         uuid.New(),
+		OrderID:    order.ID,
+		FromStatus: order.OrderStatus,
+		ToStatus:   core.OrderStatusCancelled,
+		ChangedBy:  changedBy,
+		Note:       reason,
+	}
+	if err := s.repo.CreateStatusHistoryWithTx(tx, history); err != nil {
+		return core.ErrInternalServer
+	}
+
+	return nil
+}
+
+// resolveModifiers memvalidasi opsi modifier yang dipilih untuk satu produk dan mengembalikan snapshot-nya.
+// Produk tanpa modifier group hanya menerima pilihan kosong.
+func (s *orderService) resolveModifiers(productID uuid.UUID, optionIDs []uuid.UUID) ([]core.OrderItemModifier, error) {
+	groups, err := s.repo.FindModifierGroups(productID)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	if len(groups) == 0 && len(optionIDs) == 0 {
+		return nil, nil
+	}
+	return core.SelectModifiers(groups, optionIDs)
+}
+
+// resolveVoucher mengambil voucher aktif berdasarkan kode (baca biasa, tanpa lock). Kode kosong = tanpa voucher.
+func (s *orderService) resolveVoucher(code string) (*core.Voucher, error) {
+	if code == "" {
+		return nil, nil
+	}
+	voucher, err := s.repo.FindVoucherByCode(code)
+	if err != nil {
+		return nil, core.ErrVoucherInvalid
+	}
+	if !voucher.IsActive || voucher.ValidUntil.Before(time.Now()) {
+		return nil, core.ErrVoucherInvalid
+	}
+	return voucher, nil
+}
+
+// buildCheckoutItems menyusun item ronde pertama dari request checkout, termasuk validasi pilihan
+// modifier & isi bundle (data menu, baca biasa). Harga belum diisi.
+func (s *orderService) buildCheckoutItems(inputs []CheckoutItemInput) ([]core.OrderItem, error) {
+	items := make([]core.OrderItem, 0, len(inputs))
+	for _, item := range inputs {
+		modifiers, err := s.resolveModifiers(item.ProductID, item.ModifierOptionIDs)
+		if err != nil {
+			return nil, err
+		}
+		components, err := s.resolveBundle(item.ProductID, item.BundleSelections)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, core.OrderItem{
+			ID:         uuid.New(),
+			ProductID:  item.ProductID,
+			Qty:        item.Qty,
+			Notes:      item.Notes,
+			Round:      1,
+			Modifiers:  modifiers,
+			Components: components,
+		})
+	}
+	return items, nil
+}
+
+// priceCheckout adalah pipeline harga order baru, dipakai Checkout dan QuoteCheckout agar quote selalu
+// sama dengan hasil checkout:
+//   - harga satuan = harga produk (promo jika jadwalnya berlaku di zona waktu toko) + selisih modifier;
+//     bundle dihargai sebagai satu baris dengan harga bundle itu sendiri;
+//   - promo otomatis per item, lalu diskon voucher atas subtotal setelah promo;
+//   - platform fee dari profil toko, lalu service charge & pajak (mengisi Charges dan TotalFinalAmount).
+//
+// products harus memuat produk setiap item. Tidak ada yang ditulis ke database; mengembalikan waktu
+// (zona waktu toko) yang dipakai untuk mengevaluasi promo.
+func (s *orderService) priceCheckout(order *core.Order, products map[uuid.UUID]*core.Product, voucher *core.Voucher) (time.Time, error) {
+	priceAt, err := s.promoClock(products)
+	if err != nil {
+		return priceAt, err
+	}
+	order.TotalBasePrice = 0
+	for i := range order.Items {
+		product, ok := products[order.Items[i].ProductID]
+		if !ok {
+			return priceAt, fmt.Errorf("produk dengan ID %s tidak ditemukan", order.Items[i].ProductID)
+		}
+		order.Items[i].UnitPrice = calculateItemUnitPrice(product, &order.Items[i], priceAt)
+		order.Items[i].Subtotal = order.Items[i].UnitPrice * order.Items[i].Qty
+		order.TotalBasePrice += order.Items[i].Subtotal
+	}
+
+	promoDiscount, err := s.applyPromotions(order.Items, priceAt, voucher != nil)
+	if err != nil {
+		return priceAt, err
+	}
+	order.TotalPromoDiscount = promoDiscount
+	order.TotalDiscount = promoDiscount
+	if voucher != nil {
+		if order.TotalBasePrice-promoDiscount < voucher.MinOrderAmount {
+			return priceAt, core.ErrVoucherMinOrder
+		}
+		order.TotalDiscount += calculateDiscount(voucher, order.TotalBasePrice-promoDiscount)
+	}
+
+	order.PlatformFee = s.repo.GetStoreMarkupFee()
+	return priceAt, s.applyCharges(order, order.Items)
+}
+
+// applyPromotions mengevaluasi promo otomatis atas items pada waktu at (zona waktu toko), mengisi
+// PromotionID/PromotionName/PromoDiscount setiap item, dan mengembalikan total diskon promo.
+// UnitPrice & Subtotal item harus sudah final.
+func (s *orderService) applyPromotions(items []core.OrderItem, at time.Time, hasVoucher bool) (int, error) {
+	promotions, err := s.repo.ListActivePromotions()
+	if err != nil {
+		return 0, core.ErrInternalServer
+	}
+
+	var discounts []core.PromotionDiscount
+	if len(promotions) > 0 && len(items) > 0 {
+		productIDs := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		categories, err := s.repo.FindProductCategories(productIDs)
+		if err != nil {
+			return 0, core.ErrInternalServer
+		}
+		lines := make([]core.PromotionLine, len(items))
+		for i, item := range items {
+			lines[i] = core.PromotionLine{
+				ProductID:  item.ProductID,
+				CategoryID: categories[item.ProductID],
+				UnitPrice:  item.UnitPrice,
+				Qty:        item.Qty,
+			}
+		}
+		discounts = core.ApplyPromotions(promotions, lines, at, hasVoucher)
+	}
+
+	total := 0
+	for i := range items {
+		items[i].PromotionID, items[i].PromotionName, items[i].PromoDiscount = nil, "", 0
+		if i < len(discounts) && discounts[i].PromotionID != nil {
+			items[i].PromotionID = discounts[i].PromotionID
+			items[i].PromotionName = discounts[i].PromotionName
+			items[i].PromoDiscount = discounts[i].Amount
+			total += discounts[i].Amount
+		}
+	}
+	return total, nil
+}
+
+// storeLocation mengembalikan zona waktu toko; tanpa profil toko dipakai zona waktu server.
+func (s *orderService) storeLocation() *time.Location {
+	profile, err := s.repo.GetStoreProfile()
+	if err != nil {
+		return time.Local
+	}
+	return profile.Location()
+}
+
+// promoClock memuat jadwal promo produk yang akan dihargai lalu mengembalikan waktu sekarang
+// di zona waktu toko, sehingga promo tidak bergantung pada zona waktu server.
+func (s *orderService) promoClock(products map[uuid.UUID]*core.Product) (time.Time, error) {
+	now := time.Now().In(s.storeLocation())
+	if len(products) == 0 {
+		return now, nil
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(products))
+	for id := range products {
+		productIDs = append(productIDs, id)
+	}
+	schedules, err := s.repo.FindPromoSchedules(productIDs)
+	if err != nil {
+		return now, core.ErrInternalServer
+	}
+	for id, product := range products {
+		product.PromoSchedules = schedules[id]
+	}
+	return now, nil
+}
+
+// applyCharges menghitung service charge & pajak order dari item-nya dengan aturan yang aktif saat ini,
+// lalu mengisi Charges, total pajak, dan TotalFinalAmount. TotalBasePrice, TotalDiscount, dan PlatformFee
+// harus sudah final. Platform fee tidak dikenai pajak.
+func (s *orderService) applyCharges(order *core.Order, items []core.OrderItem) error {
+	rules, err := s.repo.ListActiveChargeRules()
+	if err != nil {
+		return core.ErrInternalServer
+	}
+
+	var breakdown core.ChargeBreakdown
+	if len(rules) > 0 && len(items) > 0 {
+		productIDs := make([]uuid.UUID, 0, len(items))
+		for _, item := range items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		categories, err := s.repo.FindProductCategories(productIDs)
+		if err != nil {
+			return core.ErrInternalServer
+		}
+		lines := make([]core.ChargeLine, len(items))
+		for i, item := range items {
+			lines[i] = core.ChargeLine{CategoryID: categories[item.ProductID], Subtotal: item.Subtotal, Discount: item.PromoDiscount}
+		}
+		breakdown = core.CalculateCharges(rules, lines, order.TotalDiscount-order.TotalPromoDiscount)
+	}
+
+	for i := range breakdown.Charges {
+		breakdown.Charges[i].OrderID = order.ID
+	}
+	order.Charges = breakdown.Charges
+	order.TotalServiceCharge = breakdown.ServiceCharge
+	order.TotalTax = breakdown.Tax
+	order.TotalTaxIncluded = breakdown.TaxIncluded
+	order.TotalFinalAmount = order.TotalBasePrice - order.TotalDiscount + breakdown.Added() + order.PlatformFee
+	return nil
+}
+
+// resolveBundle menentukan isi bundle untuk satu produk dan mengembalikan snapshot komponennya.
+// Produk biasa (tanpa slot bundle) hanya menerima pilihan kosong.
+func (s *orderService) resolveBundle(productID uuid.UUID, inputs []BundleSelectionInput) ([]core.OrderItemComponent, error) {
+	slots, err := s.repo.FindBundleSlots(productID)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	if len(slots) == 0 && len(inputs) == 0 {
+		return nil, nil
+	}
+	selections := make([]core.BundleSelection, len(inputs))
+	for i, in := range inputs {
+		selections[i] = core.BundleSelection{SlotID: in.SlotID, ProductID: in.ProductID}
+	}
+	return core.SelectBundleComponents(slots, selections)
+}
+
+// adjustProductStock memotong (delta > 0) atau mengembalikan (delta < 0) stok produk. Semua produk
+// di delta dikunci dengan urutan LessProductID (anti-deadlock), termasuk yang delta-nya 0, lalu
+// dikembalikan agar bisa dipakai menghitung harga. Produk yang sudah dihapus dari katalog dilewati
+// selama stoknya tidak perlu dipotong.
+func (s *orderService) adjustProductStock(tx *gorm.DB, delta map[uuid.UUID]int) (map[uuid.UUID]*core.Product, error) {
+	productIDs := make([]uuid.UUID, 0, len(delta))
+	for productID := range delta {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool {
+		return core.LessProductID(productIDs[i], productIDs[j])
+	})
+
+	products := make(map[uuid.UUID]*core.Product, len(productIDs))
+	for _, productID := range productIDs {
+		product, err := s.repo.LockAndGetProduct(tx, productID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if delta[productID] > 0 {
+					return nil, fmt.Errorf("produk dengan ID %s tidak ditemukan", productID)
+				}
+				continue
+			}
+			return nil, core.ErrInternalServer
+		}
+		products[productID] = product
+
+		if delta[productID] == 0 {
+			continue
+		}
+		if product.Stock < delta[productID] {
+			return nil, fmt.Errorf("%w: %s (tersisa %d)", core.ErrInsufficientStock, product.Name, product.Stock)
+		}
+		product.Stock -= delta[productID]
+		if err := s.repo.DeductStockWithTx(tx, product); err != nil {
+			return nil, core.ErrInternalServer
+		}
+	}
+	return products, nil
+}
+
+// adjustModifierStock memotong (delta > 0) atau mengembalikan (delta < 0) stok opsi modifier
+// yang TrackStock. Opsi dikunci dengan urutan LessModifierOptionID, SETELAH produk (anti-deadlock).
+func (s *orderService) adjustModifierStock(tx *gorm.DB, delta map[uuid.UUID]int) error {
+	var optionIDs []uuid.UUID
+	for optionID, qty := range delta {
+		if qty != 0 {
+			optionIDs = append(optionIDs, optionID)
+		}
+	}
+	sort.Slice(optionIDs, func(i, j int) bool {
+		return core.LessModifierOptionID(optionIDs[i], optionIDs[j])
+	})
+
+	for _, optionID := range optionIDs {
+		option, err := s.repo.LockModifierOption(tx, optionID)
+		if err != nil {
+			// Opsi sudah dihapus dari menu: tidak ada stok yang perlu disesuaikan
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if delta[optionID] > 0 {
+					return fmt.Errorf("%w: opsi %s sudah tidak tersedia", core.ErrInvalidModifier, optionID)
+				}
+				continue
+			}
+			return core.ErrInternalServer
+		}
+		if !option.TrackStock {
+			continue
+		}
+		if option.Stock < delta[optionID] {
+			return fmt.Errorf("%w: %s (tersisa %d)", core.ErrInsufficientStock, option.Name, option.Stock)
+		}
+		option.Stock -= delta[optionID]
+		if err := s.repo.SaveModifierOptionWithTx(tx, option); err != nil {
+			return core.ErrInternalServer
+		}
+	}
+	return nil
+}
+
+// ===========================================
+// HELPER FUNCTIONS (private)
+// ===========================================
+
+// addStockQty menambahkan sign × qty setiap item ke kebutuhan stok per produk (lihat OrderItem.AddStockQty).
+// Produk item itu sendiri selalu tercatat (minimal 0) agar ikut dikunci dan bisa dihargai, termasuk bundle.
+func addStockQty(into map[uuid.UUID]int, items []core.OrderItem, sign int) {
+	for _, item := range items {
+		if _, ok := into[item.ProductID]; !ok {
+			into[item.ProductID] = 0
+		}
+		item.AddStockQty(into, sign*item.Qty)
+	}
+}
+
+// addModifierQty menambahkan sign × qty item ke setiap opsi modifier yang dipilih item tersebut.
+func addModifierQty(into map[uuid.UUID]int, items []core.OrderItem, sign int) {
+	for _, item := range items {
+		for _, m := range item.Modifiers {
+			into[m.ModifierOptionID] += sign * item.Qty
+		}
+	}
+}
+
+// partitionItemsByGroups memindahkan qty yang dipilih pelanggan ke setiap group.
+// Hasil [0] adalah sisa item order induk (ID lama dipertahankan), [1:] baris baru per bill anak.
+func partitionItemsByGroups(items []core.OrderItem, groups []SplitGroupInput) ([][]core.OrderItem, error) {
+	remaining := make(map[uuid.UUID]int, len(items))
+	byID := make(map[uuid.UUID]core.OrderItem, len(items))
+	for _, item := range items {
+		remaining[item.ID] = item.Qty
+		byID[item.ID] = item
+	}
+
+	given := make(map[uuid.UUID]int, len(items)) // Diskon promo yang ikut pindah ke bill anak
+	type linePos struct{ part, line int }
+	lastLine := make(map[uuid.UUID]linePos, len(items))
+	parts := make([][]core.OrderItem, 1, len(groups)+1)
+	for _, group := range groups {
+		var lines []core.OrderItem
+		for _, input := range group.Items {
+			item, ok := byID[input.OrderItemID]
+			if !ok {
+				return nil, fmt.Errorf("%w: item %s bukan milik order ini", core.ErrInvalidSplit, input.OrderItemID)
+			}
+			if input.Qty > remaining[item.ID] {
+				return nil, fmt.Errorf("%w: qty item %s melebihi sisa %d", core.ErrInvalidSplit, item.ID, remaining[item.ID])
+			}
+			remaining[item.ID] -= input.Qty
+			line := splitLine(item, input.Qty)
+			given[item.ID] += line.PromoDiscount
+			lastLine[item.ID] = linePos{part: len(parts), line: len(lines)}
+			lines = append(lines, line)
+		}
+		parts = append(parts, lines)
+	}
+
+	for _, item := range items {
+		if remaining[item.ID] > 0 {
+			item.Qty = remaining[item.ID]
+			item.Subtotal = item.UnitPrice * item.Qty
+			item.PromoDiscount -= given[item.ID]
+			parts[0] = append(parts[0], item)
+		} else if pos, ok := lastLine[item.ID]; ok {
+			// Baris pindah seluruhnya: sisa pembulatan diskon promo ikut potongan terakhir
+			parts[pos.part][pos.line].PromoDiscount += item.PromoDiscount - given[item.ID]
+		}
+	}
+	return parts, nil
+}
+
+// partitionItemsEvenly membagi setiap unit item ke ways bill dengan algoritma greedy
+// (unit termahal lebih dulu, selalu ke bill dengan subtotal terkecil) agar nominal serata mungkin.
+// Hasil [0] adalah bagian order induk (ID lama dipertahankan), [1:] baris baru per bill anak.
+func partitionItemsEvenly(items []core.OrderItem, ways int) ([][]core.OrderItem, error) {
+	type unit struct {
+		index int
+		price int
+	}
+	var units []unit
+	for i, item := range items {
+		for q := 0; q < item.Qty; q++ {
+			units = append(units, unit{index: i, price: item.UnitPrice})
+		}
+	}
+	if len(units) < ways {
+		return nil, fmt.Errorf("%w: hanya ada %d item untuk dibagi ke %d bill", core.ErrInvalidSplit, len(units), ways)
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].price > units[j].price
+	})
+
+	sums := make([]int, ways)
+	counts := make([][]int, ways)
+	for b := range counts {
+		counts[b] = make([]int, len(items))
+	}
+	for _, u := range units {
+		best := 0
+		for b := 1; b < ways; b++ {
+			if sums[b] < sums[best] {
+				best = b
+			}
+		}
+		sums[best] += u.price
+		counts[best][u.index]++
+	}
+
+	// Diskon promo ikut dibagi per qty; sisa pembulatan tetap di order induk, atau di bill anak
+	// terakhir yang menerima item jika semua unitnya pindah
+	given := make([]int, len(items))
+	promoByPart := make([][]int, ways)
+	for b := range promoByPart {
+		promoByPart[b] = make([]int, len(items))
+	}
+	for i, item := range items {
+		last := 0
+		for b := 1; b < ways; b++ {
+			if counts[b][i] > 0 {
+				promoByPart[b][i] = promoShare(item, counts[b][i])
+				given[i] += promoByPart[b][i]
+				last = b
+			}
+		}
+		if counts[0][i] > 0 {
+			promoByPart[0][i] = item.PromoDiscount - given[i]
+		} else {
+			promoByPart[last][i] += item.PromoDiscount - given[i]
+		}
+	}
+
+	parts := make([][]core.OrderItem, ways)
+	for b := 0; b < ways; b++ {
+		for i, item := range items {
+			qty := counts[b][i]
+			if qty == 0 {
+				continue
+			}
+			if b == 0 {
+				item.Qty = qty
+				item.Subtotal = item.UnitPrice * qty
+				item.PromoDiscount = promoByPart[0][i]
+				parts[0] = append(parts[0], item)
+				continue
+			}
+			line := splitLine(item, qty)
+			line.PromoDiscount = promoByPart[b][i]
+			parts[b] = append(parts[b], line)
+		}
+		if len(parts[b]) == 0 {
+			return nil, fmt.Errorf("%w: tidak cukup item untuk %d bill", core.ErrInvalidSplit, ways)
+		}
+	}
+	return parts, nil
+}
+
+// promoShare adalah bagian diskon promo item untuk qty unit (dibulatkan ke bawah).
+func promoShare(item core.OrderItem, qty int) int {
+	if item.Qty <= 0 || item.PromoDiscount <= 0 {
+		return 0
+	}
+	return item.PromoDiscount * qty / item.Qty
+}
+
+// splitLine membuat baris item baru (untuk bill anak) dengan harga satuan, modifier, isi bundle,
+// dan promo yang sama; diskon promo ikut dibagi sesuai qty.
+func splitLine(item core.OrderItem, qty int) core.OrderItem {
+	modifiers := make([]core.OrderItemModifier, len(item.Modifiers))
+	for i, m := range item.Modifiers {
+		m.ID = uuid.New()
+		m.OrderItemID = uuid.Nil
+		modifiers[i] = m
+	}
+	components := make([]core.OrderItemComponent, len(item.Components))
+	for i, c := range item.Components {
+		c.ID = uuid.New()
+		c.OrderItemID = uuid.Nil
+		components[i] = c
+	}
+	return core.OrderItem{
+		ID:         uuid.New(),
+		ProductID:  item.ProductID,
+		Qty:        qty,
+		UnitPrice:  item.UnitPrice,
+		Subtotal:   item.UnitPrice * qty,
+		Notes:      item.Notes,
+		Round:      item.Round,
+		Modifiers:  modifiers,
+		Components: components,
+
+		PromotionID:   item.PromotionID,
+		PromotionName: item.PromotionName,
+		PromoDiscount: promoShare(item, qty),
+	}
+}
+
+// lastRound mengembalikan ronde tertinggi di antara item (minimal 1).
+func lastRound(items []core.OrderItem) int {
+	round := 1
+	for _, item := range items {
+		if item.Round > round {
+			round = item.Round
+		}
+	}
+	return round
+}
+
+// hashCheckoutRequest membuat sidik jari body checkout (IdempotencyKey tidak ikut karena json:"-").
+// Item diurutkan ke bentuk kanonis lebih dulu sehingga keranjang yang sama dengan urutan item
+// berbeda menghasilkan hash yang sama.
+func hashCheckoutRequest(req CheckoutRequest) string {
+	type keyedItem struct {
+		key  string
+		item CheckoutItemInput
+	}
+	keyed := make([]keyedItem, len(req.Items))
+	for i, item := range req.Items {
+		encoded, _ := json.Marshal(item)
+		keyed[i] = keyedItem{key: string(encoded), item: item}
+	}
+	sort.Slice(keyed, func(i, j int) bool { return keyed[i].key < keyed[j].key })
+	req.Items = make([]CheckoutItemInput, len(keyed)) // req adalah salinan; slice milik caller tidak berubah
+	for i := range keyed {
+		req.Items[i] = keyed[i].item
+	}
+
+	payload, _ := json.Marshal(req)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// generateTrackingToken membuat rahasia acak 256-bit (hex) yang tidak bisa ditebak dari ID/antrean.
+func generateTrackingToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// encodeOrderCursor membungkus posisi keyset (created_at, id) menjadi string opaque untuk client.
+func encodeOrderCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeOrderCursor adalah kebalikan encodeOrderCursor.
+func decodeOrderCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, errors.New("format cursor salah")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+	return createdAt, id, nil
+}
+
+// calculateUnitPrice menentukan harga jual berdasarkan jadwal promo pada waktu at (zona waktu toko).
+func calculateUnitPrice(product *core.Product, at time.Time) int {
+	return product.PriceAt(at)
+}
 
-	return product.NormalPrice
+// calculateItemUnitPrice adalah harga produk (promo-aware) ditambah selisih semua modifier item.
+// Modifier dengan delta negatif tidak bisa membuat harga satuan di bawah nol.
+func calculateItemUnitPrice(product *core.Product, item *core.OrderItem, at time.Time) int {
+	return max(calculateUnitPrice(product, at)+item.ModifierDelta(), 0)
 }
 
 // calculateDiscount menghitung jumlah diskon berdasarkan tipe voucher.
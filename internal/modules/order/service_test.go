@@ -0,0 +1,1328 @@
+package order_test
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"go-fiber-pos/internal/core"
+
+	"go-fiber-pos/internal/modules/order"
+	"go-fiber-pos/internal/modules/order/mocks"
+	"go-fiber-pos/internal/testutil"
+
+	"github.com/google/uuid"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+// stubPricing menyiapkan sumber harga tanpa promo, jadwal, maupun charge rule,
+// dan toko tanpa profil (zona waktu server).
+func stubPricing(mockRepo *mocks.MockOrderRepository) {
+	mockRepo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	mockRepo.EXPECT().FindPromoSchedules(gomock.Any()).Return(map[uuid.UUID][]core.PromoSchedule{}, nil).AnyTimes()
+	mockRepo.EXPECT().ListActivePromotions().Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().ListActiveChargeRules().Return(nil, nil).AnyTimes()
+}
+
+func TestUpdateOrderItem_Gomock(t *testing.T) {
+	productID := uuid.New()
+	itemID := uuid.New()
+	item := core.OrderItem{ID: itemID, ProductID: productID, Qty: 2, UnitPrice: 20000, Subtotal: 40000}
+
+	testCases := []struct {
+		name              string
+		order             core.Order
+		req               order.UpdateOrderItemRequest
+		buildStubs        func(mockRepo *mocks.MockOrderRepository, o *core.Order, saved *core.Order)
+		expectedError     error
+		expectedBasePrice int
+	}{
+		{
+			name:  "Gagal - Masih ada payment UNPAID",
+			order: core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalBasePrice: 40000, TotalFinalAmount: 40000},
+			req:   order.UpdateOrderItemRequest{Qty: 3},
+			buildStubs: func(mockRepo *mocks.MockOrderRepository, o *core.Order, saved *core.Order) {
+				// Link QRIS untuk nominal lama masih aktif
+				mockRepo.EXPECT().CountUnpaidPaymentsWithTx(gomock.Any(), o.ID).Return(int64(1), nil).Times(1)
+			},
+			expectedError: core.ErrPaymentInProgress,
+		},
+		{
+			name:  "Sukses - Edit setelah payment di-void",
+			order: core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalBasePrice: 40000, TotalFinalAmount: 40000},
+			req:   order.UpdateOrderItemRequest{Qty: 3},
+			buildStubs: func(mockRepo *mocks.MockOrderRepository, o *core.Order, saved *core.Order) {
+				// Payment yang di-void berstatus FAILED sehingga tidak lagi dihitung
+				mockRepo.EXPECT().CountUnpaidPaymentsWithTx(gomock.Any(), o.ID).Return(int64(0), nil).Times(1)
+				mockRepo.EXPECT().FindItemsByOrderIDWithTx(gomock.Any(), o.ID).Return([]core.OrderItem{item}, nil).Times(1)
+				mockRepo.EXPECT().LockAndGetProduct(gomock.Any(), productID).Return(&core.Product{ID: productID, Name: "Kopi Susu", NormalPrice: 20000, Stock: 10}, nil).Times(1)
+				mockRepo.EXPECT().DeductStockWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, p *core.Product) error {
+					assert.Equal(t, 9, p.Stock)
+					return nil
+				}).Times(1)
+				stubPricing(mockRepo)
+				mockRepo.EXPECT().SaveItemWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, i *core.OrderItem) error {
+					assert.Equal(t, 3, i.Qty)
+					assert.Equal(t, 60000, i.Subtotal)
+					return nil
+				}).Times(1)
+				mockRepo.EXPECT().UpdateOrderTotalsWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, updated *core.Order) error {
+					*saved = *updated
+					return nil
+				}).Times(1)
+				mockRepo.EXPECT().FindByID(o.ID).Return(o, nil).Times(1)
+			},
+			expectedBasePrice: 60000,
+		},
+		{
+			name:  "Gagal - Order sudah dibayar",
+			order: core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusPaid, TotalBasePrice: 40000, TotalFinalAmount: 40000},
+			req:   order.UpdateOrderItemRequest{Qty: 3},
+			buildStubs: func(mockRepo *mocks.MockOrderRepository, o *core.Order, saved *core.Order) {
+			},
+			expectedError: core.ErrOrderNotEditable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			o := tc.order
+			o.ID = uuid.New()
+			var saved core.Order
+
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockOrder(gomock.Any(), o.ID).Return(&o, nil).Times(1)
+			tc.buildStubs(mockRepo, &o, &saved)
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			_, err := service.UpdateOrderItem(o.ID, itemID, tc.req)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+				assert.Equal(t, 1, txdb.Rollbacks())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, txdb.Commits())
+			assert.Equal(t, tc.expectedBasePrice, saved.TotalBasePrice)
+			assert.Equal(t, tc.expectedBasePrice, saved.TotalFinalAmount)
+		})
+	}
+}
+
+// billTotals adalah ringkasan nominal satu bill hasil split.
+type billTotals struct {
+	Base     int
+	Discount int
+	Fee      int
+	Final    int
+}
+
+func TestSplitOrder_Gomock(t *testing.T) {
+	latte := core.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Qty: 2, UnitPrice: 20000, Subtotal: 40000}
+	cookie := core.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Qty: 1, UnitPrice: 10000, Subtotal: 10000}
+	changedBy := uuid.New()
+
+	testCases := []struct {
+		name             string
+		req              order.SplitOrderRequest
+		unpaidPayments   int64
+		expectedParent   billTotals
+		expectedChildren []billTotals
+		expectedDeleted  []uuid.UUID
+		expectedError    error
+	}{
+		{
+			name: "Sukses - Split per item membagi voucher dan platform fee proporsional",
+			req: order.SplitOrderRequest{Mode: order.SplitModeItems, Groups: []order.SplitGroupInput{
+				{Items: []order.SplitItemInput{{OrderItemID: cookie.ID, Qty: 1}}},
+			}},
+			expectedParent:   billTotals{Base: 40000, Discount: 4000, Fee: 800, Final: 36800},
+			expectedChildren: []billTotals{{Base: 10000, Discount: 1000, Fee: 200, Final: 9200}},
+			expectedDeleted:  []uuid.UUID{cookie.ID},
+		},
+		{
+			name: "Sukses - Split per qty memecah baris item",
+			req: order.SplitOrderRequest{Mode: order.SplitModeItems, Groups: []order.SplitGroupInput{
+				{Items: []order.SplitItemInput{{OrderItemID: latte.ID, Qty: 1}}},
+				{Items: []order.SplitItemInput{{OrderItemID: latte.ID, Qty: 1}}},
+			}},
+			expectedParent: billTotals{Base: 10000, Discount: 1000, Fee: 200, Final: 9200},
+			expectedChildren: []billTotals{
+				{Base: 20000, Discount: 2000, Fee: 400, Final: 18400},
+				{Base: 20000, Discount: 2000, Fee: 400, Final: 18400},
+			},
+			expectedDeleted: []uuid.UUID{latte.ID},
+		},
+		{
+			name: "Gagal - Masih ada payment UNPAID",
+			req: order.SplitOrderRequest{Mode: order.SplitModeItems, Groups: []order.SplitGroupInput{
+				{Items: []order.SplitItemInput{{OrderItemID: cookie.ID, Qty: 1}}},
+			}},
+			unpaidPayments: 1,
+			expectedError:  core.ErrPaymentInProgress,
+		},
+		{
+			name: "Gagal - Semua item dipindah dari order induk",
+			req: order.SplitOrderRequest{Mode: order.SplitModeItems, Groups: []order.SplitGroupInput{
+				{Items: []order.SplitItemInput{{OrderItemID: latte.ID, Qty: 2}, {OrderItemID: cookie.ID, Qty: 1}}},
+			}},
+			expectedError: core.ErrInvalidSplit,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			voucherID := uuid.New()
+			parent := core.Order{
+				ID:               uuid.New(),
+				VoucherID:        &voucherID,
+				OrderSource:      core.OrderSourceCashier,
+				QueueNumber:      "POS-001",
+				OrderStatus:      core.OrderStatusPreparing,
+				PaymentStatus:    core.PaymentStatusUnpaid,
+				TotalBasePrice:   50000,
+				TotalDiscount:    5000,
+				PlatformFee:      1000,
+				TotalFinalAmount: 46000,
+			}
+
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			locked := parent
+			mockRepo.EXPECT().LockOrder(gomock.Any(), parent.ID).Return(&locked, nil).Times(1)
+			mockRepo.EXPECT().CountUnpaidPaymentsWithTx(gomock.Any(), parent.ID).Return(tc.unpaidPayments, nil).Times(1)
+			if tc.unpaidPayments == 0 {
+				mockRepo.EXPECT().FindItemsByOrderIDWithTx(gomock.Any(), parent.ID).Return([]core.OrderItem{latte, cookie}, nil).Times(1)
+			}
+
+			var savedParent core.Order
+			var children []core.Order
+			var histories []core.OrderStatusHistory
+			var deleted []uuid.UUID
+			if tc.expectedError == nil {
+				stubPricing(mockRepo)
+				mockRepo.EXPECT().SaveItemWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				mockRepo.EXPECT().DeleteItemWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, itemID uuid.UUID) error {
+					deleted = append(deleted, itemID)
+					return nil
+				}).Times(len(tc.expectedDeleted))
+				mockRepo.EXPECT().UpdateOrderTotalsWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+					savedParent = *o
+					return nil
+				}).Times(1)
+				mockRepo.EXPECT().GetNextQueueNumber(gomock.Any(), core.OrderSourceCashier).Return("POS-002", nil).Times(len(tc.expectedChildren))
+				mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+					children = append(children, *o)
+					return nil
+				}).Times(len(tc.expectedChildren))
+				mockRepo.EXPECT().CreateStatusHistoryWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, h *core.OrderStatusHistory) error {
+					histories = append(histories, *h)
+					return nil
+				}).Times(len(tc.expectedChildren))
+				mockRepo.EXPECT().FindByID(gomock.Any()).Return(&locked, nil).Times(1 + len(tc.expectedChildren))
+			}
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			_, err := service.SplitOrder(parent.ID, tc.req, &changedBy)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+				assert.Equal(t, 1, txdb.Rollbacks())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, txdb.Commits())
+			assert.ElementsMatch(t, tc.expectedDeleted, deleted)
+			assert.Equal(t, tc.expectedParent, billTotals{savedParent.TotalBasePrice, savedParent.TotalDiscount, savedParent.PlatformFee, savedParent.TotalFinalAmount})
+
+			// Total semua bill tetap sama dengan tagihan awal
+			sum := savedParent.TotalFinalAmount
+			for i, child := range children {
+				assert.Equal(t, tc.expectedChildren[i], billTotals{child.TotalBasePrice, child.TotalDiscount, child.PlatformFee, child.TotalFinalAmount})
+				assert.Equal(t, &parent.ID, child.ParentOrderID)
+				assert.Equal(t, core.PaymentStatusUnpaid, child.PaymentStatus)
+				for _, item := range child.Items {
+					assert.Equal(t, child.ID, item.OrderID)
+				}
+				sum += child.TotalFinalAmount
+
+				// Satu riwayat status per bill anak yang menunjuk order induk
+				assert.Equal(t, child.ID, histories[i].OrderID)
+				assert.Equal(t, parent.OrderStatus, histories[i].FromStatus)
+				assert.Equal(t, child.OrderStatus, histories[i].ToStatus)
+				assert.Equal(t, &changedBy, histories[i].ChangedBy)
+				assert.Contains(t, histories[i].Note, parent.QueueNumber)
+				assert.Contains(t, histories[i].Note, parent.ID.String())
+			}
+			assert.Equal(t, parent.TotalFinalAmount, sum)
+		})
+	}
+}
+
+// eventRecorder mencatat tipe event yang dipublikasikan service.
+type eventRecorder struct{ types []string }
+
+func (r *eventRecorder) Publish(event core.OrderEvent) { r.types = append(r.types, event.Type) }
+
+func TestReleaseDueOrders_Gomock(t *testing.T) {
+	now := time.Date(2026, 1, 2, 9, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name           string
+		profile        *core.StoreProfile
+		expectedCutoff time.Time
+		due            []core.Order
+		expectedEvents []string
+	}{
+		{
+			name:           "Sukses - Pre-order dalam lead time toko dirilis",
+			profile:        &core.StoreProfile{PreOrderLeadMinutes: 45},
+			expectedCutoff: now.Add(45 * time.Minute),
+			due:            []core.Order{{ID: uuid.New()}, {ID: uuid.New()}},
+			expectedEvents: []string{core.EventOrderReleased, core.EventOrderReleased},
+		},
+		{
+			name:           "Sukses - Tanpa profil toko memakai lead time default",
+			expectedCutoff: now.Add(core.DefaultPreOrderLead),
+			due:            []core.Order{{ID: uuid.New()}},
+			expectedEvents: []string{core.EventOrderReleased},
+		},
+		{
+			name:           "Sukses - Tidak ada pre-order jatuh tempo",
+			profile:        &core.StoreProfile{PreOrderLeadMinutes: 45},
+			expectedCutoff: now.Add(45 * time.Minute),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+			events := &eventRecorder{}
+
+			if tc.profile != nil {
+				mockRepo.EXPECT().GetStoreProfile().Return(tc.profile, nil).Times(1)
+			} else {
+				mockRepo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).Times(1)
+			}
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockDueScheduledWithTx(gomock.Any(), tc.expectedCutoff, gomock.Any()).Return(tc.due, nil).Times(1)
+			if len(tc.due) > 0 {
+				ids := make([]uuid.UUID, 0, len(tc.due))
+				for _, o := range tc.due {
+					ids = append(ids, o.ID)
+				}
+				mockRepo.EXPECT().MarkReleasedWithTx(gomock.Any(), ids, now).Return(nil).Times(1)
+			}
+
+			service := order.NewOrderService(mockRepo, validator.New(), events)
+
+			released, err := service.ReleaseDueOrders(now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tc.due), released)
+			assert.Equal(t, tc.expectedEvents, events.types)
+			assert.Equal(t, 1, txdb.Commits()+txdb.Rollbacks())
+		})
+	}
+}
+
+func TestExpireUnpaidOrders_Gomock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	txdb := testutil.NewTxDB(t)
+	events := &eventRecorder{}
+
+	now := time.Date(2026, 1, 2, 9, 0, 0, 0, time.Local)
+	cutoff := now.Add(-15 * time.Minute)
+	stale := now.Add(-time.Hour)
+	locked := uuid.New()
+	expired := &core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, CreatedAt: stale}
+	paid := &core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusPaid, CreatedAt: stale}
+
+	mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+	mockRepo.EXPECT().GetStoreProfile().Return(&core.StoreProfile{UnpaidOrderTTLMinutes: 15}, nil).Times(1)
+	mockRepo.EXPECT().FindExpiredUnpaidOrderIDs(cutoff, gomock.Any()).Return([]uuid.UUID{locked, expired.ID, paid.ID}, nil).Times(1)
+
+	// Order pertama gagal dikunci: dicatat lalu dilewati, order berikutnya tetap diproses
+	mockRepo.EXPECT().LockOrder(gomock.Any(), locked).Return(nil, errors.New("lock timeout")).Times(1)
+
+	mockRepo.EXPECT().LockOrder(gomock.Any(), expired.ID).Return(expired, nil).Times(1)
+	mockRepo.EXPECT().FindItemsByOrderIDWithTx(gomock.Any(), expired.ID).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().CancelOrderWithTx(gomock.Any(), expired.ID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().CloseUnpaidPaymentsWithTx(gomock.Any(), expired.ID, core.PaymentStatusExpired).Return(nil).Times(1)
+	mockRepo.EXPECT().CreateStatusHistoryWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().FindByID(expired.ID).Return(expired, nil).Times(1)
+
+	// Sudah dilunasi webhook setelah dipilih sebagai kandidat: dilewati tanpa error
+	mockRepo.EXPECT().LockOrder(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+	service := order.NewOrderService(mockRepo, validator.New(), events)
+
+	count, err := service.ExpireUnpaidOrders(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, txdb.Commits())
+	assert.Equal(t, 2, txdb.Rollbacks())
+	assert.Equal(t, []string{core.EventOrderStatusChanged}, events.types)
+}
+
+func TestSplitOrder_PreOrder(t *testing.T) {
+	scheduledFor := time.Now().Add(3 * time.Hour)
+	releasedAt := time.Now()
+
+	testCases := []struct {
+		name           string
+		releasedAt     *time.Time
+		expectedEvents []string
+	}{
+		{
+			name:           "Sukses - Pre-order belum dirilis tidak dikirim ke dapur",
+			releasedAt:     nil,
+			expectedEvents: nil,
+		},
+		{
+			name:           "Sukses - Pre-order sudah dirilis dikirim ke dapur",
+			releasedAt:     &releasedAt,
+			expectedEvents: []string{core.EventOrderItemsChanged, core.EventOrderCreated},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+			events := &eventRecorder{}
+
+			latte := core.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Qty: 2, UnitPrice: 20000, Subtotal: 40000}
+			parent := core.Order{
+				ID:               uuid.New(),
+				OrderSource:      core.OrderSourceCashier,
+				QueueNumber:      "POS-001",
+				OrderStatus:      core.OrderStatusPending,
+				PaymentStatus:    core.PaymentStatusUnpaid,
+				TotalBasePrice:   40000,
+				TotalFinalAmount: 40000,
+				ScheduledFor:     &scheduledFor,
+				ReleasedAt:       tc.releasedAt,
+			}
+
+			var children []core.Order
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockOrder(gomock.Any(), parent.ID).Return(&parent, nil).Times(1)
+			mockRepo.EXPECT().CountUnpaidPaymentsWithTx(gomock.Any(), parent.ID).Return(int64(0), nil).Times(1)
+			mockRepo.EXPECT().FindItemsByOrderIDWithTx(gomock.Any(), parent.ID).Return([]core.OrderItem{latte}, nil).Times(1)
+			stubPricing(mockRepo)
+			mockRepo.EXPECT().SaveItemWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			mockRepo.EXPECT().UpdateOrderTotalsWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			mockRepo.EXPECT().GetNextQueueNumber(gomock.Any(), core.OrderSourceCashier).Return("POS-002", nil).Times(1)
+			mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+				children = append(children, *o)
+				return nil
+			}).Times(1)
+			mockRepo.EXPECT().CreateStatusHistoryWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			mockRepo.EXPECT().FindByID(gomock.Any()).Return(&parent, nil).Times(2)
+
+			service := order.NewOrderService(mockRepo, validator.New(), events)
+
+			_, err := service.SplitOrder(parent.ID, order.SplitOrderRequest{Mode: order.SplitModeEven, Ways: 2}, nil)
+
+			assert.NoError(t, err)
+			if assert.Len(t, children, 1) {
+				// Bill anak ikut jadwal ambil dan status rilis order induk
+				assert.Equal(t, parent.ScheduledFor, children[0].ScheduledFor)
+				assert.Equal(t, parent.ReleasedAt, children[0].ReleasedAt)
+			}
+			assert.Equal(t, tc.expectedEvents, events.types)
+		})
+	}
+}
+
+func TestMergeOrders_Gomock(t *testing.T) {
+	scheduledFor := time.Now().Add(3 * time.Hour)
+	releasedAt := time.Now()
+	hemat10, gratisOngkir := uuid.New(), uuid.New()
+
+	testCases := []struct {
+		name          string
+		orders        []core.Order
+		expectedError error
+	}{
+		{
+			name: "Gagal - Pre-order belum dirilis",
+			orders: []core.Order{
+				{QueueNumber: "POS-001", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid},
+				{QueueNumber: "POS-002", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, ScheduledFor: &scheduledFor},
+			},
+			expectedError: core.ErrOrderNotEditable,
+		},
+		{
+			name: "Gagal - Order sudah dibayar",
+			orders: []core.Order{
+				{QueueNumber: "POS-001", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid},
+				{QueueNumber: "POS-002", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusPaid, ScheduledFor: &scheduledFor, ReleasedAt: &releasedAt},
+			},
+			expectedError: core.ErrOrderAlreadyPaid,
+		},
+		{
+			// Order tengah tanpa voucher: konflik tetap terdeteksi sebelum item mana pun dipindahkan
+			name: "Gagal - Order memakai voucher berbeda",
+			orders: []core.Order{
+				{QueueNumber: "POS-001", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, VoucherID: &hemat10},
+				{QueueNumber: "POS-002", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid},
+				{QueueNumber: "POS-003", OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, VoucherID: &gratisOngkir},
+			},
+			expectedError: core.ErrVoucherConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			req := order.MergeOrdersRequest{}
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			for i := range tc.orders {
+				o := tc.orders[i]
+				o.ID = uuid.New()
+				req.OrderIDs = append(req.OrderIDs, o.ID)
+				// Urutan lock mengikuti ID, jadi order yang ditolak bisa terkunci lebih dulu
+				mockRepo.EXPECT().LockOrder(gomock.Any(), o.ID).Return(&o, nil).MaxTimes(1)
+				mockRepo.EXPECT().CountUnpaidPaymentsWithTx(gomock.Any(), o.ID).Return(int64(0), nil).MaxTimes(1)
+			}
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			_, err := service.MergeOrders(req, nil)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Equal(t, 0, txdb.Commits())
+			assert.Equal(t, 1, txdb.Rollbacks())
+		})
+	}
+}
+
+func TestUpdateStatus_Gomock(t *testing.T) {
+	scheduledFor := time.Now().Add(3 * time.Hour)
+	releasedAt := time.Now()
+
+	testCases := []struct {
+		name          string
+		order         core.Order
+		req           order.UpdateOrderStatusRequest
+		expectedError error
+	}{
+		{
+			name:  "Sukses - Order lunas diselesaikan",
+			order: core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPaid},
+			req:   order.UpdateOrderStatusRequest{Status: core.OrderStatusCompleted},
+		},
+		{
+			name:  "Sukses - Selesaikan order setelah satu item di-refund",
+			order: core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPartiallyRefunded, TotalFinalAmount: 50000, TotalRefunded: 20000},
+			req:   order.UpdateOrderStatusRequest{Status: core.OrderStatusCompleted},
+		},
+		{
+			name:          "Gagal - Order belum lunas",
+			order:         core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPartiallyPaid},
+			req:           order.UpdateOrderStatusRequest{Status: core.OrderStatusCompleted},
+			expectedError: core.ErrOrderNotPaid,
+		},
+		{
+			name:  "Sukses - Pre-order yang sudah dirilis mulai dimasak",
+			order: core.Order{OrderStatus: core.OrderStatusConfirmed, PaymentStatus: core.PaymentStatusPaid, ScheduledFor: &scheduledFor, ReleasedAt: &releasedAt},
+			req:   order.UpdateOrderStatusRequest{Status: core.OrderStatusPreparing},
+		},
+		{
+			name:          "Gagal - Pre-order belum dirilis ke dapur",
+			order:         core.Order{QueueNumber: "POS-007", OrderStatus: core.OrderStatusConfirmed, PaymentStatus: core.PaymentStatusPaid, ScheduledFor: &scheduledFor},
+			req:           order.UpdateOrderStatusRequest{Status: core.OrderStatusPreparing},
+			expectedError: core.ErrInvalidTransition,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			o := tc.order
+			o.ID = uuid.New()
+
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockOrder(gomock.Any(), o.ID).Return(&o, nil).Times(1)
+			if tc.expectedError == nil {
+				mockRepo.EXPECT().UpdateOrderStatusWithTx(gomock.Any(), o.ID, tc.req.Status).Return(nil).Times(1)
+				mockRepo.EXPECT().CreateStatusHistoryWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, h *core.OrderStatusHistory) error {
+					assert.Equal(t, tc.order.OrderStatus, h.FromStatus)
+					assert.Equal(t, tc.req.Status, h.ToStatus)
+					return nil
+				}).Times(1)
+				mockRepo.EXPECT().FindByID(o.ID).Return(&o, nil).Times(1)
+			}
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			_, err := service.UpdateStatus(o.ID, tc.req, nil)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, txdb.Commits())
+		})
+	}
+}
+
+func TestCancelOrder_Gomock(t *testing.T) {
+	americano := core.Product{ID: uuid.New(), Name: "Americano", Stock: 5}
+	croissant := core.Product{ID: uuid.New(), Name: "Croissant", Stock: 10}
+	juice := core.Product{ID: uuid.New(), Name: "Jus Jeruk", Stock: 8}
+	paket := core.Product{ID: uuid.New(), Name: "Paket Sarapan", Stock: 0}
+	extraShot := core.ModifierOption{ID: uuid.New(), Name: "Extra Shot", TrackStock: true, Stock: 3}
+	lessSugar := core.ModifierOption{ID: uuid.New(), Name: "Less Sugar"}
+
+	items := []core.OrderItem{
+		{ID: uuid.New(), ProductID: americano.ID, Qty: 2, Modifiers: []core.OrderItemModifier{
+			{ModifierOptionID: extraShot.ID, OptionName: extraShot.Name},
+			{ModifierOptionID: lessSugar.ID, OptionName: lessSugar.Name},
+		}},
+		{ID: uuid.New(), ProductID: paket.ID, Qty: 2, Components: []core.OrderItemComponent{
+			{ProductID: croissant.ID, ProductName: croissant.Name, Qty: 1},
+			{ProductID: juice.ID, ProductName: juice.Name, Qty: 2},
+		}},
+	}
+
+	testCases := []struct {
+		name                string
+		order               core.Order
+		expectedStock       map[uuid.UUID]int
+		expectedOptionStock map[uuid.UUID]int
+		expectedError       error
+	}{
+		{
+			// Bundle mengembalikan stok komponennya (qty × isi per bundle), bukan stok bundle itu sendiri
+			name:                "Sukses - Restock produk, komponen bundle, dan opsi modifier",
+			order:               core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid},
+			expectedStock:       map[uuid.UUID]int{americano.ID: 7, croissant.ID: 12, juice.ID: 12},
+			expectedOptionStock: map[uuid.UUID]int{extraShot.ID: 5},
+		},
+		{
+			name:          "Gagal - Order yang sudah dibatalkan tidak di-restock dua kali",
+			order:         core.Order{OrderStatus: core.OrderStatusCancelled, PaymentStatus: core.PaymentStatusUnpaid},
+			expectedError: core.ErrInvalidTransition,
+		},
+		{
+			name:          "Gagal - Order yang sudah dibayar harus lewat refund",
+			order:         core.Order{OrderStatus: core.OrderStatusConfirmed, PaymentStatus: core.PaymentStatusPaid},
+			expectedError: core.ErrOrderAlreadyPaid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			o := tc.order
+			o.ID = uuid.New()
+
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockOrder(gomock.Any(), o.ID).Return(&o, nil).Times(1)
+
+			// Tanpa EXPECT stok pada kasus gagal: restock apa pun akan menggagalkan test
+			stock := make(map[uuid.UUID]int)
+			optionStock := make(map[uuid.UUID]int)
+			if tc.expectedError == nil {
+				catalog := map[uuid.UUID]core.Product{americano.ID: americano, croissant.ID: croissant, juice.ID: juice, paket.ID: paket}
+				options := map[uuid.UUID]core.ModifierOption{extraShot.ID: extraShot, lessSugar.ID: lessSugar}
+
+				mockRepo.EXPECT().FindItemsByOrderIDWithTx(gomock.Any(), o.ID).Return(items, nil).Times(1)
+				mockRepo.EXPECT().LockAndGetProduct(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.Product, error) {
+					p := catalog[id]
+					return &p, nil
+				}).Times(len(catalog))
+				mockRepo.EXPECT().DeductStockWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, p *core.Product) error {
+					stock[p.ID] = p.Stock
+					return nil
+				}).Times(len(tc.expectedStock))
+				mockRepo.EXPECT().LockModifierOption(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.ModifierOption, error) {
+					option := options[id]
+					return &option, nil
+				}).Times(len(options))
+				mockRepo.EXPECT().SaveModifierOptionWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, option *core.ModifierOption) error {
+					optionStock[option.ID] = option.Stock
+					return nil
+				}).Times(len(tc.expectedOptionStock))
+				mockRepo.EXPECT().CancelOrderWithTx(gomock.Any(), o.ID, "Pelanggan batal", gomock.Any()).Return(nil).Times(1)
+				mockRepo.EXPECT().CloseUnpaidPaymentsWithTx(gomock.Any(), o.ID, core.PaymentStatusFailed).Return(nil).Times(1)
+				mockRepo.EXPECT().CreateStatusHistoryWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockRepo.EXPECT().FindByID(o.ID).Return(&o, nil).Times(1)
+			}
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			_, err := service.CancelOrder(o.ID, order.CancelOrderRequest{Reason: "Pelanggan batal"}, nil)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, txdb.Commits())
+			assert.Equal(t, tc.expectedStock, stock)
+			assert.Equal(t, tc.expectedOptionStock, optionStock)
+		})
+	}
+}
+
+// stubCatalog menyiapkan menu untuk Checkout/QuoteCheckout: produk tanpa modifier maupun slot bundle,
+// stok dibaca ulang dari katalog setiap kali dikunci, dan toko tanpa platform fee.
+func stubCatalog(mockRepo *mocks.MockOrderRepository, products ...core.Product) {
+	catalog := make(map[uuid.UUID]core.Product, len(products))
+	for _, p := range products {
+		catalog[p.ID] = p
+	}
+	mockRepo.EXPECT().FindModifierGroups(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().FindBundleSlots(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().LockAndGetProduct(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.Product, error) {
+		p, ok := catalog[id]
+		if !ok {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &p, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().DeductStockWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().GetStoreMarkupFee().Return(0).AnyTimes()
+	mockRepo.EXPECT().GetNextQueueNumber(gomock.Any(), gomock.Any()).Return("CASHIER-001", nil).AnyTimes()
+}
+
+func TestCheckoutIdempotency_Gomock(t *testing.T) {
+	croissant := core.Product{ID: uuid.New(), Name: "Croissant", NormalPrice: 25000, Stock: 10}
+	latte := core.Product{ID: uuid.New(), Name: "Latte", NormalPrice: 30000, Stock: 10}
+	cart := order.CheckoutRequest{
+		OrderSource:    core.OrderSourceCashier,
+		IdempotencyKey: "tablet-1-0001",
+		Items:          []order.CheckoutItemInput{{ProductID: croissant.ID, Qty: 2}, {ProductID: latte.ID, Qty: 1, Notes: "less sugar"}},
+	}
+
+	testCases := []struct {
+		name          string
+		retry         order.CheckoutRequest
+		expectedError error
+	}{
+		{
+			name:  "Sukses - Retry dengan body sama mengembalikan order lama",
+			retry: cart,
+		},
+		{
+			name: "Sukses - Retry dengan urutan item berbeda mengembalikan order lama",
+			retry: order.CheckoutRequest{
+				OrderSource:    core.OrderSourceCashier,
+				IdempotencyKey: "tablet-1-0001",
+				Items:          []order.CheckoutItemInput{{ProductID: latte.ID, Qty: 1, Notes: "less sugar"}, {ProductID: croissant.ID, Qty: 2}},
+			},
+		},
+		{
+			name: "Gagal - Key sama dipakai untuk keranjang berbeda",
+			retry: order.CheckoutRequest{
+				OrderSource:    core.OrderSourceCashier,
+				IdempotencyKey: "tablet-1-0001",
+				Items:          []order.CheckoutItemInput{{ProductID: croissant.ID, Qty: 3}},
+			},
+			expectedError: core.ErrIdempotencyReuse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			var stored *core.Order
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			stubCatalog(mockRepo, croissant, latte)
+			stubPricing(mockRepo)
+			mockRepo.EXPECT().FindByIdempotencyKey(cart.IdempotencyKey).DoAndReturn(func(string) (*core.Order, error) {
+				if stored == nil {
+					return nil, gorm.ErrRecordNotFound
+				}
+				// Replay memuat detail lengkap seperti FindByID
+				replayed := *stored
+				replayed.Payments = []core.Payment{}
+				return &replayed, nil
+			}).Times(2)
+			mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+				created := *o
+				stored = &created
+				return nil
+			}).Times(1)
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			first, err := service.Checkout(cart)
+			assert.NoError(t, err)
+
+			retried, err := service.Checkout(tc.retry)
+
+			// Retry tidak pernah membuka transaksi baru: stok tidak dipotong dua kali
+			assert.Equal(t, 1, txdb.Commits())
+			assert.Equal(t, 0, txdb.Rollbacks())
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, retried)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, first.ID, retried.ID)
+			assert.Equal(t, first.TotalFinalAmount, retried.TotalFinalAmount)
+		})
+	}
+}
+
+func TestCheckoutIdempotency_LostRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	txdb := testutil.NewTxDB(t)
+
+	croissant := core.Product{ID: uuid.New(), Name: "Croissant", NormalPrice: 25000, Stock: 10}
+	req := order.CheckoutRequest{
+		OrderSource:    core.OrderSourceCashier,
+		IdempotencyKey: "tablet-1-0002",
+		Items:          []order.CheckoutItemInput{{ProductID: croissant.ID, Qty: 2}},
+	}
+
+	var winner *core.Order
+	mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+	stubCatalog(mockRepo, croissant)
+	stubPricing(mockRepo)
+	// Pembacaan awal belum menemukan key; setelah insert ditolak, order pemenang sudah ada
+	lookup := mockRepo.EXPECT().FindByIdempotencyKey(req.IdempotencyKey).Return(nil, gorm.ErrRecordNotFound).Times(1)
+	insert := mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+		// Request kembar meng-commit lebih dulu dengan body yang sama
+		won := *o
+		won.ID = uuid.New()
+		winner = &won
+		return errors.New("duplicate key value violates unique constraint \"idx_orders_idempotency_key\"")
+	}).Times(1).After(lookup)
+	mockRepo.EXPECT().FindByIdempotencyKey(req.IdempotencyKey).DoAndReturn(func(string) (*core.Order, error) {
+		return winner, nil
+	}).Times(1).After(insert)
+
+	service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+	result, err := service.Checkout(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, winner.ID, result.ID)
+	// Stok yang sempat dipotong ikut di-rollback bersama insert yang kalah
+	assert.Equal(t, 0, txdb.Commits())
+	assert.Equal(t, 1, txdb.Rollbacks())
+}
+
+func TestQuoteCheckout_MatchesCheckout(t *testing.T) {
+	now := time.Now()
+	window := func(p *core.PromoSchedule) {
+		// Jendela promo yang sedang berlaku saat test berjalan (boleh melewati tengah malam)
+		p.StartTime = now.Add(-time.Hour).Format(core.PromoTimeLayout)
+		p.EndTime = now.Add(time.Hour).Format(core.PromoTimeLayout)
+	}
+
+	drinks, pastry := uuid.New(), uuid.New()
+	large := core.ModifierOption{ID: uuid.New(), Name: "Large", PriceDelta: 5000, IsAvailable: true}
+	extraShot := core.ModifierOption{ID: uuid.New(), Name: "Extra Shot", PriceDelta: 4000, TrackStock: true, Stock: 20, IsAvailable: true}
+	latte := core.Product{ID: uuid.New(), CategoryID: drinks, Name: "Latte", NormalPrice: 30000, IsPromoActive: true, PromoPrice: 27000, Stock: 10, IsAvailable: true}
+	croissant := core.Product{ID: uuid.New(), CategoryID: pastry, Name: "Croissant", NormalPrice: 25000, Stock: 10, IsAvailable: true}
+	latteGroups := []core.ModifierGroup{
+		{ID: uuid.New(), ProductID: latte.ID, Name: "Ukuran", MaxSelect: 1, Options: []core.ModifierOption{large}},
+		{ID: uuid.New(), ProductID: latte.ID, Name: "Extra", Options: []core.ModifierOption{extraShot}},
+	}
+	var happyHour core.PromoSchedule
+	window(&happyHour)
+	pastryPromo := core.Promotion{ID: uuid.New(), Name: "Pastry Sore", Type: core.PromotionPercentOff, IsActive: true, StackWithVoucher: true, CategoryID: &pastry, DiscountPercent: 20}
+	pastryPromo.StartTime, pastryPromo.EndTime = happyHour.StartTime, happyHour.EndTime
+	voucher := core.Voucher{ID: uuid.New(), Code: "HEMAT10", DiscountType: core.DiscountTypePercentage, DiscountValue: 10, ValidUntil: now.Add(24 * time.Hour), IsActive: true}
+	rules := []core.ChargeRule{
+		{ID: uuid.New(), Kind: core.ChargeKindService, Name: "Service", RateBps: 500, AfterDiscount: true, IsActive: true},
+		{ID: uuid.New(), Kind: core.ChargeKindTax, Name: "PB1", RateBps: 1000, AfterDiscount: true, IsActive: true, SortOrder: 1},
+		{ID: uuid.New(), Kind: core.ChargeKindTax, Name: "PPN", RateBps: 1100, IsInclusive: true, IsActive: true, SortOrder: 2},
+	}
+
+	req := order.CheckoutRequest{
+		OrderSource: core.OrderSourceCashier,
+		VoucherCode: voucher.Code,
+		Items: []order.CheckoutItemInput{
+			{ProductID: latte.ID, Qty: 2, ModifierOptionIDs: []uuid.UUID{large.ID, extraShot.ID}},
+			{ProductID: croissant.ID, Qty: 3},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	txdb := testutil.NewTxDB(t)
+
+	catalog := map[uuid.UUID]core.Product{latte.ID: latte, croissant.ID: croissant}
+	fresh := func(id uuid.UUID) *core.Product {
+		p := catalog[id]
+		return &p
+	}
+	mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+	mockRepo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	mockRepo.EXPECT().FindVoucherByCode(voucher.Code).Return(&voucher, nil).Times(2)
+	mockRepo.EXPECT().FindModifierGroups(latte.ID).Return(latteGroups, nil).Times(2)
+	mockRepo.EXPECT().FindModifierGroups(croissant.ID).Return(nil, nil).Times(2)
+	mockRepo.EXPECT().FindBundleSlots(gomock.Any()).Return(nil, nil).Times(4)
+	mockRepo.EXPECT().FindPromoSchedules(gomock.Any()).Return(map[uuid.UUID][]core.PromoSchedule{latte.ID: {happyHour}}, nil).Times(2)
+	mockRepo.EXPECT().ListActivePromotions().Return([]core.Promotion{pastryPromo}, nil).Times(2)
+	mockRepo.EXPECT().FindProductCategories(gomock.Any()).Return(map[uuid.UUID]uuid.UUID{latte.ID: drinks, croissant.ID: pastry}, nil).AnyTimes()
+	mockRepo.EXPECT().ListActiveChargeRules().Return(rules, nil).Times(2)
+	mockRepo.EXPECT().GetStoreMarkupFee().Return(1000).Times(2)
+
+	// QuoteCheckout: baca biasa tanpa lock
+	mockRepo.EXPECT().FindProducts(gomock.Any()).DoAndReturn(func(ids []uuid.UUID) (map[uuid.UUID]*core.Product, error) {
+		products := make(map[uuid.UUID]*core.Product, len(ids))
+		for _, id := range ids {
+			products[id] = fresh(id)
+		}
+		return products, nil
+	}).Times(1)
+	mockRepo.EXPECT().FindModifierOptions(gomock.Any()).Return(map[uuid.UUID]*core.ModifierOption{large.ID: &large, extraShot.ID: &extraShot}, nil).Times(1)
+
+	// Checkout: produk & opsi modifier dikunci lalu stoknya dipotong
+	mockRepo.EXPECT().GetNextQueueNumber(gomock.Any(), core.OrderSourceCashier).Return("CASHIER-001", nil).Times(1)
+	mockRepo.EXPECT().LockAndGetProduct(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.Product, error) {
+		return fresh(id), nil
+	}).Times(2)
+	mockRepo.EXPECT().DeductStockWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	options := map[uuid.UUID]core.ModifierOption{large.ID: large, extraShot.ID: extraShot}
+	mockRepo.EXPECT().LockModifierOption(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.ModifierOption, error) {
+		option := options[id]
+		return &option, nil
+	}).Times(2)
+	mockRepo.EXPECT().SaveModifierOptionWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+	quote, err := service.QuoteCheckout(req)
+	assert.NoError(t, err)
+	created, err := service.Checkout(req)
+	assert.NoError(t, err)
+
+	// Skenario memang memakai semua komponen harga
+	assert.True(t, quote.Items[0].IsPromoPrice || quote.Items[1].IsPromoPrice)
+	assert.Greater(t, quote.TotalPromoDiscount, 0)
+	assert.Greater(t, quote.VoucherDiscount, 0)
+	assert.Greater(t, quote.TotalServiceCharge, 0)
+	assert.Greater(t, quote.TotalTaxIncluded, 0)
+	assert.True(t, quote.CanCheckout)
+	assert.Empty(t, quote.Warnings)
+
+	assert.Equal(t, created.TotalBasePrice, quote.TotalBasePrice)
+	assert.Equal(t, created.TotalPromoDiscount, quote.TotalPromoDiscount)
+	assert.Equal(t, created.TotalDiscount-created.TotalPromoDiscount, quote.VoucherDiscount)
+	assert.Equal(t, created.TotalDiscount, quote.TotalDiscount)
+	assert.Equal(t, created.PlatformFee, quote.PlatformFee)
+	assert.Equal(t, created.TotalServiceCharge, quote.TotalServiceCharge)
+	assert.Equal(t, created.TotalTax, quote.TotalTax)
+	assert.Equal(t, created.TotalTaxIncluded, quote.TotalTaxIncluded)
+	assert.Equal(t, created.TotalFinalAmount, quote.TotalFinalAmount)
+
+	if assert.Len(t, quote.Items, len(created.Items)) {
+		for i, item := range created.Items {
+			assert.Equal(t, item.ProductID, quote.Items[i].ProductID)
+			assert.Equal(t, item.UnitPrice, quote.Items[i].UnitPrice)
+			assert.Equal(t, item.Subtotal, quote.Items[i].Subtotal)
+			assert.Equal(t, item.PromotionName, quote.Items[i].PromotionName)
+			assert.Equal(t, item.PromoDiscount, quote.Items[i].PromoDiscount)
+			assert.Len(t, quote.Items[i].Modifiers, len(item.Modifiers))
+		}
+	}
+	if assert.Len(t, quote.Charges, len(created.Charges)) {
+		for i, charge := range created.Charges {
+			assert.Equal(t, charge.Kind, quote.Charges[i].Kind)
+			assert.Equal(t, charge.Name, quote.Charges[i].Name)
+			assert.Equal(t, charge.IsInclusive, quote.Charges[i].IsInclusive)
+			assert.Equal(t, charge.Amount, quote.Charges[i].Amount)
+		}
+	}
+}
+
+// tabStore menyimpan satu tab dan item-nya di memori agar OpenTab → AddTabRound → CloseTab
+// bisa dijalankan berurutan terhadap mock repository yang sama.
+type tabStore struct {
+	tab     *core.Order
+	items   []core.OrderItem
+	catalog map[uuid.UUID]core.Product
+}
+
+func (s *tabStore) snapshot() *core.Order {
+	o := *s.tab
+	o.Items = append([]core.OrderItem(nil), s.items...)
+	return &o
+}
+
+func newTabStore(mockRepo *mocks.MockOrderRepository, txdb *testutil.TxDB, promotions []core.Promotion, products ...core.Product) *tabStore {
+	store := &tabStore{catalog: make(map[uuid.UUID]core.Product, len(products))}
+	for _, p := range products {
+		store.catalog[p.ID] = p
+	}
+
+	mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+	mockRepo.EXPECT().GetStoreMarkupFee().Return(1000).AnyTimes()
+	mockRepo.EXPECT().GetNextQueueNumber(gomock.Any(), core.OrderSourceCashier).Return("K001", nil).AnyTimes()
+	mockRepo.EXPECT().FindOpenTabByTableWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, table string) (*core.Order, error) {
+		if store.tab == nil || !store.tab.IsOpenTab || *store.tab.TableNumber != table {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return store.snapshot(), nil
+	}).AnyTimes()
+	mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+		tab := *o
+		store.tab = &tab
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().LockOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.Order, error) {
+		return store.snapshot(), nil
+	}).AnyTimes()
+	mockRepo.EXPECT().FindByID(gomock.Any()).DoAndReturn(func(id uuid.UUID) (*core.Order, error) {
+		return store.snapshot(), nil
+	}).AnyTimes()
+	mockRepo.EXPECT().FindItemsByOrderIDWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) ([]core.OrderItem, error) {
+		return append([]core.OrderItem(nil), store.items...), nil
+	}).AnyTimes()
+
+	mockRepo.EXPECT().FindModifierGroups(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().FindBundleSlots(gomock.Any()).Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().LockAndGetProduct(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID) (*core.Product, error) {
+		p := store.catalog[id]
+		return &p, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().DeductStockWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, p *core.Product) error {
+		store.catalog[p.ID] = *p
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	mockRepo.EXPECT().FindPromoSchedules(gomock.Any()).Return(map[uuid.UUID][]core.PromoSchedule{}, nil).AnyTimes()
+	mockRepo.EXPECT().ListActivePromotions().Return(promotions, nil).AnyTimes()
+	mockRepo.EXPECT().FindProductCategories(gomock.Any()).Return(map[uuid.UUID]uuid.UUID{}, nil).AnyTimes()
+	mockRepo.EXPECT().ListActiveChargeRules().Return(nil, nil).AnyTimes()
+	mockRepo.EXPECT().SaveItemWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, item *core.OrderItem) error {
+		for i := range store.items {
+			if store.items[i].ID == item.ID {
+				store.items[i] = *item
+				return nil
+			}
+		}
+		store.items = append(store.items, *item)
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().CreateItemModifiersWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().CreateItemComponentsWithTx(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().UpdateOrderTotalsWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, o *core.Order) error {
+		store.tab.TotalBasePrice = o.TotalBasePrice
+		store.tab.TotalPromoDiscount = o.TotalPromoDiscount
+		store.tab.TotalDiscount = o.TotalDiscount
+		store.tab.TotalFinalAmount = o.TotalFinalAmount
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().CloseTabWithTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, id uuid.UUID, closedAt time.Time) error {
+		store.tab.IsOpenTab = false
+		return nil
+	}).AnyTimes()
+	return store
+}
+
+func TestTabLifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	txdb := testutil.NewTxDB(t)
+
+	kopi := core.Product{ID: uuid.New(), Name: "Kopi Susu", NormalPrice: 20000, Stock: 10, IsAvailable: true}
+	buy2get1 := core.Promotion{ID: uuid.New(), Name: "Beli 2 Gratis 1", Type: core.PromotionBuyXGetY, IsActive: true, ProductID: &kopi.ID, BuyQty: 2, GetQty: 1}
+	store := newTabStore(mockRepo, txdb, []core.Promotion{buy2get1}, kopi)
+
+	service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+	tab, err := service.OpenTab(order.OpenTabRequest{TableNumber: "A1"})
+	assert.NoError(t, err)
+	assert.True(t, tab.IsOpenTab)
+	assert.Equal(t, 1000, tab.TotalFinalAmount)
+
+	_, err = service.OpenTab(order.OpenTabRequest{TableNumber: "A1"})
+	assert.ErrorIs(t, err, core.ErrTabAlreadyOpen)
+
+	// Ronde 1: dua kopi, promo belum terpenuhi
+	tab, err = service.AddTabRound(tab.ID, order.AddTabRoundRequest{Items: []order.CheckoutItemInput{{ProductID: kopi.ID, Qty: 2}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 40000, tab.TotalBasePrice)
+	assert.Equal(t, 0, tab.TotalPromoDiscount)
+	assert.Equal(t, 8, store.catalog[kopi.ID].Stock)
+
+	// Harga naik di antara ronde: item ronde 1 tetap memakai harga saat dipesan
+	kopiNaik := store.catalog[kopi.ID]
+	kopiNaik.NormalPrice = 22000
+	store.catalog[kopi.ID] = kopiNaik
+
+	// Ronde 2: satu kopi lagi. Promo SENGAJA dievaluasi ulang atas semua ronde, sehingga
+	// kopi ketiga melengkapi "Beli 2 Gratis 1" dan unit termurah (ronde 1) yang gratis.
+	tab, err = service.AddTabRound(tab.ID, order.AddTabRoundRequest{Items: []order.CheckoutItemInput{{ProductID: kopi.ID, Qty: 1}}})
+	assert.NoError(t, err)
+	if assert.Len(t, tab.Items, 2) {
+		assert.Equal(t, 1, tab.Items[0].Round)
+		assert.Equal(t, 20000, tab.Items[0].UnitPrice)
+		assert.Equal(t, 2, tab.Items[1].Round)
+		assert.Equal(t, 22000, tab.Items[1].UnitPrice)
+	}
+	assert.Equal(t, 62000, tab.TotalBasePrice)
+	assert.Equal(t, 20000, tab.TotalPromoDiscount)
+	assert.Equal(t, 62000-20000+1000, tab.TotalFinalAmount)
+	assert.Equal(t, 7, store.catalog[kopi.ID].Stock)
+
+	tab, err = service.CloseTab(tab.ID)
+	assert.NoError(t, err)
+	assert.False(t, tab.IsOpenTab)
+
+	_, err = service.AddTabRound(tab.ID, order.AddTabRoundRequest{Items: []order.CheckoutItemInput{{ProductID: kopi.ID, Qty: 1}}})
+	assert.ErrorIs(t, err, core.ErrTabNotOpen)
+	_, err = service.CloseTab(tab.ID)
+	assert.ErrorIs(t, err, core.ErrTabNotOpen)
+
+	// Setelah ditutup, meja boleh membuka tab baru
+	_, err = service.OpenTab(order.OpenTabRequest{TableNumber: "A1"})
+	assert.NoError(t, err)
+}
+
+func TestOpenTab_LostRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	txdb := testutil.NewTxDB(t)
+	winner := &core.Order{ID: uuid.New(), IsOpenTab: true}
+
+	mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+	gomock.InOrder(
+		mockRepo.EXPECT().FindOpenTabByTableWithTx(gomock.Any(), "A1").Return(nil, gorm.ErrRecordNotFound),
+		mockRepo.EXPECT().FindOpenTabByTableWithTx(gomock.Any(), "A1").Return(winner, nil),
+	)
+	mockRepo.EXPECT().GetNextQueueNumber(gomock.Any(), core.OrderSourceCashier).Return("K002", nil).Times(1)
+	mockRepo.EXPECT().GetStoreMarkupFee().Return(0).Times(1)
+	// Tab lain untuk meja yang sama ter-commit lebih dulu: partial unique index menolak insert ini
+	mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).Return(errors.New("duplicate key value violates unique constraint \"idx_orders_open_tab\"")).Times(1)
+
+	service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+	_, err := service.OpenTab(order.OpenTabRequest{TableNumber: "A1"})
+
+	assert.ErrorIs(t, err, core.ErrTabAlreadyOpen)
+	assert.Equal(t, 0, txdb.Commits())
+	assert.Equal(t, 1, txdb.Rollbacks())
+}
+
+func TestListOrders_CursorAcrossEqualCreatedAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+
+	// Tiga order dibuat pada detik yang sama (mis. checkout massal): cursor harus tetap membedakan lewat ID
+	busy := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	rows := []order.OrderSummary{
+		{ID: uuid.New(), PaymentStatus: core.PaymentStatusExpired, CreatedAt: busy.Add(time.Minute)},
+		{ID: uuid.New(), PaymentStatus: core.PaymentStatusExpired, CreatedAt: busy},
+		{ID: uuid.New(), PaymentStatus: core.PaymentStatusExpired, CreatedAt: busy},
+		{ID: uuid.New(), PaymentStatus: core.PaymentStatusExpired, CreatedAt: busy},
+		{ID: uuid.New(), PaymentStatus: core.PaymentStatusExpired, CreatedAt: busy.Add(-time.Minute)},
+	}
+	// Urutan repository: created_at DESC, id DESC (uuid dibandingkan per byte seperti PostgreSQL)
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].CreatedAt.Equal(rows[j].CreatedAt) {
+			return rows[i].CreatedAt.After(rows[j].CreatedAt)
+		}
+		return bytes.Compare(rows[i].ID[:], rows[j].ID[:]) > 0
+	})
+
+	mockRepo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	mockRepo.EXPECT().List(gomock.Any()).DoAndReturn(func(filter order.OrderListFilter) ([]order.OrderSummary, error) {
+		assert.Equal(t, core.PaymentStatusExpired, filter.PaymentStatus)
+		var page []order.OrderSummary
+		for _, row := range rows {
+			if filter.CursorCreatedAt != nil {
+				// (created_at, id) < (cursor_created_at, cursor_id)
+				older := row.CreatedAt.Before(*filter.CursorCreatedAt) ||
+					(row.CreatedAt.Equal(*filter.CursorCreatedAt) && bytes.Compare(row.ID[:], filter.CursorID[:]) < 0)
+				if !older {
+					continue
+				}
+			}
+			if len(page) == filter.Limit {
+				break
+			}
+			page = append(page, row)
+		}
+		return page, nil
+	}).AnyTimes()
+
+	service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+	var seen []uuid.UUID
+	query := order.OrderListQuery{PaymentStatus: core.PaymentStatusExpired, Limit: 2}
+	for pages := 0; pages < len(rows); pages++ {
+		resp, err := service.ListOrders(query)
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, summary := range resp.Data {
+			seen = append(seen, summary.ID)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		query.Cursor = resp.NextCursor
+	}
+
+	expected := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		expected[i] = row.ID
+	}
+	// Setiap order muncul tepat sekali dengan urutan yang sama seperti tanpa pagination
+	assert.Equal(t, expected, seen)
+}
+
+func TestTrackOrder_Gomock(t *testing.T) {
+	tableNumber := "A-12"
+	tracked := &core.Order{
+		ID:               uuid.New(),
+		TrackingToken:    "tok_3f9a",
+		QueueNumber:      "E-014",
+		TableNumber:      &tableNumber,
+		OrderStatus:      core.OrderStatusPreparing,
+		PaymentStatus:    core.PaymentStatusPartiallyPaid,
+		TotalFinalAmount: 55000,
+		TotalPaid:        30000,
+		// Biasanya diisi hook AfterFind saat order dibaca dari database
+		OutstandingAmount: 25000,
+		Items:             []core.OrderItem{{Product: core.Product{Name: "Kopi Susu"}, Qty: 2, UnitPrice: 27500, Subtotal: 55000}},
+	}
+
+	testCases := []struct {
+		name          string
+		token         string
+		buildStubs    func(mockRepo *mocks.MockOrderRepository)
+		expectedError error
+	}{
+		{
+			name:  "Sukses - Order ditemukan lewat tracking token",
+			token: tracked.TrackingToken,
+			buildStubs: func(mockRepo *mocks.MockOrderRepository) {
+				mockRepo.EXPECT().FindByTrackingToken(tracked.TrackingToken).Return(tracked, nil).Times(1)
+			},
+		},
+		{
+			name:  "Gagal - Token tidak dikenal",
+			token: "tok_tidak_ada",
+			buildStubs: func(mockRepo *mocks.MockOrderRepository) {
+				mockRepo.EXPECT().FindByTrackingToken("tok_tidak_ada").Return(nil, gorm.ErrRecordNotFound).Times(1)
+			},
+			expectedError: core.ErrNotFound,
+		},
+		{
+			// Token kosong tidak boleh sampai ke query (WHERE tracking_token = '' bisa cocok dengan order lama)
+			name:          "Gagal - Token kosong",
+			buildStubs:    func(mockRepo *mocks.MockOrderRepository) {},
+			expectedError: core.ErrNotFound,
+		},
+		{
+			name:  "Gagal - Database error",
+			token: tracked.TrackingToken,
+			buildStubs: func(mockRepo *mocks.MockOrderRepository) {
+				mockRepo.EXPECT().FindByTrackingToken(tracked.TrackingToken).Return(nil, errors.New("connection refused")).Times(1)
+			},
+			expectedError: core.ErrInternalServer,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			tc.buildStubs(mockRepo)
+
+			service := order.NewOrderService(mockRepo, validator.New(), nil)
+
+			resp, err := service.TrackOrder(tc.token)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, resp)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tracked.TrackingToken, resp.TrackingToken)
+			assert.Equal(t, "E-014", resp.QueueNumber)
+			assert.Equal(t, &tableNumber, resp.TableNumber)
+			assert.Equal(t, core.OrderStatusPreparing, resp.OrderStatus)
+			assert.Equal(t, 25000, resp.OutstandingAmount)
+			if assert.Len(t, resp.Items, 1) {
+				assert.Equal(t, "Kopi Susu", resp.Items[0].ProductName)
+			}
+		})
+	}
+}
@@ -6,29 +6,74 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // PaymentGateway adalah PORT — abstraksi untuk semua payment gateway.
 // Didefinisikan di sini (bukan di infrastructure) agar service layer
 // hanya bergantung pada interface, bukan implementasi konkret.
 type PaymentGateway interface {
-	CreatePaymentLink(order *core.Order) (paymentURL string, transactionID string, err error)
+	// CreatePaymentLink membuat link pembayaran untuk SATU payment (split payment = banyak link per order).
+	CreatePaymentLink(order *core.Order, payment *core.Payment) (paymentURL string, transactionID string, err error)
 	VerifySignature(payload WebhookPayload) bool
+	// Refund mengembalikan sebagian/seluruh dana dari transaksi gateway yang sudah settle.
+	Refund(payment *core.Payment, amount int, reason string) (refundID string, err error)
+	// CancelPaymentLink mematikan link pembayaran yang belum dibayar agar tidak bisa settle lagi.
+	CancelPaymentLink(payment *core.Payment) error
 }
 
 // PaymentRepository mendefinisikan kontrak akses data untuk Payment.
 type PaymentRepository interface {
-	Create(payment *core.Payment) error
+	FindByID(id uuid.UUID) (*core.Payment, error)
 	FindByIdempotencyKey(key string) (*core.Payment, error)
-	FindByOrderID(orderID uuid.UUID) (*core.Payment, error)
+	FindByOrderID(orderID uuid.UUID) ([]core.Payment, error)
 	FindOrderByID(orderID uuid.UUID) (*core.Order, error)
 	UpdateStatus(paymentID uuid.UUID, status string, paidAt *time.Time) error
-	UpdateWebhookTimestamp(paymentID uuid.UUID, receivedAt time.Time) error
-	UpdateOrderPaymentStatus(orderID uuid.UUID, status string) error
+
+	// --- Split payment (semua dijalankan di dalam satu transaksi) ---
+	CreateWithTx(tx *gorm.DB, payment *core.Payment) error
+	UpdateStatusWithTx(tx *gorm.DB, paymentID uuid.UUID, status string) error
+	// SetGatewayTransactionWithTx menyimpan transaction ID gateway sebagai MidtransTransactionID dan IdempotencyKey.
+	SetGatewayTransactionWithTx(tx *gorm.DB, paymentID uuid.UUID, transactionID string) error
+	// LockPayment mengambil payment dengan FOR UPDATE. Selalu kunci ORDER terlebih dahulu, baru payment.
+	LockPayment(tx *gorm.DB, paymentID uuid.UUID) (*core.Payment, error)
+	// SumPaymentsWithTx menjumlahkan AmountPaid payment milik order dengan status tertentu.
+	SumPaymentsWithTx(tx *gorm.DB, orderID uuid.UUID, statuses ...string) (int, error)
+	// MarkPaidWithTx menandai payment PAID; webhookReceivedAt nil untuk pelunasan lokal (tanpa gateway).
+	MarkPaidWithTx(tx *gorm.DB, paymentID uuid.UUID, paidAt time.Time, webhookReceivedAt *time.Time) error
+	UpdateOrderPaidWithTx(tx *gorm.DB, orderID uuid.UUID, totalPaid int, paymentStatus string) error
+
+	// --- Refund (semua dijalankan di dalam satu transaksi) ---
+	LockOrder(tx *gorm.DB, orderID uuid.UUID) (*core.Order, error)
+	FindOrderItemsWithTx(tx *gorm.DB, orderID uuid.UUID) ([]core.OrderItem, error)
+	// FindPaidPaymentWithTx mengambil payment PAID tertentu, atau yang terakhir dibayar jika paymentID nil.
+	FindPaidPaymentWithTx(tx *gorm.DB, orderID uuid.UUID, paymentID *uuid.UUID) (*core.Payment, error)
+	// SumRefundedByPaymentWithTx menjumlahkan refund PENDING & COMPLETED milik payment (FAILED tidak dihitung).
+	SumRefundedByPaymentWithTx(tx *gorm.DB, paymentID uuid.UUID) (int, error)
+	// LockAndGetProduct mengambil product dengan FOR UPDATE untuk restock.
+	LockAndGetProduct(tx *gorm.DB, productID uuid.UUID) (*core.Product, error)
+	SaveProductWithTx(tx *gorm.DB, product *core.Product) error
+	// LockModifierOption mengambil opsi modifier dengan FOR UPDATE untuk restock (setelah semua produk).
+	LockModifierOption(tx *gorm.DB, optionID uuid.UUID) (*core.ModifierOption, error)
+	SaveModifierOptionWithTx(tx *gorm.DB, option *core.ModifierOption) error
+	UpdateRefundedQtyWithTx(tx *gorm.DB, orderItemID uuid.UUID, refundedQty int) error
+	UpdateOrderRefundWithTx(tx *gorm.DB, orderID uuid.UUID, totalRefunded int, paymentStatus string) error
+	CreateRefundWithTx(tx *gorm.DB, refund *core.Refund) error
+	// UpdateRefundWithTx menyelesaikan refund PENDING; gatewayRefundID nil = tidak diubah.
+	UpdateRefundWithTx(tx *gorm.DB, refundID uuid.UUID, status string, gatewayRefundID *string) error
+	// GetStoreProfile mengambil profil toko untuk aturan pembulatan pembayaran.
+	GetStoreProfile() (*core.StoreProfile, error)
+	DB() *gorm.DB
 }
 
 // PaymentService mendefinisikan kontrak business logic untuk Payment.
 type PaymentService interface {
 	InitiatePayment(req InitiatePaymentRequest) (*InitiatePaymentResponse, error)
 	HandleWebhook(payload WebhookPayload) error
+	// GetOrderPayments mengembalikan ringkasan split payment: total, terbayar, pending, dan sisa tagihan.
+	GetOrderPayments(orderID uuid.UUID) (*PaymentSummaryResponse, error)
+	// VoidPayment membatalkan payment UNPAID agar nominalnya bisa dialokasikan ke metode lain.
+	VoidPayment(paymentID uuid.UUID) error
+	// RefundOrder mengembalikan dana per item untuk order yang sudah lunas.
+	RefundOrder(req RefundRequest, createdBy *uuid.UUID) (*core.Refund, error)
 }
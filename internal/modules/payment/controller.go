@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type PaymentController struct {
@@ -17,7 +18,7 @@ func NewPaymentController(service PaymentService) *PaymentController {
 	return &PaymentController{service: service}
 }
 
-// InitiatePayment membuat link pembayaran untuk sebuah order.
+// InitiatePayment membuat link pembayaran untuk sebuah order (CASH langsung dilunasi di kasir).
 // Endpoint: POST /admin/payments/initiate
 func (ctrl *PaymentController) InitiatePayment(c *fiber.Ctx) error {
 	var req InitiatePaymentRequest
@@ -34,12 +35,25 @@ func (ctrl *PaymentController) InitiatePayment(c *fiber.Ctx) error {
 		if errors.Is(err, core.ErrNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Order tidak ditemukan"})
 		}
-		if errors.Is(err, core.ErrOrderAlreadyPaid) {
+		if errors.Is(err, core.ErrOrderAlreadyPaid) || errors.Is(err, core.ErrOrderCancelled) || errors.Is(err, core.ErrOverpayment) || errors.Is(err, core.ErrTabOpen) || errors.Is(err, core.ErrPaymentNotPending) {
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
 		}
+		if errors.Is(err, core.ErrInsufficientCash) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrPaymentGateway) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	if resp.PaymentMethod == core.PaymentMethodCash {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"message": "Pembayaran tunai berhasil",
+			"data":    resp,
+		})
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "Link pembayaran berhasil dibuat",
 		"data":    resp,
@@ -69,3 +83,81 @@ func (ctrl *PaymentController) HandleWebhook(c *fiber.Ctx) error {
 	// Selalu return 200 untuk notifikasi yang valid (termasuk yang sudah diproses/idempotent)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "OK"})
 }
+
+// GetOrderPayments menampilkan semua payment (split tender) milik order beserta sisa tagihannya.
+// Endpoint: GET /admin/payments/orders/:order_id
+func (ctrl *PaymentController) GetOrderPayments(c *fiber.Ctx) error {
+	orderID, err := uuid.Parse(c.Params("order_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID order tidak valid"})
+	}
+
+	summary, err := ctrl.service.GetOrderPayments(orderID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Order tidak ditemukan"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": summary})
+}
+
+// VoidPayment membatalkan payment yang masih UNPAID.
+// Endpoint: POST /admin/payments/:id/void
+func (ctrl *PaymentController) VoidPayment(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID payment tidak valid"})
+	}
+
+	if err := ctrl.service.VoidPayment(id); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrPaymentNotPending) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrPaymentGateway) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Payment berhasil dibatalkan"})
+}
+
+// Refund mengembalikan dana per item untuk order yang sudah lunas.
+// Endpoint: POST /admin/payments/refunds
+func (ctrl *PaymentController) Refund(c *fiber.Ctx) error {
+	var req RefundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	var createdBy *uuid.UUID
+	if userID, ok := c.Locals("user_id").(uuid.UUID); ok {
+		createdBy = &userID
+	}
+
+	refund, err := ctrl.service.RefundOrder(req, createdBy)
+	if err != nil {
+		var valErr validator.ValidationErrors
+		if errors.As(err, &valErr) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+		}
+		if errors.Is(err, core.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrOrderNotPaid) || errors.Is(err, core.ErrRefundExceedsQty) || errors.Is(err, core.ErrRefundExceedsPaid) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, core.ErrPaymentGateway) {
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Refund berhasil diproses",
+		"data":    refund,
+	})
+}
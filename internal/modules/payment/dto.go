@@ -1,11 +1,19 @@
 package payment
 
-import "github.com/google/uuid"
+import (
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+)
 
 // InitiatePaymentRequest adalah DTO untuk request membuat link pembayaran.
 type InitiatePaymentRequest struct {
 	OrderID       uuid.UUID `json:"order_id" validate:"required"`
 	PaymentMethod string    `json:"payment_method" validate:"required,oneof=CASH QRIS TRANSFER"`
+	Amount        int       `json:"amount" validate:"min=0"` // 0 = bayar seluruh sisa tagihan; > 0 = split payment
+
+	// AmountTendered wajib untuk CASH: uang yang diserahkan pelanggan ke kasir
+	AmountTendered int `json:"amount_tendered" validate:"required_if=PaymentMethod CASH,min=0"`
 }
 
 // InitiatePaymentResponse berisi data payment yang berhasil dibuat.
@@ -14,8 +22,28 @@ type InitiatePaymentResponse struct {
 	PaymentURL     string `json:"payment_url"`     // URL redirect untuk QRIS/Transfer
 	TransactionID  string `json:"transaction_id"`  // Midtrans transaction ID
 	IdempotencyKey string `json:"idempotency_key"` // Untuk tracking webhook
-	AmountDue      int    `json:"amount_due"`
+	AmountDue      int    `json:"amount_due"` // Nominal yang ditagih ke pelanggan, sudah termasuk pembulatan
 	PaymentMethod  string `json:"payment_method"`
+
+	RoundingAdjustment int `json:"rounding_adjustment"` // Selisih pembulatan; tidak mengurangi/menambah sisa tagihan order
+	OutstandingBalance int `json:"outstanding_balance"` // Sisa tagihan yang belum dialokasikan ke payment mana pun
+
+	// Khusus CASH — dilunasi langsung tanpa gateway
+	PaymentStatus  string `json:"payment_status"`
+	AmountTendered int    `json:"amount_tendered,omitempty"`
+	ChangeDue      int    `json:"change_due"`
+}
+
+// PaymentSummaryResponse merangkum semua payment (split tender) milik satu order.
+type PaymentSummaryResponse struct {
+	OrderID            uuid.UUID      `json:"order_id"`
+	PaymentStatus      string         `json:"payment_status"`
+	TotalFinalAmount   int            `json:"total_final_amount"`
+	TotalPaid          int            `json:"total_paid"`
+	TotalPending       int            `json:"total_pending"`       // Payment UNPAID yang masih menunggu pelunasan
+	OutstandingBalance int            `json:"outstanding_balance"` // TotalFinalAmount - TotalPaid - TotalPending
+	TotalRounding      int            `json:"total_rounding"`      // Selisih pembulatan payment PAID (uang diterima = TotalPaid + TotalRounding)
+	Payments           []core.Payment `json:"payments"`
 }
 
 // WebhookPayload adalah DTO untuk menerima notifikasi dari Midtrans.
@@ -28,3 +56,18 @@ type WebhookPayload struct {
 	SignatureKey      string `json:"signature_key"`
 	FraudStatus       string `json:"fraud_status"`
 }
+
+// RefundItemInput adalah satu baris OrderItem yang dikembalikan.
+type RefundItemInput struct {
+	OrderItemID uuid.UUID `json:"order_item_id" validate:"required"`
+	Qty         int       `json:"qty" validate:"required,min=1"`
+}
+
+// RefundRequest adalah DTO untuk request refund per item pada order yang sudah lunas.
+type RefundRequest struct {
+	OrderID   uuid.UUID         `json:"order_id" validate:"required"`
+	PaymentID *uuid.UUID        `json:"payment_id"` // Opsional untuk split payment; default payment PAID terakhir
+	Reason    string            `json:"reason" validate:"required,min=3,max=255"`
+	Restock   bool              `json:"restock"` // true jika barang masih layak jual (misal: salah pesan, belum dibuat)
+	Items     []RefundItemInput `json:"items" validate:"required,min=1,dive"`
+}
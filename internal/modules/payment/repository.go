@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type paymentRepository struct {
@@ -17,30 +18,38 @@ func NewPaymentRepository(db *gorm.DB) PaymentRepository {
 	return &paymentRepository{db: db}
 }
 
-func (r *paymentRepository) Create(payment *core.Payment) error {
-	return r.db.Create(payment).Error
+// DB mengekspos koneksi database untuk pembuatan transaksi di service layer.
+func (r *paymentRepository) DB() *gorm.DB {
+	return r.db
 }
 
-// FindByIdempotencyKey adalah kunci dari webhook idempotency.
-// Mencari payment berdasarkan IdempotencyKey (= Midtrans order_id).
-func (r *paymentRepository) FindByIdempotencyKey(key string) (*core.Payment, error) {
+func (r *paymentRepository) FindByID(id uuid.UUID) (*core.Payment, error) {
 	var p core.Payment
-	err := r.db.Where("idempotency_key = ?", key).First(&p).Error
+	err := r.db.First(&p, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
-func (r *paymentRepository) FindByOrderID(orderID uuid.UUID) (*core.Payment, error) {
+// FindByIdempotencyKey adalah kunci dari webhook idempotency.
+// Mencari payment berdasarkan IdempotencyKey (= Midtrans order_id).
+func (r *paymentRepository) FindByIdempotencyKey(key string) (*core.Payment, error) {
 	var p core.Payment
-	err := r.db.Where("order_id = ?", orderID).First(&p).Error
+	err := r.db.Where("idempotency_key = ?", key).First(&p).Error
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
 
+// FindByOrderID mengambil semua payment (split tender) milik order, urut dari yang pertama dibuat.
+func (r *paymentRepository) FindByOrderID(orderID uuid.UUID) ([]core.Payment, error) {
+	var payments []core.Payment
+	err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&payments).Error
+	return payments, err
+}
+
 func (r *paymentRepository) FindOrderByID(orderID uuid.UUID) (*core.Order, error) {
 	var order core.Order
 	err := r.db.First(&order, "id = ?", orderID).Error
@@ -58,14 +67,162 @@ func (r *paymentRepository) UpdateStatus(paymentID uuid.UUID, status string, pai
 	return r.db.Model(&core.Payment{}).Where("id = ?", paymentID).Updates(updates).Error
 }
 
-func (r *paymentRepository) UpdateWebhookTimestamp(paymentID uuid.UUID, receivedAt time.Time) error {
-	return r.db.Model(&core.Payment{}).
+func (r *paymentRepository) CreateWithTx(tx *gorm.DB, payment *core.Payment) error {
+	return tx.Create(payment).Error
+}
+
+func (r *paymentRepository) UpdateStatusWithTx(tx *gorm.DB, paymentID uuid.UUID, status string) error {
+	return tx.Model(&core.Payment{}).Where("id = ?", paymentID).Update("payment_status", status).Error
+}
+
+func (r *paymentRepository) SetGatewayTransactionWithTx(tx *gorm.DB, paymentID uuid.UUID, transactionID string) error {
+	return tx.Model(&core.Payment{}).
 		Where("id = ?", paymentID).
-		Update("webhook_received_at", receivedAt).Error
+		Updates(map[string]interface{}{
+			"midtrans_transaction_id": transactionID,
+			"idempotency_key":         transactionID,
+		}).Error
+}
+
+func (r *paymentRepository) LockPayment(tx *gorm.DB, paymentID uuid.UUID) (*core.Payment, error) {
+	var p core.Payment
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&p, "id = ?", paymentID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *paymentRepository) SumPaymentsWithTx(tx *gorm.DB, orderID uuid.UUID, statuses ...string) (int, error) {
+	var total int
+	err := tx.Model(&core.Payment{}).
+		Where("order_id = ? AND payment_status IN ?", orderID, statuses).
+		Select("COALESCE(SUM(amount_paid), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *paymentRepository) MarkPaidWithTx(tx *gorm.DB, paymentID uuid.UUID, paidAt time.Time, webhookReceivedAt *time.Time) error {
+	updates := map[string]interface{}{
+		"payment_status": core.PaymentStatusPaid,
+		"paid_at":        paidAt,
+	}
+	if webhookReceivedAt != nil {
+		updates["webhook_received_at"] = webhookReceivedAt
+	}
+	return tx.Model(&core.Payment{}).Where("id = ?", paymentID).Updates(updates).Error
+}
+
+func (r *paymentRepository) UpdateOrderPaidWithTx(tx *gorm.DB, orderID uuid.UUID, totalPaid int, paymentStatus string) error {
+	return tx.Model(&core.Order{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"total_paid":     totalPaid,
+			"payment_status": paymentStatus,
+		}).Error
+}
+
+// LockOrder mengambil order dengan FOR UPDATE. Dipakai sebagai "mutex" per order untuk
+// alokasi split payment, settlement webhook, dan refund agar tidak saling menimpa.
+func (r *paymentRepository) LockOrder(tx *gorm.DB, orderID uuid.UUID) (*core.Order, error) {
+	var order core.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, "id = ?", orderID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
 }
 
-func (r *paymentRepository) UpdateOrderPaymentStatus(orderID uuid.UUID, status string) error {
-	return r.db.Model(&core.Order{}).
+func (r *paymentRepository) FindOrderItemsWithTx(tx *gorm.DB, orderID uuid.UUID) ([]core.OrderItem, error) {
+	var items []core.OrderItem
+	err := tx.Preload("Modifiers").Preload("Components").Where("order_id = ?", orderID).Find(&items).Error
+	return items, err
+}
+
+func (r *paymentRepository) FindPaidPaymentWithTx(tx *gorm.DB, orderID uuid.UUID, paymentID *uuid.UUID) (*core.Payment, error) {
+	var p core.Payment
+	query := tx.Where("order_id = ? AND payment_status = ?", orderID, core.PaymentStatusPaid)
+	if paymentID != nil {
+		query = query.Where("id = ?", *paymentID)
+	}
+	err := query.Order("paid_at DESC").First(&p).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *paymentRepository) SumRefundedByPaymentWithTx(tx *gorm.DB, paymentID uuid.UUID) (int, error) {
+	var total int
+	err := tx.Model(&core.Refund{}).
+		Where("payment_id = ? AND status <> ?", paymentID, core.RefundStatusFailed).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *paymentRepository) LockAndGetProduct(tx *gorm.DB, productID uuid.UUID) (*core.Product, error) {
+	var product core.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, "id = ?", productID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *paymentRepository) SaveProductWithTx(tx *gorm.DB, product *core.Product) error {
+	return tx.Save(product).Error
+}
+
+func (r *paymentRepository) LockModifierOption(tx *gorm.DB, optionID uuid.UUID) (*core.ModifierOption, error) {
+	var option core.ModifierOption
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&option, "id = ?", optionID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+func (r *paymentRepository) SaveModifierOptionWithTx(tx *gorm.DB, option *core.ModifierOption) error {
+	return tx.Save(option).Error
+}
+
+func (r *paymentRepository) UpdateRefundedQtyWithTx(tx *gorm.DB, orderItemID uuid.UUID, refundedQty int) error {
+	return tx.Model(&core.OrderItem{}).
+		Where("id = ?", orderItemID).
+		Update("refunded_qty", refundedQty).Error
+}
+
+func (r *paymentRepository) UpdateOrderRefundWithTx(tx *gorm.DB, orderID uuid.UUID, totalRefunded int, paymentStatus string) error {
+	return tx.Model(&core.Order{}).
 		Where("id = ?", orderID).
-		Update("payment_status", status).Error
+		Updates(map[string]interface{}{
+			"total_refunded": totalRefunded,
+			"payment_status": paymentStatus,
+		}).Error
+}
+
+// CreateRefundWithTx menyimpan Refund beserta semua RefundItem-nya.
+func (r *paymentRepository) CreateRefundWithTx(tx *gorm.DB, refund *core.Refund) error {
+	return tx.Create(refund).Error
+}
+
+func (r *paymentRepository) UpdateRefundWithTx(tx *gorm.DB, refundID uuid.UUID, status string, gatewayRefundID *string) error {
+	updates := map[string]interface{}{"status": status}
+	if gatewayRefundID != nil {
+		updates["gateway_refund_id"] = *gatewayRefundID
+	}
+	return tx.Model(&core.Refund{}).Where("id = ?", refundID).Updates(updates).Error
+}
+
+func (r *paymentRepository) GetStoreProfile() (*core.StoreProfile, error) {
+	var profile core.StoreProfile
+	if err := r.db.First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
 }
@@ -1,18 +1,23 @@
 package payment
 
 import (
+	"go-fiber-pos/internal/core"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(adminGroup fiber.Router, webhookGroup fiber.Router, db *gorm.DB, v *validator.Validate, gateway PaymentGateway) {
+func SetupRoutes(adminGroup fiber.Router, webhookGroup fiber.Router, db *gorm.DB, v *validator.Validate, gateway PaymentGateway, events core.OrderEventPublisher) {
 	repo := NewPaymentRepository(db)
-	service := NewPaymentService(repo, gateway, v)
+	service := NewPaymentService(repo, gateway, v, events)
 	ctrl := NewPaymentController(service)
 
 	// Admin: membuat link pembayaran
 	adminGroup.Post("/payments/initiate", ctrl.InitiatePayment)
+	adminGroup.Get("/payments/orders/:order_id", ctrl.GetOrderPayments)
+	adminGroup.Post("/payments/:id/void", ctrl.VoidPayment)
+	adminGroup.Post("/payments/refunds", ctrl.Refund)
 
 	// Webhook: public endpoint (tanpa JWT) — Midtrans mengirim notifikasi ke sini
 	webhookGroup.Post("/payment", ctrl.HandleWebhook)
@@ -2,9 +2,12 @@ package payment
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"go-fiber-pos/internal/core"
+	"go-fiber-pos/pkg/logger"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -15,60 +18,218 @@ type paymentService struct {
 	repo    PaymentRepository
 	gateway PaymentGateway
 	v       *validator.Validate
+	events  core.OrderEventPublisher
 }
 
-func NewPaymentService(repo PaymentRepository, gateway PaymentGateway, v *validator.Validate) PaymentService {
-	return &paymentService{repo: repo, gateway: gateway, v: v}
+func NewPaymentService(repo PaymentRepository, gateway PaymentGateway, v *validator.Validate, events core.OrderEventPublisher) PaymentService {
+	return &paymentService{repo: repo, gateway: gateway, v: v, events: events}
 }
 
 // InitiatePayment membuat payment record baru dan link pembayaran via gateway.
+// Mendukung split payment: satu order boleh dibayar dengan beberapa payment (misal CASH + QRIS)
+// selama total yang dialokasikan (PAID + UNPAID) tidak melebihi TotalFinalAmount.
 func (s *paymentService) InitiatePayment(req InitiatePaymentRequest) (*InitiatePaymentResponse, error) {
 	if err := s.v.Struct(req); err != nil {
 		return nil, err
 	}
 
-	// Ambil order
-	order, err := s.repo.FindOrderByID(req.OrderID)
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Kunci order agar dua kasir tidak mengalokasikan sisa tagihan yang sama secara bersamaan
+	order, err := s.repo.LockOrder(tx, req.OrderID)
 	if err != nil {
+		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, core.ErrNotFound
 		}
 		return nil, core.ErrInternalServer
 	}
 
-	// Cek apakah order sudah lunas
-	if order.PaymentStatus == core.PaymentStatusPaid {
+	if order.OrderStatus == core.OrderStatusCancelled {
+		tx.Rollback()
+		return nil, core.ErrOrderCancelled
+	}
+
+	// Tab dine-in dibayar sekali setelah ditutup, bukan per ronde
+	if order.IsOpenTab {
+		tx.Rollback()
+		return nil, core.ErrTabOpen
+	}
+
+	// Cek apakah order sudah lunas (termasuk yang sudah pernah di-refund)
+	if order.PaymentStatus != core.PaymentStatusUnpaid && order.PaymentStatus != core.PaymentStatusPartiallyPaid {
+		tx.Rollback()
 		return nil, core.ErrOrderAlreadyPaid
 	}
 
-	// Buat link pembayaran via gateway (PORT & ADAPTER)
-	paymentURL, transactionID, err := s.gateway.CreatePaymentLink(order)
+	// Hitung sisa tagihan yang belum dialokasikan ke payment mana pun
+	outstanding, err := s.unallocatedAmount(tx, order)
 	if err != nil {
+		tx.Rollback()
 		return nil, core.ErrInternalServer
 	}
 
-	// Simpan payment record dengan IdempotencyKey = transactionID dari gateway
+	amount := req.Amount
+	if amount == 0 {
+		amount = outstanding
+	}
+	if amount <= 0 || amount > outstanding {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: sisa %d", core.ErrOverpayment, outstanding)
+	}
+
 	p := &core.Payment{
-		ID:                    uuid.New(),
-		OrderID:               order.ID,
-		PaymentMethod:         req.PaymentMethod,
-		MidtransTransactionID: &transactionID,
-		IdempotencyKey:        transactionID, // Ini yang di-cek saat webhook masuk
-		AmountPaid:            order.TotalFinalAmount,
-		PaymentStatus:         core.PaymentStatusUnpaid,
+		ID:            uuid.New(),
+		OrderID:       order.ID,
+		PaymentMethod: req.PaymentMethod,
+		AmountPaid:    amount,
+		PaymentStatus: core.PaymentStatusUnpaid,
+	}
+	// Pembulatan dicatat sebagai selisih terpisah; AmountPaid tetap menutup tagihan persis
+	if store, err := s.repo.GetStoreProfile(); err == nil {
+		p.RoundingAdjustment = store.RoundingAdjustment(amount, req.PaymentMethod)
+	}
+
+	// CASH tidak menunggu webhook — langsung lunas di kasir
+	if req.PaymentMethod == core.PaymentMethodCash {
+		resp, err := s.settleCash(tx, order, p, req.AmountTendered)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return nil, core.ErrInternalServer
+		}
+		s.publishPaid(order.ID)
+		resp.OutstandingBalance = outstanding - amount
+		return resp, nil
 	}
 
-	if err := s.repo.Create(p); err != nil {
+	// Simpan payment UNPAID lebih dulu agar nominalnya sudah teralokasi, lalu commit sebelum
+	// memanggil gateway sehingga lock order tidak ditahan selama request ke Midtrans
+	p.IdempotencyKey = "PENDING-" + p.ID.String() // Diganti transactionID gateway setelah link dibuat
+	if err := s.repo.CreateWithTx(tx, p); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
 		return nil, core.ErrInternalServer
 	}
 
+	// Buat link pembayaran via gateway (PORT & ADAPTER)
+	paymentURL, transactionID, err := s.gateway.CreatePaymentLink(order, p)
+	if err != nil {
+		// Lepaskan alokasi agar sisa tagihan bisa dibayar dengan metode lain
+		if err := s.transitionPayment(p, core.PaymentStatusUnpaid, core.PaymentStatusFailed); err != nil && logger.Log != nil {
+			logger.Log.Errorf("Gagal menandai payment %s FAILED setelah gateway menolak link: %v", p.ID, err)
+		}
+		return nil, core.ErrPaymentGateway
+	}
+
+	// IdempotencyKey = transactionID dari gateway; ini yang di-cek saat webhook masuk
+	p.MidtransTransactionID = &transactionID
+	p.IdempotencyKey = transactionID
+	if err := s.attachGatewayTransaction(p); err != nil {
+		return nil, err
+	}
+
 	return &InitiatePaymentResponse{
-		PaymentID:      p.ID.String(),
-		PaymentURL:     paymentURL,
-		TransactionID:  transactionID,
-		IdempotencyKey: p.IdempotencyKey,
-		AmountDue:      order.TotalFinalAmount,
-		PaymentMethod:  req.PaymentMethod,
+		PaymentID:          p.ID.String(),
+		PaymentURL:         paymentURL,
+		TransactionID:      transactionID,
+		IdempotencyKey:     p.IdempotencyKey,
+		AmountDue:          p.AmountCharged(),
+		PaymentMethod:      req.PaymentMethod,
+		RoundingAdjustment: p.RoundingAdjustment,
+		OutstandingBalance: outstanding - amount,
+		PaymentStatus:      p.PaymentStatus,
+	}, nil
+}
+
+// attachGatewayTransaction menyimpan transaction ID gateway ke payment yang sudah di-commit UNPAID.
+// Jika payment di-void selagi link dibuat, transaction ID tetap disimpan (agar settlement yang lolos
+// bisa di-refund webhook) lalu link dimatikan dan ErrPaymentNotPending dikembalikan.
+func (s *paymentService) attachGatewayTransaction(p *core.Payment) error {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := s.repo.LockOrder(tx, p.OrderID); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	locked, err := s.repo.LockPayment(tx, p.ID)
+	if err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := s.repo.SetGatewayTransactionWithTx(tx, p.ID, p.IdempotencyKey); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		// Link sudah ada di gateway tetapi belum tercatat; catat agar bisa direkonsiliasi manual
+		if logger.Log != nil {
+			logger.Log.Errorf("Transaksi gateway %s untuk payment %s gagal disimpan: %v", p.IdempotencyKey, p.ID, err)
+		}
+		return core.ErrInternalServer
+	}
+
+	if locked.PaymentStatus != core.PaymentStatusUnpaid {
+		if err := s.gateway.CancelPaymentLink(p); err != nil && logger.Log != nil {
+			logger.Log.Errorf("Gagal mematikan link payment %s yang sudah di-void: %v", p.ID, err)
+		}
+		return core.ErrPaymentNotPending
+	}
+	return nil
+}
+
+// settleCash melunasi payment tunai secara lokal di dalam tx milik caller (order sudah dikunci):
+// validasi uang diterima terhadap nominal setelah pembulatan, catat kembalian, simpan payment PAID,
+// lalu hitung ulang status order.
+func (s *paymentService) settleCash(tx *gorm.DB, order *core.Order, p *core.Payment, amountTendered int) (*InitiatePaymentResponse, error) {
+	due := p.AmountCharged()
+	if amountTendered < due {
+		return nil, fmt.Errorf("%w: kurang %d", core.ErrInsufficientCash, due-amountTendered)
+	}
+
+	now := time.Now()
+	p.AmountTendered = amountTendered
+	p.ChangeAmount = amountTendered - due
+	p.IdempotencyKey = "CASH-" + p.ID.String() // Unik per payment; tidak ada webhook untuk tunai
+	p.PaymentStatus = core.PaymentStatusPaid
+	p.PaidAt = &now
+
+	if err := s.repo.CreateWithTx(tx, p); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	if err := s.recalculateOrderPaid(tx, order); err != nil {
+		return nil, err
+	}
+
+	return &InitiatePaymentResponse{
+		PaymentID:          p.ID.String(),
+		IdempotencyKey:     p.IdempotencyKey,
+		AmountDue:          due,
+		PaymentMethod:      p.PaymentMethod,
+		RoundingAdjustment: p.RoundingAdjustment,
+		PaymentStatus:      p.PaymentStatus,
+		AmountTendered:     p.AmountTendered,
+		ChangeDue:          p.ChangeAmount,
 	}, nil
 }
 
@@ -90,30 +251,80 @@ func (s *paymentService) HandleWebhook(payload WebhookPayload) error {
 
 	// 3. ⭐ IDEMPOTENCY CHECK — Inti keamanan webhook
 	// Jika sudah PAID, abaikan webhook duplikat. Return nil agar Midtrans tidak retry.
-	if p.PaymentStatus == core.PaymentStatusPaid {
+	// REFUNDED/REFUND_PENDING = settlement terlambat yang sudah/sedang dikembalikan (lihat refundLateSettlement).
+	// FAILED/EXPIRED tetap diproses di bawah: dananya harus dikembalikan, bukan dicatat sebagai pelunasan.
+	if isSettlementHandled(p.PaymentStatus) {
 		return nil
 	}
 
 	// 4. Proses berdasarkan status dari Midtrans
 	switch payload.TransactionStatus {
 	case "settlement", "capture":
-		// Pembayaran berhasil
-		now := time.Now()
-		if err := s.repo.UpdateStatus(p.ID, core.PaymentStatusPaid, &now); err != nil {
+		// Pembayaran berhasil — pelunasan + rekalkulasi status order dalam satu transaksi
+		tx := s.repo.DB().Begin()
+		if tx.Error != nil {
+			return core.ErrInternalServer
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+			}
+		}()
+
+		// Urutan lock: ORDER dulu, baru PAYMENT (sama dengan InitiatePayment & RefundOrder)
+		order, err := s.repo.LockOrder(tx, p.OrderID)
+		if err != nil {
+			tx.Rollback()
 			return core.ErrInternalServer
 		}
-		if err := s.repo.UpdateWebhookTimestamp(p.ID, now); err != nil {
+		locked, err := s.repo.LockPayment(tx, p.ID)
+		if err != nil {
+			tx.Rollback()
 			return core.ErrInternalServer
 		}
-		// Update juga payment_status di tabel orders
-		if err := s.repo.UpdateOrderPaymentStatus(p.OrderID, core.PaymentStatusPaid); err != nil {
+		// Cek ulang setelah lock: webhook duplikat yang datang bersamaan cukup diproses sekali
+		if isSettlementHandled(locked.PaymentStatus) {
+			tx.Rollback()
+			return nil
+		}
+
+		// Dana masuk untuk payment yang tidak lagi ditunggu: jangan dicatat sebagai pelunasan,
+		// kembalikan dananya ke pelanggan. Terjadi jika order sudah dibatalkan/kedaluwarsa (stok sudah
+		// dikembalikan), payment sudah di-void/expire, atau pelunasan ini membuat order kelebihan bayar.
+		reason, err := s.lateSettlementReason(tx, order, locked)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if reason != "" {
+			// Tandai REFUND_PENDING lalu commit sebelum memanggil gateway agar lock tidak ditahan
+			// selama request HTTP ke Midtrans; webhook duplikat melihat status ini dan berhenti.
+			if err := s.repo.UpdateStatusWithTx(tx, locked.ID, core.PaymentStatusRefundPending); err != nil {
+				tx.Rollback()
+				return core.ErrInternalServer
+			}
+			if err := tx.Commit().Error; err != nil {
+				return core.ErrInternalServer
+			}
+			return s.refundLateSettlement(locked, reason)
+		}
+
+		now := time.Now()
+		if err := s.settlePayment(tx, order, locked, now, &now); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit().Error; err != nil {
 			return core.ErrInternalServer
 		}
+		s.publishPaid(order.ID)
 
 	case "cancel", "deny", "expire":
-		// Pembayaran gagal
-		if err := s.repo.UpdateStatus(p.ID, core.PaymentStatusFailed, nil); err != nil {
-			return core.ErrInternalServer
+		// Pembayaran gagal — hanya payment yang masih menunggu yang boleh gagal, sehingga webhook
+		// "expire" yang datang bersamaan tidak menimpa payment yang baru saja PAID
+		if err := s.transitionPayment(p, core.PaymentStatusUnpaid, core.PaymentStatusFailed); err != nil {
+			return err
 		}
 
 	// "pending" dan status lain tidak perlu tindakan
@@ -121,3 +332,611 @@ func (s *paymentService) HandleWebhook(payload WebhookPayload) error {
 
 	return nil
 }
+
+// GetOrderPayments mengembalikan semua payment milik order beserta ringkasan sisa tagihannya.
+func (s *paymentService) GetOrderPayments(orderID uuid.UUID) (*PaymentSummaryResponse, error) {
+	order, err := s.repo.FindOrderByID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+
+	payments, err := s.repo.FindByOrderID(orderID)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	summary := &PaymentSummaryResponse{
+		OrderID:          order.ID,
+		PaymentStatus:    order.PaymentStatus,
+		TotalFinalAmount: order.TotalFinalAmount,
+		Payments:         payments,
+	}
+	for _, p := range payments {
+		switch p.PaymentStatus {
+		case core.PaymentStatusPaid:
+			summary.TotalPaid += p.AmountPaid
+			summary.TotalRounding += p.RoundingAdjustment
+		case core.PaymentStatusUnpaid:
+			summary.TotalPending += p.AmountPaid
+		}
+	}
+	summary.OutstandingBalance = order.TotalFinalAmount - summary.TotalPaid - summary.TotalPending
+	if summary.OutstandingBalance < 0 {
+		summary.OutstandingBalance = 0
+	}
+
+	return summary, nil
+}
+
+// VoidPayment membatalkan payment UNPAID (misal pelanggan ganti metode bayar) sehingga
+// nominalnya kembali menjadi sisa tagihan yang bisa dialokasikan ulang. Link pembayaran di
+// gateway dimatikan SETELAH commit agar gateway yang lambat tidak menahan lock order; settlement
+// yang tetap lolos karena link gagal dimatikan di-refund oleh HandleWebhook.
+func (s *paymentService) VoidPayment(paymentID uuid.UUID) error {
+	p, err := s.repo.FindByID(paymentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return core.ErrNotFound
+		}
+		return core.ErrInternalServer
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := s.repo.LockOrder(tx, p.OrderID); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	locked, err := s.repo.LockPayment(tx, p.ID)
+	if err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if locked.PaymentStatus != core.PaymentStatusUnpaid {
+		tx.Rollback()
+		return core.ErrPaymentNotPending
+	}
+
+	if err := s.repo.UpdateStatusWithTx(tx, locked.ID, core.PaymentStatusFailed); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return core.ErrInternalServer
+	}
+
+	if locked.MidtransTransactionID != nil {
+		if err := s.gateway.CancelPaymentLink(locked); err != nil && logger.Log != nil {
+			logger.Log.Errorf("Gagal mematikan link payment %s yang sudah di-void: %v", locked.ID, err)
+		}
+	}
+	return nil
+}
+
+// settlePayment menandai payment PAID lalu menghitung ulang TotalPaid dan PaymentStatus order.
+// Order dan payment HARUS sudah dikunci oleh caller di dalam tx yang sama.
+func (s *paymentService) settlePayment(tx *gorm.DB, order *core.Order, p *core.Payment, paidAt time.Time, webhookReceivedAt *time.Time) error {
+	if err := s.repo.MarkPaidWithTx(tx, p.ID, paidAt, webhookReceivedAt); err != nil {
+		return core.ErrInternalServer
+	}
+	return s.recalculateOrderPaid(tx, order)
+}
+
+// recalculateOrderPaid menghitung ulang TotalPaid dan PaymentStatus order dari semua payment PAID.
+func (s *paymentService) recalculateOrderPaid(tx *gorm.DB, order *core.Order) error {
+	totalPaid, err := s.repo.SumPaymentsWithTx(tx, order.ID, core.PaymentStatusPaid)
+	if err != nil {
+		return core.ErrInternalServer
+	}
+
+	// Update juga payment_status di tabel orders berdasarkan total yang sudah dibayar
+	status := resolveOrderPaymentStatus(order.TotalFinalAmount, totalPaid)
+	if err := s.repo.UpdateOrderPaidWithTx(tx, order.ID, totalPaid, status); err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// lateSettlementReason menentukan apakah settlement untuk payment yang sudah dikunci harus di-refund
+// alih-alih dicatat. Mengembalikan alasan refund, atau "" jika payment boleh dilunasi.
+func (s *paymentService) lateSettlementReason(tx *gorm.DB, order *core.Order, p *core.Payment) (string, error) {
+	switch {
+	case order.OrderStatus == core.OrderStatusCancelled:
+		return "Order sudah dibatalkan sebelum pembayaran diterima", nil
+	case p.PaymentStatus != core.PaymentStatusUnpaid:
+		return "Pembayaran sudah dibatalkan sebelum dana diterima", nil
+	}
+	totalPaid, err := s.repo.SumPaymentsWithTx(tx, order.ID, core.PaymentStatusPaid)
+	if err != nil {
+		return "", core.ErrInternalServer
+	}
+	if totalPaid+p.AmountPaid > order.TotalFinalAmount {
+		return "Pembayaran melebihi sisa tagihan order", nil
+	}
+	return "", nil
+}
+
+// refundLateSettlement me-refund penuh payment yang settle padahal tidak lagi ditunggu (lihat lateSettlementReason).
+// Payment HARUS sudah di-commit sebagai REFUND_PENDING oleh caller; gateway dipanggil di luar transaksi.
+// Jika gateway gagal, status dikembalikan ke semula dan error dikembalikan agar Midtrans mengirim ulang
+// webhook sehingga refund dicoba lagi.
+func (s *paymentService) refundLateSettlement(p *core.Payment, reason string) error {
+	refundID, err := s.gateway.Refund(p, p.AmountCharged(), reason)
+	if err != nil {
+		if revertErr := s.transitionPayment(p, core.PaymentStatusRefundPending, p.PaymentStatus); revertErr != nil && logger.Log != nil {
+			logger.Log.Errorf("Gagal mengembalikan status payment %s setelah refund gateway gagal: %v", p.ID, revertErr)
+		}
+		return core.ErrPaymentGateway
+	}
+	if err := s.transitionPayment(p, core.PaymentStatusRefundPending, core.PaymentStatusRefunded); err != nil {
+		// Dana sudah dikembalikan gateway; catat refund ID agar bisa direkonsiliasi manual
+		if logger.Log != nil {
+			logger.Log.Errorf("Refund %s untuk payment %s sudah diproses gateway tetapi status gagal disimpan: %v", refundID, p.ID, err)
+		}
+		return err
+	}
+	if logger.Log != nil {
+		logger.Log.Warnf("Settlement terlambat untuk payment %s (order %s) di-refund otomatis (%s), refund ID %s", p.ID, p.OrderID, reason, refundID)
+	}
+	return nil
+}
+
+// transitionPayment mengubah status payment dari `from` ke `to` dalam transaksi singkat (urutan lock:
+// ORDER lalu PAYMENT). Payment yang statusnya sudah bukan `from` dibiarkan tanpa error.
+func (s *paymentService) transitionPayment(p *core.Payment, from, to string) error {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := s.repo.LockOrder(tx, p.OrderID); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	locked, err := s.repo.LockPayment(tx, p.ID)
+	if err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if locked.PaymentStatus != from {
+		tx.Rollback()
+		return nil
+	}
+	if err := s.repo.UpdateStatusWithTx(tx, locked.ID, to); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// isSettlementHandled bernilai true jika settlement untuk payment berstatus ini sudah/sedang diproses.
+func isSettlementHandled(status string) bool {
+	switch status {
+	case core.PaymentStatusPaid, core.PaymentStatusRefunded, core.PaymentStatusRefundPending:
+		return true
+	}
+	return false
+}
+
+// publishPaid memberi tahu kitchen display bahwa pembayaran order berubah (dipanggil setelah commit).
+// Order dibaca ulang agar event membawa PaymentStatus terbaru (PAID / PARTIALLY_PAID).
+func (s *paymentService) publishPaid(orderID uuid.UUID) {
+	if s.events == nil {
+		return
+	}
+	order, err := s.repo.FindOrderByID(orderID)
+	if err != nil {
+		return
+	}
+	s.events.Publish(core.OrderEvent{
+		Type:          core.EventOrderPaid,
+		OrderID:       order.ID,
+		OrderSource:   order.OrderSource,
+		OrderStatus:   order.OrderStatus,
+		PaymentStatus: order.PaymentStatus,
+		OccurredAt:    time.Now(),
+	})
+}
+
+// unallocatedAmount menghitung sisa tagihan yang belum ditutup payment PAID maupun UNPAID (pending).
+func (s *paymentService) unallocatedAmount(tx *gorm.DB, order *core.Order) (int, error) {
+	allocated, err := s.repo.SumPaymentsWithTx(tx, order.ID, core.PaymentStatusPaid, core.PaymentStatusUnpaid)
+	if err != nil {
+		return 0, err
+	}
+	return order.TotalFinalAmount - allocated, nil
+}
+
+// RefundOrder mengembalikan dana per item untuk order yang sudah lunas.
+// Order dikunci FOR UPDATE sehingga dua refund concurrent tidak bisa melebihi qty maupun total bayar.
+// Refund non-tunai di-commit sebagai PENDING sebelum gateway dipanggil (lock tidak ditahan selama
+// request ke Midtrans), lalu diselesaikan oleh completeRefund atau dilepas oleh releaseRefund.
+// Status baris Payment sengaja TIDAK diubah: webhook settlement yang terlambat tetap idempotent.
+func (s *paymentService) RefundOrder(req RefundRequest, createdBy *uuid.UUID) (*core.Refund, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return nil, core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 1. Kunci order dan pastikan sudah lunas
+	order, err := s.repo.LockOrder(tx, req.OrderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if order.PaymentStatus != core.PaymentStatusPaid && order.PaymentStatus != core.PaymentStatusPartiallyRefunded {
+		tx.Rollback()
+		return nil, core.ErrOrderNotPaid
+	}
+
+	items, err := s.repo.FindOrderItemsWithTx(tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	itemByID := make(map[uuid.UUID]*core.OrderItem, len(items))
+	for i := range items {
+		itemByID[items[i].ID] = &items[i]
+	}
+
+	// 2. Gabungkan qty per OrderItem (request boleh menyebut item yang sama dua kali)
+	requestedQty := make(map[uuid.UUID]int)
+	var orderItemIDs []uuid.UUID
+	for _, in := range req.Items {
+		if _, seen := requestedQty[in.OrderItemID]; !seen {
+			orderItemIDs = append(orderItemIDs, in.OrderItemID)
+		}
+		requestedQty[in.OrderItemID] += in.Qty
+	}
+
+	// 3. Validasi qty dan hitung nominal refund per item
+	refundID := uuid.New()
+	var refundItems []core.RefundItem
+	restockQty := make(map[uuid.UUID]int)
+	restockOptionQty := make(map[uuid.UUID]int)
+	totalAmount := 0
+
+	for _, itemID := range orderItemIDs {
+		item, ok := itemByID[itemID]
+		if !ok {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: item %s bukan bagian dari order ini", core.ErrNotFound, itemID)
+		}
+		qty := requestedQty[itemID]
+		if item.RefundedQty+qty > item.Qty {
+			tx.Rollback()
+			return nil, fmt.Errorf("%w: tersisa %d", core.ErrRefundExceedsQty, item.Qty-item.RefundedQty)
+		}
+
+		amount := calculateRefundAmount(order, item, qty)
+		totalAmount += amount
+		item.RefundedQty += qty
+		item.AddStockQty(restockQty, qty) // Bundle: yang di-restock adalah komponennya
+		for _, m := range item.Modifiers {
+			restockOptionQty[m.ModifierOptionID] += qty
+		}
+
+		refundItems = append(refundItems, core.RefundItem{
+			ID:          uuid.New(),
+			RefundID:    refundID,
+			OrderItemID: item.ID,
+			Qty:         qty,
+			Amount:      amount,
+		})
+	}
+
+	// 4. Jika seluruh item sudah dikembalikan, sisa (platform fee + sisa pembagian prorata) ikut
+	// dikembalikan sehingga total refund selalu sama persis dengan TotalFinalAmount. Selisih pembulatan
+	// kas (Payment.RoundingAdjustment) TIDAK ikut dikembalikan: selisih itu milik laci kas dan sudah
+	// tercatat terpisah di rekap shift, bukan bagian dari tagihan order.
+	fullyRefunded := true
+	for _, item := range items {
+		if item.RefundedQty < item.Qty {
+			fullyRefunded = false
+			break
+		}
+	}
+	remaining := order.TotalFinalAmount - order.TotalRefunded
+	if fullyRefunded || totalAmount > remaining {
+		totalAmount = remaining
+	}
+
+	// Tentukan payment sumber dana dan pastikan dananya cukup (split payment: per payment)
+	paid, err := s.repo.FindPaidPaymentWithTx(tx, order.ID, req.PaymentID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	alreadyRefunded, err := s.repo.SumRefundedByPaymentWithTx(tx, paid.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+	if totalAmount > paid.AmountPaid-alreadyRefunded {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: tersisa %d", core.ErrRefundExceedsPaid, paid.AmountPaid-alreadyRefunded)
+	}
+
+	// 5. Restock (opsional) — refund non-tunai baru me-restock setelah gateway berhasil (completeRefund)
+	viaGateway := paid.PaymentMethod != core.PaymentMethodCash
+	if req.Restock && !viaGateway {
+		if err := s.restockWithTx(tx, restockQty, restockOptionQty); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	for _, itemID := range orderItemIDs {
+		if err := s.repo.UpdateRefundedQtyWithTx(tx, itemID, itemByID[itemID].RefundedQty); err != nil {
+			tx.Rollback()
+			return nil, core.ErrInternalServer
+		}
+	}
+
+	// 6. Catat refund; non-tunai tetap PENDING sampai gateway mengonfirmasi
+	refund := &core.Refund{
+		ID:        refundID,
+		OrderID:   order.ID,
+		PaymentID: paid.ID,
+		Amount:    totalAmount,
+		Reason:    req.Reason,
+		Restock:   req.Restock,
+		Status:    core.RefundStatusCompleted,
+		CreatedBy: createdBy,
+		Items:     refundItems,
+	}
+	if viaGateway {
+		refund.Status = core.RefundStatusPending
+	}
+
+	if err := s.repo.CreateRefundWithTx(tx, refund); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	// 7. Update akumulasi refund dan status pembayaran order
+	totalRefunded := order.TotalRefunded + totalAmount
+	paymentStatus := core.PaymentStatusPartiallyRefunded
+	if totalRefunded >= order.TotalFinalAmount {
+		paymentStatus = core.PaymentStatusRefunded
+	}
+	if err := s.repo.UpdateOrderRefundWithTx(tx, order.ID, totalRefunded, paymentStatus); err != nil {
+		tx.Rollback()
+		return nil, core.ErrInternalServer
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, core.ErrInternalServer
+	}
+	if !viaGateway {
+		return refund, nil
+	}
+
+	// 8. Payment non-tunai dikembalikan lewat gateway (PORT & ADAPTER), di luar transaksi
+	gatewayRefundID, err := s.gateway.Refund(paid, totalAmount, req.Reason)
+	if err != nil {
+		if err := s.releaseRefund(refund); err != nil && logger.Log != nil {
+			logger.Log.Errorf("Gagal melepas refund %s setelah gateway menolak: %v", refund.ID, err)
+		}
+		return nil, core.ErrPaymentGateway
+	}
+	if !req.Restock {
+		restockQty, restockOptionQty = nil, nil
+	}
+	if err := s.completeRefund(refund, gatewayRefundID, restockQty, restockOptionQty); err != nil {
+		// Dana sudah dikembalikan gateway; catat refund ID agar bisa direkonsiliasi manual
+		if logger.Log != nil {
+			logger.Log.Errorf("Refund gateway %s untuk refund %s sudah diproses tetapi gagal diselesaikan: %v", gatewayRefundID, refund.ID, err)
+		}
+		return nil, err
+	}
+	refund.Status = core.RefundStatusCompleted
+	refund.GatewayRefundID = &gatewayRefundID
+
+	return refund, nil
+}
+
+// completeRefund menyelesaikan refund PENDING setelah gateway berhasil: restock (jika diminta)
+// lalu simpan GatewayRefundID. Order dikunci lebih dulu, sama seperti RefundOrder.
+func (s *paymentService) completeRefund(refund *core.Refund, gatewayRefundID string, restockQty, restockOptionQty map[uuid.UUID]int) error {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := s.repo.LockOrder(tx, refund.OrderID); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := s.restockWithTx(tx, restockQty, restockOptionQty); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.repo.UpdateRefundWithTx(tx, refund.ID, core.RefundStatusCompleted, &gatewayRefundID); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// releaseRefund menandai refund PENDING sebagai FAILED dan melepas qty serta nominal yang sudah
+// dialokasikan, sehingga item yang sama bisa di-refund ulang.
+func (s *paymentService) releaseRefund(refund *core.Refund) error {
+	tx := s.repo.DB().Begin()
+	if tx.Error != nil {
+		return core.ErrInternalServer
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := s.repo.LockOrder(tx, refund.OrderID)
+	if err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	items, err := s.repo.FindOrderItemsWithTx(tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	refundedQty := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		refundedQty[item.ID] = item.RefundedQty
+	}
+	for _, ri := range refund.Items {
+		if err := s.repo.UpdateRefundedQtyWithTx(tx, ri.OrderItemID, refundedQty[ri.OrderItemID]-ri.Qty); err != nil {
+			tx.Rollback()
+			return core.ErrInternalServer
+		}
+	}
+
+	totalRefunded := order.TotalRefunded - refund.Amount
+	paymentStatus := core.PaymentStatusPaid
+	if totalRefunded > 0 {
+		paymentStatus = core.PaymentStatusPartiallyRefunded
+	}
+	if err := s.repo.UpdateOrderRefundWithTx(tx, order.ID, totalRefunded, paymentStatus); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := s.repo.UpdateRefundWithTx(tx, refund.ID, core.RefundStatusFailed, nil); err != nil {
+		tx.Rollback()
+		return core.ErrInternalServer
+	}
+	if err := tx.Commit().Error; err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// restockWithTx mengembalikan stok produk (komponen untuk bundle) lalu opsi modifier di dalam tx milik caller.
+// Produk dikunci dengan urutan yang SAMA seperti Checkout (anti-deadlock).
+func (s *paymentService) restockWithTx(tx *gorm.DB, restockQty, restockOptionQty map[uuid.UUID]int) error {
+	var productIDs []uuid.UUID
+	for productID := range restockQty {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool {
+		return core.LessProductID(productIDs[i], productIDs[j])
+	})
+
+	for _, productID := range productIDs {
+		product, err := s.repo.LockAndGetProduct(tx, productID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue // Produk sudah dihapus dari katalog
+			}
+			return core.ErrInternalServer
+		}
+		product.Stock += restockQty[productID]
+		if err := s.repo.SaveProductWithTx(tx, product); err != nil {
+			return core.ErrInternalServer
+		}
+	}
+
+	// Stok opsi modifier (mis. extra shot) dikunci SETELAH produk, juga dengan urutan tetap
+	var optionIDs []uuid.UUID
+	for optionID := range restockOptionQty {
+		optionIDs = append(optionIDs, optionID)
+	}
+	sort.Slice(optionIDs, func(i, j int) bool {
+		return core.LessModifierOptionID(optionIDs[i], optionIDs[j])
+	})
+
+	for _, optionID := range optionIDs {
+		option, err := s.repo.LockModifierOption(tx, optionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue // Opsi sudah dihapus dari menu
+			}
+			return core.ErrInternalServer
+		}
+		if !option.TrackStock {
+			continue
+		}
+		option.Stock += restockOptionQty[optionID]
+		if err := s.repo.SaveModifierOptionWithTx(tx, option); err != nil {
+			return core.ErrInternalServer
+		}
+	}
+	return nil
+}
+
+// resolveOrderPaymentStatus menentukan PaymentStatus order dari total yang sudah dibayar.
+func resolveOrderPaymentStatus(totalFinalAmount, totalPaid int) string {
+	switch {
+	case totalPaid >= totalFinalAmount:
+		return core.PaymentStatusPaid
+	case totalPaid > 0:
+		return core.PaymentStatusPartiallyPaid
+	default:
+		return core.PaymentStatusUnpaid
+	}
+}
+
+// calculateRefundAmount menghitung nominal refund satu item: diskon promo item itu sendiri
+// dikurangi per qty, lalu diskon voucher order serta service charge & pajak eksklusif yang ikut
+// dibayar dibagi proporsional, sehingga pelanggan hanya menerima kembali apa yang benar-benar dibayar.
+func calculateRefundAmount(order *core.Order, item *core.OrderItem, qty int) int {
+	gross := item.UnitPrice * qty
+	if order.TotalBasePrice <= 0 {
+		return gross
+	}
+	net := gross
+	if item.PromoDiscount > 0 && item.Qty > 0 {
+		net -= item.PromoDiscount * qty / item.Qty
+	}
+	if voucher, base := order.TotalDiscount-order.TotalPromoDiscount, order.TotalBasePrice-order.TotalPromoDiscount; voucher > 0 && base > 0 {
+		net -= net * voucher / base
+	}
+	if charges, taxable := order.ExclusiveCharges(), order.TotalBasePrice-order.TotalDiscount; charges > 0 && taxable > 0 {
+		net += net * charges / taxable
+	}
+	return net
+}
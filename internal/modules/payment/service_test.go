@@ -0,0 +1,683 @@
+package payment_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"go-fiber-pos/internal/core"
+
+	"go-fiber-pos/internal/modules/payment"
+	"go-fiber-pos/internal/modules/payment/mocks"
+	"go-fiber-pos/internal/testutil"
+
+	"github.com/google/uuid"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+const transactionID = "MOCK-MIDTRANS-TEST"
+
+func settlementPayload() payment.WebhookPayload {
+	return payment.WebhookPayload{OrderID: transactionID, TransactionStatus: "settlement", SignatureKey: "MOCK_VALID"}
+}
+
+func newPayment(orderID uuid.UUID, status string, amount int) *core.Payment {
+	txID := transactionID
+	return &core.Payment{
+		ID:                    uuid.New(),
+		OrderID:               orderID,
+		PaymentMethod:         core.PaymentMethodQRIS,
+		MidtransTransactionID: &txID,
+		IdempotencyKey:        transactionID,
+		AmountPaid:            amount,
+		PaymentStatus:         status,
+	}
+}
+
+func TestHandleWebhook_Gomock(t *testing.T) {
+	testCases := []struct {
+		name          string
+		order         core.Order
+		payment       func(orderID uuid.UUID) *core.Payment
+		lockedStatus  string // Status payment saat dibaca ulang dengan lock; kosong = sama dengan pembacaan awal
+		payload       payment.WebhookPayload
+		buildStubs    func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment)
+		expectedError error
+	}{
+		{
+			name:    "Sukses - Settlement melunasi payment UNPAID",
+			order:   core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000},
+			payment: func(orderID uuid.UUID) *core.Payment { return newPayment(orderID, core.PaymentStatusUnpaid, 50000) },
+			payload: settlementPayload(),
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment) {
+				mockRepo.EXPECT().SumPaymentsWithTx(gomock.Any(), p.OrderID, core.PaymentStatusPaid).Return(0, nil).Times(1)
+				mockRepo.EXPECT().MarkPaidWithTx(gomock.Any(), p.ID, gomock.Any(), gomock.Not(gomock.Nil())).Return(nil).Times(1)
+				mockRepo.EXPECT().SumPaymentsWithTx(gomock.Any(), p.OrderID, core.PaymentStatusPaid).Return(50000, nil).Times(1)
+				mockRepo.EXPECT().UpdateOrderPaidWithTx(gomock.Any(), p.OrderID, 50000, core.PaymentStatusPaid).Return(nil).Times(1)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "Sukses - Settlement payment yang sudah di-void di-refund",
+			order:   core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000},
+			payment: func(orderID uuid.UUID) *core.Payment { return newPayment(orderID, core.PaymentStatusFailed, 50000) },
+			payload: settlementPayload(),
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment) {
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusRefundPending).Return(nil).Times(1)
+				mockGateway.EXPECT().Refund(gomock.Any(), 50000, gomock.Any()).Return("REFUND-1", nil).Times(1)
+				// Transaksi kedua: REFUND_PENDING → REFUNDED
+				mockRepo.EXPECT().LockOrder(gomock.Any(), p.OrderID).Return(&core.Order{ID: p.OrderID}, nil).Times(1)
+				pending := *p
+				pending.PaymentStatus = core.PaymentStatusRefundPending
+				mockRepo.EXPECT().LockPayment(gomock.Any(), p.ID).Return(&pending, nil).Times(1)
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusRefunded).Return(nil).Times(1)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "Sukses - Settlement melebihi sisa tagihan di-refund",
+			order:   core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusPartiallyPaid, TotalFinalAmount: 50000},
+			payment: func(orderID uuid.UUID) *core.Payment { return newPayment(orderID, core.PaymentStatusUnpaid, 30000) },
+			payload: settlementPayload(),
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment) {
+				// Payment lain sudah melunasi 40.000 dari 50.000
+				mockRepo.EXPECT().SumPaymentsWithTx(gomock.Any(), p.OrderID, core.PaymentStatusPaid).Return(40000, nil).Times(1)
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusRefundPending).Return(nil).Times(1)
+				mockGateway.EXPECT().Refund(gomock.Any(), 30000, gomock.Any()).Return("REFUND-1", nil).Times(1)
+				mockRepo.EXPECT().LockOrder(gomock.Any(), p.OrderID).Return(&core.Order{ID: p.OrderID}, nil).Times(1)
+				pending := *p
+				pending.PaymentStatus = core.PaymentStatusRefundPending
+				mockRepo.EXPECT().LockPayment(gomock.Any(), p.ID).Return(&pending, nil).Times(1)
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusRefunded).Return(nil).Times(1)
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "Gagal - Refund gateway gagal, status payment dikembalikan",
+			order:   core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000},
+			payment: func(orderID uuid.UUID) *core.Payment { return newPayment(orderID, core.PaymentStatusFailed, 50000) },
+			payload: settlementPayload(),
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment) {
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusRefundPending).Return(nil).Times(1)
+				mockGateway.EXPECT().Refund(gomock.Any(), 50000, gomock.Any()).Return("", errors.New("gateway timeout")).Times(1)
+				mockRepo.EXPECT().LockOrder(gomock.Any(), p.OrderID).Return(&core.Order{ID: p.OrderID}, nil).Times(1)
+				pending := *p
+				pending.PaymentStatus = core.PaymentStatusRefundPending
+				mockRepo.EXPECT().LockPayment(gomock.Any(), p.ID).Return(&pending, nil).Times(1)
+				// Dikembalikan ke FAILED agar webhook yang dikirim ulang mencoba refund lagi
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusFailed).Return(nil).Times(1)
+			},
+			expectedError: core.ErrPaymentGateway,
+		},
+		{
+			name:    "Sukses - Webhook duplikat terlihat PAID setelah lock",
+			order:   core.Order{OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusPaid, TotalFinalAmount: 50000},
+			payment: func(orderID uuid.UUID) *core.Payment { return newPayment(orderID, core.PaymentStatusUnpaid, 50000) },
+			// Webhook pertama melunasi payment di antara pembacaan awal dan lock
+			lockedStatus: core.PaymentStatusPaid,
+			payload:      settlementPayload(),
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment) {
+			},
+			expectedError: nil,
+		},
+		{
+			name:    "Gagal - Signature tidak valid",
+			payment: func(orderID uuid.UUID) *core.Payment { return newPayment(orderID, core.PaymentStatusUnpaid, 50000) },
+			payload: payment.WebhookPayload{OrderID: transactionID, TransactionStatus: "settlement", SignatureKey: "palsu"},
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment) {
+			},
+			expectedError: core.ErrInvalidSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			mockGateway := mocks.NewMockPaymentGateway(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			order := tc.order
+			order.ID = uuid.New()
+			p := tc.payment(order.ID)
+
+			mockGateway.EXPECT().VerifySignature(tc.payload).Return(tc.payload.SignatureKey == "MOCK_VALID").Times(1)
+			if tc.expectedError != core.ErrInvalidSignature {
+				mockRepo.EXPECT().FindByIdempotencyKey(transactionID).Return(p, nil).Times(1)
+				mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+				// Transaksi pertama selalu mengunci order lalu payment yang sama
+				first := mockRepo.EXPECT().LockOrder(gomock.Any(), order.ID).Return(&order, nil).Times(1)
+				lockedCopy := *p
+				if tc.lockedStatus != "" {
+					lockedCopy.PaymentStatus = tc.lockedStatus
+				}
+				mockRepo.EXPECT().LockPayment(gomock.Any(), p.ID).Return(&lockedCopy, nil).Times(1).After(first)
+			}
+			tc.buildStubs(mockRepo, mockGateway, p)
+
+			service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+			err := service.HandleWebhook(tc.payload)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVoidPayment_Gomock(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        string
+		buildStubs    func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment, txdb *testutil.TxDB)
+		expectedError error
+	}{
+		{
+			name:   "Sukses - Void mematikan link gateway setelah commit",
+			status: core.PaymentStatusUnpaid,
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment, txdb *testutil.TxDB) {
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusFailed).Return(nil).Times(1)
+				mockGateway.EXPECT().CancelPaymentLink(gomock.Any()).DoAndReturn(func(*core.Payment) error {
+					// Lock order & payment sudah dilepas saat gateway dipanggil
+					assert.Equal(t, 1, txdb.Commits())
+					return nil
+				}).Times(1)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Sukses - Gateway gagal, payment tetap di-void",
+			status: core.PaymentStatusUnpaid,
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment, txdb *testutil.TxDB) {
+				mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), p.ID, core.PaymentStatusFailed).Return(nil).Times(1)
+				mockGateway.EXPECT().CancelPaymentLink(gomock.Any()).Return(errors.New("gateway timeout")).Times(1)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Gagal - Payment sudah PAID",
+			status: core.PaymentStatusPaid,
+			buildStubs: func(mockRepo *mocks.MockPaymentRepository, mockGateway *mocks.MockPaymentGateway, p *core.Payment, txdb *testutil.TxDB) {
+			},
+			expectedError: core.ErrPaymentNotPending,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			mockGateway := mocks.NewMockPaymentGateway(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			p := newPayment(uuid.New(), tc.status, 50000)
+			mockRepo.EXPECT().FindByID(p.ID).Return(p, nil).Times(1)
+			mockRepo.EXPECT().DB().Return(txdb.DB).Times(1)
+			mockRepo.EXPECT().LockOrder(gomock.Any(), p.OrderID).Return(&core.Order{ID: p.OrderID}, nil).Times(1)
+			lockedCopy := *p
+			mockRepo.EXPECT().LockPayment(gomock.Any(), p.ID).Return(&lockedCopy, nil).Times(1)
+			tc.buildStubs(mockRepo, mockGateway, p, txdb)
+
+			service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+			err := service.VoidPayment(p.ID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, txdb.Commits())
+			}
+		})
+	}
+}
+
+// paymentStore meniru baris order & payment di database untuk skenario beberapa langkah.
+// rowLock meniru FOR UPDATE: diambil oleh LockOrder dan dilepas saat transaksi selesai.
+type paymentStore struct {
+	mu       sync.Mutex
+	rowLock  sync.Mutex
+	order    core.Order
+	payments map[uuid.UUID]*core.Payment
+	markPaid int
+}
+
+func (s *paymentStore) payment(id uuid.UUID) *core.Payment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *s.payments[id]
+	return &cp
+}
+
+// newPaymentStore memasang mock repository yang membaca/menulis ke paymentStore.
+func newPaymentStore(t *testing.T, mockRepo *mocks.MockPaymentRepository, order core.Order, payments ...*core.Payment) *paymentStore {
+	store := &paymentStore{order: order, payments: make(map[uuid.UUID]*core.Payment)}
+	for _, p := range payments {
+		store.payments[p.ID] = p
+	}
+
+	txdb := testutil.NewTxDB(t)
+	txdb.OnEnd = store.rowLock.Unlock
+
+	mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+	mockRepo.EXPECT().FindByID(gomock.Any()).DoAndReturn(func(id uuid.UUID) (*core.Payment, error) {
+		return store.payment(id), nil
+	}).AnyTimes()
+	mockRepo.EXPECT().FindByIdempotencyKey(gomock.Any()).DoAndReturn(func(key string) (*core.Payment, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		for _, p := range store.payments {
+			if p.IdempotencyKey == key {
+				cp := *p
+				return &cp, nil
+			}
+		}
+		return nil, errors.New("record not found")
+	}).AnyTimes()
+	mockRepo.EXPECT().LockOrder(gomock.Any(), order.ID).DoAndReturn(func(_ any, _ uuid.UUID) (*core.Order, error) {
+		store.rowLock.Lock()
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		cp := store.order
+		return &cp, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().LockPayment(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, id uuid.UUID) (*core.Payment, error) {
+		return store.payment(id), nil
+	}).AnyTimes()
+	mockRepo.EXPECT().UpdateStatusWithTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, id uuid.UUID, status string) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		store.payments[id].PaymentStatus = status
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().SumPaymentsWithTx(gomock.Any(), order.ID, gomock.Any()).DoAndReturn(func(_ any, _ uuid.UUID, statuses ...string) (int, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		total := 0
+		for _, p := range store.payments {
+			for _, status := range statuses {
+				if p.PaymentStatus == status {
+					total += p.AmountPaid
+				}
+			}
+		}
+		return total, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().MarkPaidWithTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, id uuid.UUID, _, _ any) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		store.payments[id].PaymentStatus = core.PaymentStatusPaid
+		store.markPaid++
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().UpdateOrderPaidWithTx(gomock.Any(), order.ID, gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, _ uuid.UUID, totalPaid int, status string) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		store.order.TotalPaid = totalPaid
+		store.order.PaymentStatus = status
+		return nil
+	}).AnyTimes()
+	return store
+}
+
+func TestVoidThenLateSettlement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockGateway := mocks.NewMockPaymentGateway(ctrl)
+
+	order := core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000}
+	p := newPayment(order.ID, core.PaymentStatusUnpaid, 50000)
+	store := newPaymentStore(t, mockRepo, order, p)
+
+	mockGateway.EXPECT().CancelPaymentLink(gomock.Any()).Return(nil).Times(1)
+	mockGateway.EXPECT().VerifySignature(gomock.Any()).Return(true).Times(2)
+	mockGateway.EXPECT().Refund(gomock.Any(), 50000, gomock.Any()).Return("REFUND-1", nil).Times(1)
+
+	service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+	assert.NoError(t, service.VoidPayment(p.ID))
+	// Pelanggan tetap membayar link lama: dana dikembalikan, bukan dicatat sebagai pelunasan
+	assert.NoError(t, service.HandleWebhook(settlementPayload()))
+	// Midtrans mengirim ulang webhook yang sama: tidak ada refund kedua
+	assert.NoError(t, service.HandleWebhook(settlementPayload()))
+
+	assert.Equal(t, core.PaymentStatusRefunded, store.payment(p.ID).PaymentStatus)
+	assert.Equal(t, 0, store.markPaid)
+	assert.Equal(t, core.PaymentStatusUnpaid, store.order.PaymentStatus)
+}
+
+func TestConcurrentDuplicateSettlement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockGateway := mocks.NewMockPaymentGateway(ctrl)
+
+	order := core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000}
+	p := newPayment(order.ID, core.PaymentStatusUnpaid, 50000)
+	store := newPaymentStore(t, mockRepo, order, p)
+
+	mockGateway.EXPECT().VerifySignature(gomock.Any()).Return(true).AnyTimes()
+
+	service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+	const webhooks = 8
+	errs := make(chan error, webhooks)
+	var wg sync.WaitGroup
+	for i := 0; i < webhooks; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- service.HandleWebhook(settlementPayload())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, store.markPaid)
+	assert.Equal(t, 50000, store.order.TotalPaid)
+	assert.Equal(t, core.PaymentStatusPaid, store.order.PaymentStatus)
+}
+
+func TestExpireThenLateSettlement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockGateway := mocks.NewMockPaymentGateway(ctrl)
+
+	order := core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000}
+	p := newPayment(order.ID, core.PaymentStatusUnpaid, 50000)
+	store := newPaymentStore(t, mockRepo, order, p)
+
+	mockGateway.EXPECT().VerifySignature(gomock.Any()).Return(true).Times(3)
+	mockGateway.EXPECT().Refund(gomock.Any(), 50000, gomock.Any()).Return("REFUND-1", nil).Times(1)
+
+	service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+	expire := payment.WebhookPayload{OrderID: transactionID, TransactionStatus: "expire", SignatureKey: "MOCK_VALID"}
+	assert.NoError(t, service.HandleWebhook(expire))
+	assert.Equal(t, core.PaymentStatusFailed, store.payment(p.ID).PaymentStatus)
+
+	// Order ikut kedaluwarsa (job auto-expire) sebelum settlement terlambat masuk
+	store.order.OrderStatus = core.OrderStatusCancelled
+
+	assert.NoError(t, service.HandleWebhook(settlementPayload()))
+	// Webhook "expire" yang dikirim ulang tidak boleh menimpa status REFUNDED
+	assert.NoError(t, service.HandleWebhook(expire))
+
+	assert.Equal(t, core.PaymentStatusRefunded, store.payment(p.ID).PaymentStatus)
+	assert.Equal(t, 0, store.markPaid)
+	assert.Equal(t, 0, store.order.TotalPaid)
+}
+
+func TestExpireAfterSettlementKeepsPaid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockGateway := mocks.NewMockPaymentGateway(ctrl)
+
+	order := core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusPending, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: 50000}
+	p := newPayment(order.ID, core.PaymentStatusUnpaid, 50000)
+	store := newPaymentStore(t, mockRepo, order, p)
+
+	mockGateway.EXPECT().VerifySignature(gomock.Any()).Return(true).Times(2)
+
+	service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+	assert.NoError(t, service.HandleWebhook(settlementPayload()))
+	assert.NoError(t, service.HandleWebhook(payment.WebhookPayload{OrderID: transactionID, TransactionStatus: "expire", SignatureKey: "MOCK_VALID"}))
+
+	assert.Equal(t, core.PaymentStatusPaid, store.payment(p.ID).PaymentStatus)
+	assert.Equal(t, core.PaymentStatusPaid, store.order.PaymentStatus)
+}
+
+func TestRefundOrder_Gomock(t *testing.T) {
+	latte := core.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Qty: 2, UnitPrice: 20000, Subtotal: 40000}
+	cookie := core.OrderItem{ID: uuid.New(), ProductID: uuid.New(), Qty: 1, UnitPrice: 10000, Subtotal: 10000}
+	promoLatte := latte
+	promoLatte.PromoDiscount = 4000
+	refundedLatte := latte
+	refundedLatte.RefundedQty = 2
+
+	testCases := []struct {
+		name           string
+		order          core.Order
+		items          []core.OrderItem
+		refund         []payment.RefundItemInput
+		rounding       int
+		expectedAmount int
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:           "Sukses - Refund satu item membuat order PARTIALLY_REFUNDED",
+			order:          core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPaid, TotalBasePrice: 50000, TotalFinalAmount: 50000, TotalPaid: 50000},
+			items:          []core.OrderItem{latte, cookie},
+			refund:         []payment.RefundItemInput{{OrderItemID: latte.ID, Qty: 1}},
+			expectedAmount: 20000,
+			expectedStatus: core.PaymentStatusPartiallyRefunded,
+		},
+		{
+			name:           "Sukses - Refund penuh mengembalikan platform fee tanpa selisih pembulatan kas",
+			order:          core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPaid, TotalBasePrice: 50000, PlatformFee: 1050, TotalFinalAmount: 51050, TotalPaid: 51050},
+			items:          []core.OrderItem{latte, cookie},
+			refund:         []payment.RefundItemInput{{OrderItemID: latte.ID, Qty: 2}, {OrderItemID: cookie.ID, Qty: 1}},
+			rounding:       -50,
+			expectedAmount: 51050,
+			expectedStatus: core.PaymentStatusRefunded,
+		},
+		{
+			// Voucher 5.000 dari dasar 50.000 → 10% dari harga item ikut dipotong
+			name:           "Sukses - Refund diprorata terhadap voucher",
+			order:          core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPaid, TotalBasePrice: 50000, TotalDiscount: 5000, TotalFinalAmount: 45000, TotalPaid: 45000},
+			items:          []core.OrderItem{latte, cookie},
+			refund:         []payment.RefundItemInput{{OrderItemID: latte.ID, Qty: 1}},
+			expectedAmount: 18000,
+			expectedStatus: core.PaymentStatusPartiallyRefunded,
+		},
+		{
+			// Promo item (4.000 untuk 2 qty) dipotong dulu, lalu voucher 4.600 atas dasar setelah promo 46.000
+			name:           "Sukses - Refund diprorata terhadap promo item lalu voucher",
+			order:          core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPaid, TotalBasePrice: 50000, TotalPromoDiscount: 4000, TotalDiscount: 8600, TotalFinalAmount: 41400, TotalPaid: 41400},
+			items:          []core.OrderItem{promoLatte, cookie},
+			refund:         []payment.RefundItemInput{{OrderItemID: promoLatte.ID, Qty: 1}},
+			expectedAmount: 16200,
+			expectedStatus: core.PaymentStatusPartiallyRefunded,
+		},
+		{
+			// Service 2.500 + PB1 5.250 eksklusif (15,5% dari dasar); PPN inklusif 4.955 tidak menambah refund
+			name:           "Sukses - Refund ikut mengembalikan charge eksklusif saja",
+			order:          core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPaid, TotalBasePrice: 50000, TotalServiceCharge: 2500, TotalTax: 10205, TotalTaxIncluded: 4955, TotalFinalAmount: 57750, TotalPaid: 57750},
+			items:          []core.OrderItem{latte, cookie},
+			refund:         []payment.RefundItemInput{{OrderItemID: cookie.ID, Qty: 1}},
+			expectedAmount: 11550,
+			expectedStatus: core.PaymentStatusPartiallyRefunded,
+		},
+		{
+			// Prorata cookie hanya 9.334; item terakhir mengambil sisa 10.333 (platform fee 1.000 + sisa pembagian voucher)
+			name:           "Sukses - Item terakhir mengembalikan seluruh sisa pembayaran",
+			order:          core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPartiallyRefunded, TotalBasePrice: 50000, TotalDiscount: 3333, PlatformFee: 1000, TotalFinalAmount: 47667, TotalPaid: 47667, TotalRefunded: 37334},
+			items:          []core.OrderItem{refundedLatte, cookie},
+			refund:         []payment.RefundItemInput{{OrderItemID: cookie.ID, Qty: 1}},
+			expectedAmount: 10333,
+			expectedStatus: core.PaymentStatusRefunded,
+		},
+		{
+			name:          "Gagal - Order belum lunas",
+			order:         core.Order{OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusPartiallyPaid, TotalBasePrice: 50000, TotalFinalAmount: 50000, TotalPaid: 20000},
+			refund:        []payment.RefundItemInput{{OrderItemID: latte.ID, Qty: 1}},
+			expectedError: core.ErrOrderNotPaid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			mockGateway := mocks.NewMockPaymentGateway(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			order := tc.order
+			order.ID = uuid.New()
+			paid := &core.Payment{ID: uuid.New(), OrderID: order.ID, PaymentMethod: core.PaymentMethodCash, AmountPaid: order.TotalPaid, RoundingAdjustment: tc.rounding, PaymentStatus: core.PaymentStatusPaid}
+
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockOrder(gomock.Any(), order.ID).Return(&order, nil).Times(1)
+
+			var created core.Refund
+			if tc.expectedError == nil {
+				items := make([]core.OrderItem, len(tc.items))
+				copy(items, tc.items)
+				mockRepo.EXPECT().FindOrderItemsWithTx(gomock.Any(), order.ID).Return(items, nil).Times(1)
+				mockRepo.EXPECT().FindPaidPaymentWithTx(gomock.Any(), order.ID, nil).Return(paid, nil).Times(1)
+				mockRepo.EXPECT().SumRefundedByPaymentWithTx(gomock.Any(), paid.ID).Return(order.TotalRefunded, nil).Times(1)
+				mockRepo.EXPECT().UpdateRefundedQtyWithTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(len(tc.refund))
+				mockRepo.EXPECT().CreateRefundWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, r *core.Refund) error {
+					created = *r
+					return nil
+				}).Times(1)
+				mockRepo.EXPECT().UpdateOrderRefundWithTx(gomock.Any(), order.ID, order.TotalRefunded+tc.expectedAmount, tc.expectedStatus).Return(nil).Times(1)
+			}
+
+			service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+			refund, err := service.RefundOrder(payment.RefundRequest{OrderID: order.ID, Reason: "Salah pesan", Items: tc.refund}, nil)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, txdb.Commits())
+			assert.Equal(t, tc.expectedAmount, refund.Amount)
+			assert.Equal(t, core.RefundStatusCompleted, created.Status)
+		})
+	}
+}
+
+func TestInitiateCashPayment_Gomock(t *testing.T) {
+	const bill = 47650
+
+	testCases := []struct {
+		name             string
+		store            *core.StoreProfile
+		amountTendered   int
+		expectedDue      int
+		expectedRounding int
+		expectedChange   int
+		expectedError    error
+	}{
+		{
+			name:           "Sukses - Kembalian dihitung dari tagihan tanpa pembulatan",
+			amountTendered: 50000,
+			expectedDue:    bill,
+			expectedChange: 2350,
+		},
+		{
+			name:             "Sukses - Tagihan dibulatkan ke 100 terdekat sebelum kembalian dihitung",
+			store:            &core.StoreProfile{CashRoundingMode: core.CashRoundingNearest, CashRoundingIncrement: 100, CashRoundingScope: core.CashRoundingScopeCash},
+			amountTendered:   50000,
+			expectedDue:      47700,
+			expectedRounding: 50,
+			expectedChange:   2300,
+		},
+		{
+			// Uang pas terhadap nominal setelah pembulatan diterima meski lebih kecil dari tagihan
+			name:             "Sukses - Uang pas sesuai nominal yang dibulatkan ke bawah",
+			store:            &core.StoreProfile{CashRoundingMode: core.CashRoundingDown, CashRoundingIncrement: 500, CashRoundingScope: core.CashRoundingScopeCash},
+			amountTendered:   47500,
+			expectedDue:      47500,
+			expectedRounding: -150,
+		},
+		{
+			name:           "Gagal - Uang diterima kurang dari tagihan",
+			amountTendered: 40000,
+			expectedError:  core.ErrInsufficientCash,
+		},
+		{
+			name:           "Gagal - Uang pas tagihan asli kurang dari nominal yang dibulatkan ke atas",
+			store:          &core.StoreProfile{CashRoundingMode: core.CashRoundingNearest, CashRoundingIncrement: 100, CashRoundingScope: core.CashRoundingScopeCash},
+			amountTendered: bill,
+			expectedError:  core.ErrInsufficientCash,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			mockGateway := mocks.NewMockPaymentGateway(ctrl)
+			txdb := testutil.NewTxDB(t)
+
+			order := core.Order{ID: uuid.New(), OrderStatus: core.OrderStatusReady, PaymentStatus: core.PaymentStatusUnpaid, TotalFinalAmount: bill}
+
+			mockRepo.EXPECT().DB().Return(txdb.DB).AnyTimes()
+			mockRepo.EXPECT().LockOrder(gomock.Any(), order.ID).Return(&order, nil).Times(1)
+			mockRepo.EXPECT().SumPaymentsWithTx(gomock.Any(), order.ID, core.PaymentStatusPaid, core.PaymentStatusUnpaid).Return(0, nil).Times(1)
+			if tc.store != nil {
+				mockRepo.EXPECT().GetStoreProfile().Return(tc.store, nil).Times(1)
+			} else {
+				mockRepo.EXPECT().GetStoreProfile().Return(nil, gorm.ErrRecordNotFound).Times(1)
+			}
+
+			var created core.Payment
+			if tc.expectedError == nil {
+				mockRepo.EXPECT().CreateWithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *gorm.DB, p *core.Payment) error {
+					created = *p
+					return nil
+				}).Times(1)
+				mockRepo.EXPECT().SumPaymentsWithTx(gomock.Any(), order.ID, core.PaymentStatusPaid).Return(bill, nil).Times(1)
+				mockRepo.EXPECT().UpdateOrderPaidWithTx(gomock.Any(), order.ID, bill, core.PaymentStatusPaid).Return(nil).Times(1)
+			}
+
+			service := payment.NewPaymentService(mockRepo, mockGateway, validator.New(), nil)
+
+			resp, err := service.InitiatePayment(payment.InitiatePaymentRequest{OrderID: order.ID, PaymentMethod: core.PaymentMethodCash, AmountTendered: tc.amountTendered})
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Equal(t, 0, txdb.Commits())
+				assert.Equal(t, 1, txdb.Rollbacks())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, txdb.Commits())
+			assert.Equal(t, tc.expectedDue, resp.AmountDue)
+			assert.Equal(t, tc.expectedRounding, resp.RoundingAdjustment)
+			assert.Equal(t, tc.expectedChange, resp.ChangeDue)
+			assert.Equal(t, 0, resp.OutstandingBalance)
+
+			// AmountPaid tetap menutup tagihan persis; selisih pembulatan dicatat terpisah
+			assert.Equal(t, bill, created.AmountPaid)
+			assert.Equal(t, tc.expectedRounding, created.RoundingAdjustment)
+			assert.Equal(t, tc.amountTendered, created.AmountTendered)
+			assert.Equal(t, tc.expectedChange, created.ChangeAmount)
+			assert.Equal(t, core.PaymentStatusPaid, created.PaymentStatus)
+		})
+	}
+}
@@ -3,6 +3,9 @@ package product
 // Sesuaikan dengan nama module di go.mod kamu
 import (
 	"go-fiber-pos/internal/core"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 
@@ -12,10 +15,41 @@ type ProductRepository interface {
 	Create(product *core.Product) error
 	GetAll() ([]core.Product, error)
 	FindByName(name string) (*core.Product, error)
+	FindByID(id uuid.UUID) (*core.Product, error)
+
+	// Modifier group (ukuran, level gula, extra shot) beserta opsinya
+	ListModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error)
+	FindModifierGroup(productID, groupID uuid.UUID) (*core.ModifierGroup, error)
+	CreateModifierGroup(group *core.ModifierGroup) error
+	// ReplaceModifierGroup menyimpan group beserta daftar opsi baru; opsi lama yang tidak ada lagi dihapus.
+	ReplaceModifierGroup(group *core.ModifierGroup) error
+	DeleteModifierGroup(groupID uuid.UUID) error
+
+	// Bundle (paket) beserta slot komponennya
+	FindByIDs(ids []uuid.UUID) ([]core.Product, error)
+	// IsBundleComponent mengecek apakah produk dipakai sebagai komponen bundle lain.
+	IsBundleComponent(productID uuid.UUID) (bool, error)
+	// ReplaceBundleSlots mengganti seluruh slot bundle; slot kosong = produk bukan bundle lagi.
+	ReplaceBundleSlots(productID uuid.UUID, slots []core.BundleSlot) error
+
+	// Jadwal promo (hari, rentang tanggal, jendela jam) yang dievaluasi di zona waktu toko
+	ReplacePromoSchedules(productID uuid.UUID, schedules []core.PromoSchedule) error
+	GetStoreProfile() (*core.StoreProfile, error)
 }
 
 type ProductService interface {
 	// Lihat! Sekarang dia menerima tipe dari package dto
 	CreateProduct(req CreateProductRequest) (*core.Product, error) 
 	GetAllProducts() ([]core.Product, error)
+
+	ListModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error)
+	CreateModifierGroup(productID uuid.UUID, req ModifierGroupRequest) (*core.ModifierGroup, error)
+	UpdateModifierGroup(productID, groupID uuid.UUID, req ModifierGroupRequest) (*core.ModifierGroup, error)
+	DeleteModifierGroup(productID, groupID uuid.UUID) error
+
+	UpdateBundle(productID uuid.UUID, req BundleRequest) (*core.Product, error)
+	UpdatePromoSchedules(productID uuid.UUID, req PromoScheduleRequest) (*core.Product, error)
+
+	// GetMenu mengambil semua produk beserta waktu sekarang di zona waktu toko untuk evaluasi promo.
+	GetMenu() ([]core.Product, time.Time, error)
 }
\ No newline at end of file
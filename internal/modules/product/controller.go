@@ -1,7 +1,13 @@
 package product
 
 import (
+	"errors"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type ProductController struct {
@@ -42,4 +48,146 @@ func (ctrl *ProductController) GetAll(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"data": res,
 	})
-}
\ No newline at end of file
+}
+
+func (ctrl *ProductController) ListModifierGroups(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID produk tidak valid"})
+	}
+
+	groups, err := ctrl.service.ListModifierGroups(productID)
+	if err != nil {
+		return productSetupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"data": ToModifierGroupResponseList(groups),
+	})
+}
+
+func (ctrl *ProductController) CreateModifierGroup(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID produk tidak valid"})
+	}
+
+	var req ModifierGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	group, err := ctrl.service.CreateModifierGroup(productID, req)
+	if err != nil {
+		return productSetupError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Modifier group berhasil dibuat",
+		"data":    ToModifierGroupResponseList([]core.ModifierGroup{*group})[0],
+	})
+}
+
+func (ctrl *ProductController) UpdateModifierGroup(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID produk tidak valid"})
+	}
+	groupID, err := uuid.Parse(c.Params("group_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID modifier group tidak valid"})
+	}
+
+	var req ModifierGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	group, err := ctrl.service.UpdateModifierGroup(productID, groupID, req)
+	if err != nil {
+		return productSetupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Modifier group berhasil diperbarui",
+		"data":    ToModifierGroupResponseList([]core.ModifierGroup{*group})[0],
+	})
+}
+
+func (ctrl *ProductController) DeleteModifierGroup(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID produk tidak valid"})
+	}
+	groupID, err := uuid.Parse(c.Params("group_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID modifier group tidak valid"})
+	}
+
+	if err := ctrl.service.DeleteModifierGroup(productID, groupID); err != nil {
+		return productSetupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Modifier group berhasil dihapus",
+	})
+}
+
+func (ctrl *ProductController) UpdateBundle(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID produk tidak valid"})
+	}
+
+	var req BundleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	product, err := ctrl.service.UpdateBundle(productID, req)
+	if err != nil {
+		return productSetupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Isi bundle berhasil diperbarui",
+		"data":    ToProductResponse(product),
+	})
+}
+
+func (ctrl *ProductController) UpdatePromoSchedules(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID produk tidak valid"})
+	}
+
+	var req PromoScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	product, err := ctrl.service.UpdatePromoSchedules(productID, req)
+	if err != nil {
+		return productSetupError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Jadwal promo berhasil diperbarui",
+		"data":    ToProductResponse(product),
+	})
+}
+
+// productSetupError memetakan error service modifier group, bundle & jadwal promo ke status HTTP.
+func productSetupError(c *fiber.Ctx, err error) error {
+	var valErr validator.ValidationErrors
+	if errors.As(err, &valErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+	}
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, core.ErrInvalidModifier), errors.Is(err, core.ErrInvalidBundle), errors.Is(err, core.ErrInvalidPromo):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
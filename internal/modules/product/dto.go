@@ -17,6 +17,60 @@ type CreateProductRequest struct {
 	PromoEndTime   string `json:"promo_end_time"`
 }
 
+// ModifierOptionInput adalah satu opsi dalam ModifierGroupRequest.
+type ModifierOptionInput struct {
+	ID          *uuid.UUID `json:"id"` // Isi saat update untuk mempertahankan opsi lama; kosong = opsi baru
+	Name        string     `json:"name" validate:"required,max=100"`
+	PriceDelta  int        `json:"price_delta"` // Selisih harga terhadap harga produk, boleh negatif
+	TrackStock  bool       `json:"track_stock"`
+	Stock       int        `json:"stock" validate:"min=0"`
+	IsAvailable *bool      `json:"is_available"` // Default true
+	SortOrder   int        `json:"sort_order"`
+}
+
+// ModifierGroupRequest adalah DTO untuk membuat/mengganti modifier group sebuah produk.
+// PUT mengganti seluruh daftar opsi: opsi lama yang tidak dikirim ulang akan dihapus.
+type ModifierGroupRequest struct {
+	Name       string                `json:"name" validate:"required,max=100"`
+	IsRequired bool                  `json:"is_required"`
+	MinSelect  int                   `json:"min_select" validate:"min=0"`
+	MaxSelect  int                   `json:"max_select" validate:"min=0"` // 0 = tanpa batas
+	SortOrder  int                   `json:"sort_order"`
+	Options    []ModifierOptionInput `json:"options" validate:"required,min=1,max=30,dive"`
+}
+
+// BundleSlotInput adalah satu slot dalam BundleRequest. Satu produk = komponen tetap,
+// beberapa produk = pelanggan memilih salah satunya saat checkout.
+type BundleSlotInput struct {
+	Name       string      `json:"name" validate:"required,max=100"`
+	Qty        int         `json:"qty" validate:"required,min=1"`
+	SortOrder  int         `json:"sort_order"`
+	ProductIDs []uuid.UUID `json:"product_ids" validate:"required,min=1,max=20,dive,required"`
+}
+
+// BundleRequest adalah DTO untuk mengganti isi bundle sebuah produk (PUT /admin/products/:id/bundle).
+// Harga bundle tetap memakai NormalPrice/PromoPrice produk itu sendiri. Slots kosong = bukan bundle lagi.
+type BundleRequest struct {
+	Slots []BundleSlotInput `json:"slots" validate:"max=10,dive"`
+}
+
+// PromoScheduleInput adalah satu jendela berlakunya harga promo. Kolom kosong = tanpa batas.
+// StartTime > EndTime berarti melewati tengah malam, contoh 22:00–02:00.
+type PromoScheduleInput struct {
+	Weekdays  []int  `json:"weekdays" validate:"unique,dive,min=0,max=6"` // 0 = Minggu; kosong = setiap hari
+	StartTime string `json:"start_time" validate:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required_with=StartTime,omitempty,datetime=15:04"`
+	StartDate string `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// PromoScheduleRequest mengganti seluruh jadwal promo produk (PUT /admin/products/:id/promo-schedules).
+// Harga promo tetap PromoPrice produk dan hanya berlaku jika IsPromoActive. Schedules kosong = kembali
+// ke PromoStartTime/PromoEndTime.
+type PromoScheduleRequest struct {
+	Schedules []PromoScheduleInput `json:"schedules" validate:"max=20,dive"`
+}
+
 // Response DTO (Dari Server ke Frontend)
 type ProductResponse struct {
 	ID             uuid.UUID `json:"id"`
@@ -31,5 +85,54 @@ type ProductResponse struct {
 	PromoPrice     int       `json:"promo_price"`
 	PromoStartTime string    `json:"promo_start_time"`
 	PromoEndTime   string    `json:"promo_end_time"`
+
+	PromoSchedules []PromoScheduleResponse `json:"promo_schedules"`
+	CurrentPrice   int                     `json:"current_price,omitempty"` // Khusus menu publik: harga yang berlaku saat ini
+	IsPromoNow     bool                    `json:"is_promo_now,omitempty"`
+
+	ModifierGroups []ModifierGroupResponse `json:"modifier_groups"`
+	IsBundle       bool                    `json:"is_bundle"`
+	BundleSlots    []BundleSlotResponse    `json:"bundle_slots"`
+}
+
+// PromoScheduleResponse adalah satu jendela jadwal promo produk.
+type PromoScheduleResponse struct {
+	Weekdays  []int  `json:"weekdays"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// ModifierGroupResponse adalah modifier group yang ditampilkan di menu.
+type ModifierGroupResponse struct {
+	ID         uuid.UUID                `json:"id"`
+	Name       string                   `json:"name"`
+	IsRequired bool                     `json:"is_required"`
+	MinSelect  int                      `json:"min_select"`
+	MaxSelect  int                      `json:"max_select"`
+	Options    []ModifierOptionResponse `json:"options"`
 }
 
+// ModifierOptionResponse tidak menampilkan jumlah stok; opsi yang stoknya habis ditandai tidak tersedia.
+type ModifierOptionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	PriceDelta  int       `json:"price_delta"`
+	IsAvailable bool      `json:"is_available"`
+}
+
+// BundleSlotResponse adalah satu slot bundle yang ditampilkan di menu.
+type BundleSlotResponse struct {
+	ID      uuid.UUID                  `json:"id"`
+	Name    string                     `json:"name"`
+	Qty     int                        `json:"qty"`
+	Options []BundleSlotOptionResponse `json:"options"` // Lebih dari satu = pelanggan wajib memilih
+}
+
+// BundleSlotOptionResponse adalah produk yang boleh mengisi slot bundle.
+type BundleSlotOptionResponse struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	Name        string    `json:"name"`
+	IsAvailable bool      `json:"is_available"`
+}
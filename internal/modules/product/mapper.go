@@ -1,6 +1,9 @@
 package product
 
-import model "go-fiber-pos/internal/core"
+import (
+	model "go-fiber-pos/internal/core"
+	"time"
+)
 
 // ToProductResponse: Domain GORM -> Response DTO
 func ToProductResponse(domain *model.Product) ProductResponse {
@@ -17,6 +20,10 @@ func ToProductResponse(domain *model.Product) ProductResponse {
 		PromoPrice:     domain.PromoPrice,
 		PromoStartTime: domain.PromoStartTime,
 		PromoEndTime:   domain.PromoEndTime,
+		PromoSchedules: ToPromoScheduleResponseList(domain.PromoSchedules),
+		ModifierGroups: ToModifierGroupResponseList(domain.ModifierGroups),
+		IsBundle:       domain.IsBundle,
+		BundleSlots:    ToBundleSlotResponseList(domain.BundleSlots),
 	}
 }
 
@@ -28,4 +35,81 @@ func ToProductResponseList(domains []model.Product) []ProductResponse {
 		responses = append(responses, ToProductResponse(&domain))
 	}
 	return responses
-}
\ No newline at end of file
+}
+
+// ToMenuResponseList: Array Domain -> menu publik dengan harga yang berlaku pada waktu at (zona waktu toko)
+func ToMenuResponseList(domains []model.Product, at time.Time) []ProductResponse {
+	responses := ToProductResponseList(domains)
+	for i := range domains {
+		responses[i].CurrentPrice = domains[i].PriceAt(at)
+		responses[i].IsPromoNow = domains[i].PromoActiveAt(at)
+	}
+	return responses
+}
+
+// ToPromoScheduleResponseList: Jadwal promo -> response (hari sebagai angka, 0 = Minggu)
+func ToPromoScheduleResponseList(schedules []model.PromoSchedule) []PromoScheduleResponse {
+	responses := []PromoScheduleResponse{}
+	for _, schedule := range schedules {
+		weekdays := []int{}
+		for _, wd := range schedule.WeekdayList() {
+			weekdays = append(weekdays, int(wd))
+		}
+		responses = append(responses, PromoScheduleResponse{
+			Weekdays:  weekdays,
+			StartTime: schedule.StartTime,
+			EndTime:   schedule.EndTime,
+			StartDate: schedule.StartDate,
+			EndDate:   schedule.EndDate,
+		})
+	}
+	return responses
+}
+
+// ToModifierGroupResponseList: Modifier group produk -> tampilan menu
+func ToModifierGroupResponseList(groups []model.ModifierGroup) []ModifierGroupResponse {
+	responses := []ModifierGroupResponse{}
+	for _, group := range groups {
+		options := []ModifierOptionResponse{}
+		for _, option := range group.Options {
+			options = append(options, ModifierOptionResponse{
+				ID:          option.ID,
+				Name:        option.Name,
+				PriceDelta:  option.PriceDelta,
+				IsAvailable: option.IsAvailable && (!option.TrackStock || option.Stock > 0),
+			})
+		}
+		responses = append(responses, ModifierGroupResponse{
+			ID:         group.ID,
+			Name:       group.Name,
+			IsRequired: group.IsRequired,
+			MinSelect:  group.MinSelections(),
+			MaxSelect:  group.MaxSelect,
+			Options:    options,
+		})
+	}
+	return responses
+}
+
+// ToBundleSlotResponseList: Slot bundle -> tampilan menu (komponen yang stoknya habis ditandai tidak tersedia)
+func ToBundleSlotResponseList(slots []model.BundleSlot) []BundleSlotResponse {
+	responses := []BundleSlotResponse{}
+	for _, slot := range slots {
+		options := []BundleSlotOptionResponse{}
+		for _, option := range slot.Options {
+			response := BundleSlotOptionResponse{ProductID: option.ProductID}
+			if option.Product != nil {
+				response.Name = option.Product.Name
+				response.IsAvailable = option.Product.IsAvailable && option.Product.Stock >= slot.Qty
+			}
+			options = append(options, response)
+		}
+		responses = append(responses, BundleSlotResponse{
+			ID:      slot.ID,
+			Name:    slot.Name,
+			Qty:     slot.Qty,
+			Options: options,
+		})
+	}
+	return responses
+}
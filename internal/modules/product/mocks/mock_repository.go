@@ -13,7 +13,9 @@ import (
 	core "go-fiber-pos/internal/core"
 	product "go-fiber-pos/internal/modules/product"
 	reflect "reflect"
+	time "time"
 
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +57,64 @@ func (mr *MockProductRepositoryMockRecorder) Create(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductRepository)(nil).Create), arg0)
 }
 
+// CreateModifierGroup mocks base method.
+func (m *MockProductRepository) CreateModifierGroup(group *core.ModifierGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModifierGroup", group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateModifierGroup indicates an expected call of CreateModifierGroup.
+func (mr *MockProductRepositoryMockRecorder) CreateModifierGroup(group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModifierGroup", reflect.TypeOf((*MockProductRepository)(nil).CreateModifierGroup), group)
+}
+
+// DeleteModifierGroup mocks base method.
+func (m *MockProductRepository) DeleteModifierGroup(groupID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModifierGroup", groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModifierGroup indicates an expected call of DeleteModifierGroup.
+func (mr *MockProductRepositoryMockRecorder) DeleteModifierGroup(groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModifierGroup", reflect.TypeOf((*MockProductRepository)(nil).DeleteModifierGroup), groupID)
+}
+
+// FindByID mocks base method.
+func (m *MockProductRepository) FindByID(id uuid.UUID) (*core.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", id)
+	ret0, _ := ret[0].(*core.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockProductRepositoryMockRecorder) FindByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockProductRepository)(nil).FindByID), id)
+}
+
+// FindByIDs mocks base method.
+func (m *MockProductRepository) FindByIDs(ids []uuid.UUID) ([]core.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ids)
+	ret0, _ := ret[0].([]core.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockProductRepositoryMockRecorder) FindByIDs(ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockProductRepository)(nil).FindByIDs), ids)
+}
+
 // FindByName mocks base method.
 func (m *MockProductRepository) FindByName(name string) (*core.Product, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +130,21 @@ func (mr *MockProductRepositoryMockRecorder) FindByName(name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByName", reflect.TypeOf((*MockProductRepository)(nil).FindByName), name)
 }
 
+// FindModifierGroup mocks base method.
+func (m *MockProductRepository) FindModifierGroup(productID, groupID uuid.UUID) (*core.ModifierGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindModifierGroup", productID, groupID)
+	ret0, _ := ret[0].(*core.ModifierGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindModifierGroup indicates an expected call of FindModifierGroup.
+func (mr *MockProductRepositoryMockRecorder) FindModifierGroup(productID, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindModifierGroup", reflect.TypeOf((*MockProductRepository)(nil).FindModifierGroup), productID, groupID)
+}
+
 // GetAll mocks base method.
 func (m *MockProductRepository) GetAll() ([]core.Product, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +160,93 @@ func (mr *MockProductRepositoryMockRecorder) GetAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductRepository)(nil).GetAll))
 }
 
+// GetStoreProfile mocks base method.
+func (m *MockProductRepository) GetStoreProfile() (*core.StoreProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStoreProfile")
+	ret0, _ := ret[0].(*core.StoreProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStoreProfile indicates an expected call of GetStoreProfile.
+func (mr *MockProductRepositoryMockRecorder) GetStoreProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStoreProfile", reflect.TypeOf((*MockProductRepository)(nil).GetStoreProfile))
+}
+
+// IsBundleComponent mocks base method.
+func (m *MockProductRepository) IsBundleComponent(productID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBundleComponent", productID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBundleComponent indicates an expected call of IsBundleComponent.
+func (mr *MockProductRepositoryMockRecorder) IsBundleComponent(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBundleComponent", reflect.TypeOf((*MockProductRepository)(nil).IsBundleComponent), productID)
+}
+
+// ListModifierGroups mocks base method.
+func (m *MockProductRepository) ListModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModifierGroups", productID)
+	ret0, _ := ret[0].([]core.ModifierGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModifierGroups indicates an expected call of ListModifierGroups.
+func (mr *MockProductRepositoryMockRecorder) ListModifierGroups(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModifierGroups", reflect.TypeOf((*MockProductRepository)(nil).ListModifierGroups), productID)
+}
+
+// ReplaceBundleSlots mocks base method.
+func (m *MockProductRepository) ReplaceBundleSlots(productID uuid.UUID, slots []core.BundleSlot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceBundleSlots", productID, slots)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceBundleSlots indicates an expected call of ReplaceBundleSlots.
+func (mr *MockProductRepositoryMockRecorder) ReplaceBundleSlots(productID, slots any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceBundleSlots", reflect.TypeOf((*MockProductRepository)(nil).ReplaceBundleSlots), productID, slots)
+}
+
+// ReplaceModifierGroup mocks base method.
+func (m *MockProductRepository) ReplaceModifierGroup(group *core.ModifierGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceModifierGroup", group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceModifierGroup indicates an expected call of ReplaceModifierGroup.
+func (mr *MockProductRepositoryMockRecorder) ReplaceModifierGroup(group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceModifierGroup", reflect.TypeOf((*MockProductRepository)(nil).ReplaceModifierGroup), group)
+}
+
+// ReplacePromoSchedules mocks base method.
+func (m *MockProductRepository) ReplacePromoSchedules(productID uuid.UUID, schedules []core.PromoSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePromoSchedules", productID, schedules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplacePromoSchedules indicates an expected call of ReplacePromoSchedules.
+func (mr *MockProductRepositoryMockRecorder) ReplacePromoSchedules(productID, schedules any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePromoSchedules", reflect.TypeOf((*MockProductRepository)(nil).ReplacePromoSchedules), productID, schedules)
+}
+
 // MockProductService is a mock of ProductService interface.
 type MockProductService struct {
 	ctrl     *gomock.Controller
@@ -109,6 +271,21 @@ func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
 	return m.recorder
 }
 
+// CreateModifierGroup mocks base method.
+func (m *MockProductService) CreateModifierGroup(productID uuid.UUID, req product.ModifierGroupRequest) (*core.ModifierGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModifierGroup", productID, req)
+	ret0, _ := ret[0].(*core.ModifierGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateModifierGroup indicates an expected call of CreateModifierGroup.
+func (mr *MockProductServiceMockRecorder) CreateModifierGroup(productID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModifierGroup", reflect.TypeOf((*MockProductService)(nil).CreateModifierGroup), productID, req)
+}
+
 // CreateProduct mocks base method.
 func (m *MockProductService) CreateProduct(req product.CreateProductRequest) (*core.Product, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +301,20 @@ func (mr *MockProductServiceMockRecorder) CreateProduct(req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductService)(nil).CreateProduct), req)
 }
 
+// DeleteModifierGroup mocks base method.
+func (m *MockProductService) DeleteModifierGroup(productID, groupID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModifierGroup", productID, groupID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModifierGroup indicates an expected call of DeleteModifierGroup.
+func (mr *MockProductServiceMockRecorder) DeleteModifierGroup(productID, groupID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModifierGroup", reflect.TypeOf((*MockProductService)(nil).DeleteModifierGroup), productID, groupID)
+}
+
 // GetAllProducts mocks base method.
 func (m *MockProductService) GetAllProducts() ([]core.Product, error) {
 	m.ctrl.T.Helper()
@@ -138,3 +329,79 @@ func (mr *MockProductServiceMockRecorder) GetAllProducts() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllProducts", reflect.TypeOf((*MockProductService)(nil).GetAllProducts))
 }
+
+// GetMenu mocks base method.
+func (m *MockProductService) GetMenu() ([]core.Product, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMenu")
+	ret0, _ := ret[0].([]core.Product)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetMenu indicates an expected call of GetMenu.
+func (mr *MockProductServiceMockRecorder) GetMenu() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMenu", reflect.TypeOf((*MockProductService)(nil).GetMenu))
+}
+
+// ListModifierGroups mocks base method.
+func (m *MockProductService) ListModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModifierGroups", productID)
+	ret0, _ := ret[0].([]core.ModifierGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModifierGroups indicates an expected call of ListModifierGroups.
+func (mr *MockProductServiceMockRecorder) ListModifierGroups(productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModifierGroups", reflect.TypeOf((*MockProductService)(nil).ListModifierGroups), productID)
+}
+
+// UpdateBundle mocks base method.
+func (m *MockProductService) UpdateBundle(productID uuid.UUID, req product.BundleRequest) (*core.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBundle", productID, req)
+	ret0, _ := ret[0].(*core.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBundle indicates an expected call of UpdateBundle.
+func (mr *MockProductServiceMockRecorder) UpdateBundle(productID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBundle", reflect.TypeOf((*MockProductService)(nil).UpdateBundle), productID, req)
+}
+
+// UpdateModifierGroup mocks base method.
+func (m *MockProductService) UpdateModifierGroup(productID, groupID uuid.UUID, req product.ModifierGroupRequest) (*core.ModifierGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModifierGroup", productID, groupID, req)
+	ret0, _ := ret[0].(*core.ModifierGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateModifierGroup indicates an expected call of UpdateModifierGroup.
+func (mr *MockProductServiceMockRecorder) UpdateModifierGroup(productID, groupID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModifierGroup", reflect.TypeOf((*MockProductService)(nil).UpdateModifierGroup), productID, groupID, req)
+}
+
+// UpdatePromoSchedules mocks base method.
+func (m *MockProductService) UpdatePromoSchedules(productID uuid.UUID, req product.PromoScheduleRequest) (*core.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromoSchedules", productID, req)
+	ret0, _ := ret[0].(*core.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePromoSchedules indicates an expected call of UpdatePromoSchedules.
+func (mr *MockProductServiceMockRecorder) UpdatePromoSchedules(productID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromoSchedules", reflect.TypeOf((*MockProductService)(nil).UpdatePromoSchedules), productID, req)
+}
@@ -17,13 +17,13 @@ func NewPublicProductController(service ProductService) *PublicProductController
 
 // 2. Receiver diperbaiki menjadi *PublicProductController
 func (ctrl *PublicProductController) GetAllMenu(c *fiber.Ctx) error {
-	products, err := ctrl.service.GetAllProducts()
+	products, now, err := ctrl.service.GetMenu()
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	// 3. Panggil fungsi mapper yang baru
-	res := ToProductResponseList(products)
+	// 3. Panggil fungsi mapper yang baru (harga promo dievaluasi di zona waktu toko)
+	res := ToMenuResponseList(products, now)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"data": res,
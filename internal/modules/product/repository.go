@@ -3,6 +3,7 @@ package product
 import (
 	model "go-fiber-pos/internal/core"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -23,7 +24,22 @@ func (r *productRepository) Create(product *model.Product) error {
 
 func (r* productRepository) GetAll() ([]model.Product, error){
 	var products []model.Product
-	err := r.db.Find(&products).Error
+	err := r.db.
+		Preload("ModifierGroups", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Preload("ModifierGroups.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Preload("BundleSlots", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Preload("BundleSlots.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Preload("BundleSlots.Options.Product").
+		Preload("PromoSchedules").
+		Find(&products).Error
 	return products, err
 }
 
@@ -42,5 +58,130 @@ func (r *productRepository) FindByName(name string) (*model.Product, error) {
     return &product, nil
 }
 
+func (r *productRepository) FindByID(id uuid.UUID) (*model.Product, error) {
+	var product model.Product
+	err := r.db.First(&product, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) ListModifierGroups(productID uuid.UUID) ([]model.ModifierGroup, error) {
+	var groups []model.ModifierGroup
+	err := r.db.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Where("product_id = ?", productID).
+		Order("sort_order ASC").
+		Find(&groups).Error
+	return groups, err
+}
+
+func (r *productRepository) FindModifierGroup(productID, groupID uuid.UUID) (*model.ModifierGroup, error) {
+	var group model.ModifierGroup
+	err := r.db.
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sort_order ASC")
+		}).
+		Where("id = ? AND product_id = ?", groupID, productID).
+		First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// CreateModifierGroup menyimpan group beserta semua opsinya (gorm ikut membuat Options).
+func (r *productRepository) CreateModifierGroup(group *model.ModifierGroup) error {
+	return r.db.Create(group).Error
+}
+
+func (r *productRepository) ReplaceModifierGroup(group *model.ModifierGroup) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Options").Save(group).Error; err != nil {
+			return err
+		}
+
+		keep := make([]uuid.UUID, 0, len(group.Options))
+		for _, option := range group.Options {
+			keep = append(keep, option.ID)
+		}
+		if err := tx.Where("group_id = ? AND id NOT IN ?", group.ID, keep).
+			Delete(&model.ModifierOption{}).Error; err != nil {
+			return err
+		}
 
+		for i := range group.Options {
+			if err := tx.Save(&group.Options[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteModifierGroup menghapus group beserta opsinya. Snapshot di OrderItemModifier tidak terpengaruh.
+func (r *productRepository) DeleteModifierGroup(groupID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&model.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ModifierGroup{}, "id = ?", groupID).Error
+	})
+}
 
+func (r *productRepository) FindByIDs(ids []uuid.UUID) ([]model.Product, error) {
+	var products []model.Product
+	err := r.db.Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) IsBundleComponent(productID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.BundleSlotOption{}).Where("product_id = ?", productID).Count(&count).Error
+	return count > 0, err
+}
+
+// ReplaceBundleSlots menghapus slot lama lalu membuat slot baru beserta opsinya, dan menandai IsBundle.
+// Snapshot di OrderItemComponent tidak terpengaruh.
+func (r *productRepository) ReplaceBundleSlots(productID uuid.UUID, slots []model.BundleSlot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		oldSlots := tx.Model(&model.BundleSlot{}).Select("id").Where("bundle_id = ?", productID)
+		if err := tx.Where("slot_id IN (?)", oldSlots).Delete(&model.BundleSlotOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("bundle_id = ?", productID).Delete(&model.BundleSlot{}).Error; err != nil {
+			return err
+		}
+		if len(slots) > 0 {
+			if err := tx.Create(&slots).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.Product{}).Where("id = ?", productID).Update("is_bundle", len(slots) > 0).Error
+	})
+}
+
+// ReplacePromoSchedules mengganti seluruh jadwal promo produk; daftar kosong = kembali ke
+// PromoStartTime/PromoEndTime produk.
+func (r *productRepository) ReplacePromoSchedules(productID uuid.UUID, schedules []model.PromoSchedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&model.PromoSchedule{}).Error; err != nil {
+			return err
+		}
+		if len(schedules) == 0 {
+			return nil
+		}
+		return tx.Create(&schedules).Error
+	})
+}
+
+func (r *productRepository) GetStoreProfile() (*model.StoreProfile, error) {
+	var profile model.StoreProfile
+	if err := r.db.First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -17,6 +17,12 @@ func SetupRoutes(adminGroup fiber.Router, publicGroup fiber.Router, db *gorm.DB,
 	
 	
 	adminGroup.Post("/products", adminCtrl.Create)
+	adminGroup.Get("/products/:id/modifier-groups", adminCtrl.ListModifierGroups)
+	adminGroup.Post("/products/:id/modifier-groups", adminCtrl.CreateModifierGroup)
+	adminGroup.Put("/products/:id/modifier-groups/:group_id", adminCtrl.UpdateModifierGroup)
+	adminGroup.Delete("/products/:id/modifier-groups/:group_id", adminCtrl.DeleteModifierGroup)
+	adminGroup.Put("/products/:id/bundle", adminCtrl.UpdateBundle)
+	adminGroup.Put("/products/:id/promo-schedules", adminCtrl.UpdatePromoSchedules)
 
 	
 	publicGroup.Get("/menu/products", publicCtrl.GetAllMenu)
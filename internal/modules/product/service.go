@@ -2,10 +2,16 @@ package product
 
 import (
 	"errors"
+	"fmt"
 	"go-fiber-pos/internal/core"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type productService struct {
@@ -67,4 +73,274 @@ func (s *productService) CreateProduct(req CreateProductRequest) (*core.Product,
 func (s *productService) GetAllProducts() ([]core.Product, error) {
 	// FIX: Menggunakan s.repo langsung
 	return s.repo.GetAll()
-}
\ No newline at end of file
+}
+
+func (s *productService) GetMenu() ([]core.Product, time.Time, error) {
+	products, err := s.repo.GetAll()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	now := time.Now()
+	if profile, err := s.repo.GetStoreProfile(); err == nil {
+		now = now.In(profile.Location())
+	}
+	return products, now, nil
+}
+
+func (s *productService) ListModifierGroups(productID uuid.UUID) ([]core.ModifierGroup, error) {
+	if err := s.ensureProductExists(productID); err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.ListModifierGroups(productID)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return groups, nil
+}
+
+func (s *productService) CreateModifierGroup(productID uuid.UUID, req ModifierGroupRequest) (*core.ModifierGroup, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := s.ensureProductExists(productID); err != nil {
+		return nil, err
+	}
+
+	group := &core.ModifierGroup{ID: uuid.New(), ProductID: productID}
+	if err := applyModifierGroupRequest(group, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateModifierGroup(group); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return group, nil
+}
+
+// UpdateModifierGroup mengganti isi group. Opsi yang dikirim dengan ID lama tetap memakai ID tersebut
+// sehingga riwayat order (OrderItemModifier.ModifierOptionID) dan restock tetap menunjuk opsi yang sama.
+func (s *productService) UpdateModifierGroup(productID, groupID uuid.UUID, req ModifierGroupRequest) (*core.ModifierGroup, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	group, err := s.repo.FindModifierGroup(productID, groupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if err := applyModifierGroupRequest(group, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceModifierGroup(group); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return group, nil
+}
+
+func (s *productService) DeleteModifierGroup(productID, groupID uuid.UUID) error {
+	if _, err := s.repo.FindModifierGroup(productID, groupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return core.ErrNotFound
+		}
+		return core.ErrInternalServer
+	}
+	if err := s.repo.DeleteModifierGroup(groupID); err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+func (s *productService) ensureProductExists(productID uuid.UUID) error {
+	if _, err := s.repo.FindByID(productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return core.ErrNotFound
+		}
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// applyModifierGroupRequest memetakan request ke group (opsi diganti seluruhnya) dan memastikan
+// aturan min/max pilihan masih mungkin dipenuhi pelanggan.
+func applyModifierGroupRequest(group *core.ModifierGroup, req ModifierGroupRequest) error {
+	existing := make(map[uuid.UUID]core.ModifierOption, len(group.Options))
+	for _, option := range group.Options {
+		existing[option.ID] = option
+	}
+
+	group.Name = req.Name
+	group.IsRequired = req.IsRequired
+	group.MinSelect = req.MinSelect
+	group.MaxSelect = req.MaxSelect
+	group.SortOrder = req.SortOrder
+
+	if group.MaxSelect > 0 && group.MinSelections() > group.MaxSelect {
+		return fmt.Errorf("%w: min_select (%d) melebihi max_select (%d)", core.ErrInvalidModifier, group.MinSelections(), group.MaxSelect)
+	}
+	if group.MinSelections() > len(req.Options) {
+		return fmt.Errorf("%w: min_select (%d) melebihi jumlah opsi", core.ErrInvalidModifier, group.MinSelections())
+	}
+
+	options := make([]core.ModifierOption, 0, len(req.Options))
+	seen := make(map[uuid.UUID]bool, len(req.Options))
+	for _, in := range req.Options {
+		id := uuid.New()
+		var createdAt time.Time
+		if in.ID != nil {
+			old, ok := existing[*in.ID]
+			if !ok || seen[*in.ID] {
+				return fmt.Errorf("%w: opsi %s bukan milik group ini", core.ErrInvalidModifier, *in.ID)
+			}
+			id, createdAt = old.ID, old.CreatedAt
+			seen[id] = true
+		}
+		isAvailable := true
+		if in.IsAvailable != nil {
+			isAvailable = *in.IsAvailable
+		}
+		options = append(options, core.ModifierOption{
+			ID:          id,
+			GroupID:     group.ID,
+			Name:        in.Name,
+			PriceDelta:  in.PriceDelta,
+			TrackStock:  in.TrackStock,
+			Stock:       in.Stock,
+			IsAvailable: isAvailable,
+			SortOrder:   in.SortOrder,
+			CreatedAt:   createdAt,
+		})
+	}
+	group.Options = options
+	return nil
+}
+
+// UpdateBundle mengganti isi bundle sebuah produk. Komponen harus produk biasa yang sudah ada:
+// bundle di dalam bundle ditolak agar stok selalu dipotong dari produk nyata.
+func (s *productService) UpdateBundle(productID uuid.UUID, req BundleRequest) (*core.Product, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if len(req.Slots) > 0 {
+		used, err := s.repo.IsBundleComponent(productID)
+		if err != nil {
+			return nil, core.ErrInternalServer
+		}
+		if used {
+			return nil, fmt.Errorf("%w: %s dipakai sebagai komponen bundle lain", core.ErrInvalidBundle, product.Name)
+		}
+	}
+
+	var ids []uuid.UUID
+	for _, slot := range req.Slots {
+		ids = append(ids, slot.ProductIDs...)
+	}
+	components := make(map[uuid.UUID]*core.Product, len(ids))
+	if len(ids) > 0 {
+		found, err := s.repo.FindByIDs(ids)
+		if err != nil {
+			return nil, core.ErrInternalServer
+		}
+		for i := range found {
+			components[found[i].ID] = &found[i]
+		}
+	}
+
+	slots := make([]core.BundleSlot, 0, len(req.Slots))
+	for _, in := range req.Slots {
+		slot := core.BundleSlot{
+			ID:        uuid.New(),
+			BundleID:  productID,
+			Name:      in.Name,
+			Qty:       in.Qty,
+			SortOrder: in.SortOrder,
+		}
+		seen := make(map[uuid.UUID]bool, len(in.ProductIDs))
+		for i, id := range in.ProductIDs {
+			component, ok := components[id]
+			switch {
+			case !ok:
+				return nil, fmt.Errorf("%w: produk %s tidak ditemukan", core.ErrInvalidBundle, id)
+			case id == productID || component.IsBundle:
+				return nil, fmt.Errorf("%w: %s tidak bisa menjadi komponen bundle", core.ErrInvalidBundle, component.Name)
+			case seen[id]:
+				return nil, fmt.Errorf("%w: %s muncul lebih dari sekali di slot %s", core.ErrInvalidBundle, component.Name, in.Name)
+			}
+			seen[id] = true
+			slot.Options = append(slot.Options, core.BundleSlotOption{
+				ID:        uuid.New(),
+				SlotID:    slot.ID,
+				ProductID: id,
+				SortOrder: i,
+			})
+		}
+		slots = append(slots, slot)
+	}
+
+	if err := s.repo.ReplaceBundleSlots(productID, slots); err != nil {
+		return nil, core.ErrInternalServer
+	}
+
+	// Produk komponen hanya dipasang untuk response, setelah disimpan
+	for i := range slots {
+		for j := range slots[i].Options {
+			slots[i].Options[j].Product = components[slots[i].Options[j].ProductID]
+		}
+	}
+	product.IsBundle = len(slots) > 0
+	product.BundleSlots = slots
+	return product, nil
+}
+
+func (s *productService) UpdatePromoSchedules(productID uuid.UUID, req PromoScheduleRequest) (*core.Product, error) {
+	if err := s.v.Struct(req); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+
+	schedules := make([]core.PromoSchedule, 0, len(req.Schedules))
+	for _, in := range req.Schedules {
+		if in.StartTime != "" && in.StartTime == in.EndTime {
+			return nil, fmt.Errorf("%w: jam mulai dan jam selesai tidak boleh sama", core.ErrInvalidPromo)
+		}
+		if in.StartDate != "" && in.EndDate != "" && in.EndDate < in.StartDate {
+			return nil, fmt.Errorf("%w: tanggal selesai %s sebelum tanggal mulai %s", core.ErrInvalidPromo, in.EndDate, in.StartDate)
+		}
+		sort.Ints(in.Weekdays)
+		weekdays := make([]string, 0, len(in.Weekdays))
+		for _, wd := range in.Weekdays {
+			weekdays = append(weekdays, strconv.Itoa(wd))
+		}
+		schedules = append(schedules, core.PromoSchedule{
+			ID:        uuid.New(),
+			ProductID: productID,
+			Weekdays:  strings.Join(weekdays, ","),
+			StartTime: in.StartTime,
+			EndTime:   in.EndTime,
+			StartDate: in.StartDate,
+			EndDate:   in.EndDate,
+		})
+	}
+
+	if err := s.repo.ReplacePromoSchedules(productID, schedules); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	product.PromoSchedules = schedules
+	return product, nil
+}
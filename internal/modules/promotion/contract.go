@@ -0,0 +1,27 @@
+package promotion
+
+import (
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+)
+
+// PromotionRepository mendefinisikan kontrak akses data untuk Promotion.
+type PromotionRepository interface {
+	// GetAll mengambil semua promo (aktif maupun tidak) beserta tier-nya, urut prioritas evaluasi.
+	GetAll() ([]core.Promotion, error)
+	FindByID(id uuid.UUID) (*core.Promotion, error)
+	// Save meng-insert atau meng-update promo dan mengganti seluruh tier-nya.
+	Save(promotion *core.Promotion) error
+	Delete(id uuid.UUID) error
+	ProductExists(id uuid.UUID) (bool, error)
+	CategoryExists(id uuid.UUID) (bool, error)
+}
+
+// PromotionService mendefinisikan kontrak business logic untuk Promotion.
+type PromotionService interface {
+	GetAllPromotions() ([]core.Promotion, error)
+	CreatePromotion(req PromotionRequest) (*core.Promotion, error)
+	UpdatePromotion(id uuid.UUID, req PromotionRequest) (*core.Promotion, error)
+	DeletePromotion(id uuid.UUID) error
+}
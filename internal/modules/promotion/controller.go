@@ -0,0 +1,91 @@
+package promotion
+
+import (
+	"errors"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PromotionController struct {
+	service PromotionService
+}
+
+func NewPromotionController(service PromotionService) *PromotionController {
+	return &PromotionController{service: service}
+}
+
+func (ctrl *PromotionController) GetAll(c *fiber.Ctx) error {
+	promotions, err := ctrl.service.GetAllPromotions()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": promotions})
+}
+
+func (ctrl *PromotionController) Create(c *fiber.Ctx) error {
+	var req PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	promotion, err := ctrl.service.CreatePromotion(req)
+	if err != nil {
+		return promotionError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Promo berhasil dibuat",
+		"data":    promotion,
+	})
+}
+
+func (ctrl *PromotionController) Update(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID promo tidak valid"})
+	}
+
+	var req PromotionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	promotion, err := ctrl.service.UpdatePromotion(id, req)
+	if err != nil {
+		return promotionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Promo berhasil diperbarui",
+		"data":    promotion,
+	})
+}
+
+func (ctrl *PromotionController) Delete(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID promo tidak valid"})
+	}
+
+	if err := ctrl.service.DeletePromotion(id); err != nil {
+		return promotionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Promo berhasil dihapus"})
+}
+
+// promotionError memetakan error service promo ke status HTTP.
+func promotionError(c *fiber.Ctx, err error) error {
+	var valErr validator.ValidationErrors
+	if errors.As(err, &valErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+	}
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, core.ErrInvalidPromotion):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
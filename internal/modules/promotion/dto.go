@@ -0,0 +1,38 @@
+package promotion
+
+// PromotionTierInput adalah satu tingkat promo TIERED.
+type PromotionTierInput struct {
+	MinSpend        int `json:"min_spend" validate:"min=0"`
+	DiscountPercent int `json:"discount_percent" validate:"min=1,max=100"`
+}
+
+// PromotionRequest adalah DTO untuk membuat/mengubah promo otomatis. Kolom yang dipakai tergantung Type:
+//   - BUY_X_GET_Y: buy_qty, get_qty, discount_percent (0 = unit hadiah gratis)
+//   - PERCENT_OFF: discount_percent, min_qty
+//   - TIERED: tiers
+//   - SPEND_GET_ITEM: min_spend, reward_product_id, get_qty (default 1), discount_percent (0 = gratis)
+//
+// Jadwal memakai format yang sama dengan jadwal harga promo produk; kolom kosong = tanpa batas.
+type PromotionRequest struct {
+	Name             string `json:"name" validate:"required,max=100"`
+	Type             string `json:"type" validate:"required,oneof=BUY_X_GET_Y PERCENT_OFF TIERED SPEND_GET_ITEM"`
+	Priority         int    `json:"priority" validate:"min=0"` // Lebih kecil dievaluasi lebih dulu
+	StackWithVoucher bool   `json:"stack_with_voucher"`        // false = dilewati jika pelanggan memakai voucher
+	IsActive         *bool  `json:"is_active"`                 // Kosong = aktif
+	ProductID        string `json:"product_id" validate:"omitempty,uuid"`
+	CategoryID       string `json:"category_id" validate:"omitempty,uuid"`
+
+	BuyQty          int                  `json:"buy_qty" validate:"min=0"`
+	GetQty          int                  `json:"get_qty" validate:"min=0"`
+	DiscountPercent int                  `json:"discount_percent" validate:"min=0,max=100"`
+	MinQty          int                  `json:"min_qty" validate:"min=0"`
+	MinSpend        int                  `json:"min_spend" validate:"min=0"`
+	RewardProductID string               `json:"reward_product_id" validate:"omitempty,uuid"`
+	Tiers           []PromotionTierInput `json:"tiers" validate:"dive"`
+
+	Weekdays  []int  `json:"weekdays" validate:"unique,dive,min=0,max=6"` // 0 = Minggu; kosong = setiap hari
+	StartTime string `json:"start_time" validate:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required_with=StartTime,omitempty,datetime=15:04"`
+	StartDate string `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,85 @@
+package promotion
+
+import (
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) GetAll() ([]core.Promotion, error) {
+	var promotions []core.Promotion
+	err := r.db.Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_spend ASC")
+	}).Order("priority ASC, name ASC").Find(&promotions).Error
+	return promotions, err
+}
+
+func (r *promotionRepository) FindByID(id uuid.UUID) (*core.Promotion, error) {
+	var promotion core.Promotion
+	err := r.db.Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_spend ASC")
+	}).First(&promotion, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) Save(promotion *core.Promotion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tiers := promotion.Tiers
+		save := tx.Omit("Tiers").Save
+		if promotion.ID == uuid.Nil {
+			promotion.ID = uuid.New()
+			save = tx.Omit("Tiers").Create
+		}
+		if err := save(promotion).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("promotion_id = ?", promotion.ID).Delete(&core.PromotionTier{}).Error; err != nil {
+			return err
+		}
+		for i := range tiers {
+			tiers[i].ID = uuid.New()
+			tiers[i].PromotionID = promotion.ID
+		}
+		if len(tiers) > 0 {
+			if err := tx.Create(&tiers).Error; err != nil {
+				return err
+			}
+		}
+		promotion.Tiers = tiers
+		return nil
+	})
+}
+
+// Delete menghapus promo beserta tier-nya. Kolom promo di OrderItem adalah snapshot dan tidak terpengaruh.
+func (r *promotionRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("promotion_id = ?", id).Delete(&core.PromotionTier{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&core.Promotion{}, "id = ?", id).Error
+	})
+}
+
+func (r *promotionRepository) ProductExists(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&core.Product{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *promotionRepository) CategoryExists(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&core.Category{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
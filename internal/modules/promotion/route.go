@@ -0,0 +1,19 @@
+package promotion
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupRoutes(adminGroup fiber.Router, db *gorm.DB, v *validator.Validate) {
+	repo := NewPromotionRepository(db)
+	service := NewPromotionService(repo, v)
+	ctrl := NewPromotionController(service)
+
+	// Admin-only endpoints
+	adminGroup.Get("/promotions", ctrl.GetAll)
+	adminGroup.Post("/promotions", ctrl.Create)
+	adminGroup.Put("/promotions/:id", ctrl.Update)
+	adminGroup.Delete("/promotions/:id", ctrl.Delete)
+}
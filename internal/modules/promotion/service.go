@@ -0,0 +1,164 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-fiber-pos/internal/core"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type promotionService struct {
+	repo PromotionRepository
+	v    *validator.Validate
+}
+
+func NewPromotionService(repo PromotionRepository, v *validator.Validate) PromotionService {
+	return &promotionService{repo: repo, v: v}
+}
+
+func (s *promotionService) GetAllPromotions() ([]core.Promotion, error) {
+	promotions, err := s.repo.GetAll()
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return promotions, nil
+}
+
+func (s *promotionService) CreatePromotion(req PromotionRequest) (*core.Promotion, error) {
+	promotion := &core.Promotion{}
+	if err := s.applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(promotion); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) UpdatePromotion(id uuid.UUID, req PromotionRequest) (*core.Promotion, error) {
+	promotion, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if err := s.applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(promotion); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) DeletePromotion(id uuid.UUID) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return core.ErrNotFound
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// applyPromotionRequest memvalidasi request (termasuk kolom wajib per tipe promo) lalu menyalinnya ke promotion.
+func (s *promotionService) applyPromotionRequest(promotion *core.Promotion, req PromotionRequest) error {
+	if err := s.v.Struct(req); err != nil {
+		return err
+	}
+
+	switch req.Type {
+	case core.PromotionBuyXGetY:
+		if req.BuyQty < 1 || req.GetQty < 1 {
+			return fmt.Errorf("%w: buy_qty dan get_qty minimal 1", core.ErrInvalidPromotion)
+		}
+	case core.PromotionPercentOff:
+		if req.DiscountPercent < 1 {
+			return fmt.Errorf("%w: discount_percent minimal 1", core.ErrInvalidPromotion)
+		}
+	case core.PromotionTiered:
+		if len(req.Tiers) == 0 {
+			return fmt.Errorf("%w: promo bertingkat membutuhkan minimal satu tier", core.ErrInvalidPromotion)
+		}
+	case core.PromotionSpendGetItem:
+		if req.RewardProductID == "" || req.MinSpend < 1 {
+			return fmt.Errorf("%w: min_spend dan reward_product_id wajib diisi", core.ErrInvalidPromotion)
+		}
+	}
+	if req.StartTime != "" && req.StartTime == req.EndTime {
+		return fmt.Errorf("%w: jam mulai dan jam selesai tidak boleh sama", core.ErrInvalidPromotion)
+	}
+	if req.StartDate != "" && req.EndDate != "" && req.EndDate < req.StartDate {
+		return fmt.Errorf("%w: tanggal selesai %s sebelum tanggal mulai %s", core.ErrInvalidPromotion, req.EndDate, req.StartDate)
+	}
+
+	productID, err := s.parseReference(req.ProductID, s.repo.ProductExists, "produk")
+	if err != nil {
+		return err
+	}
+	categoryID, err := s.parseReference(req.CategoryID, s.repo.CategoryExists, "kategori")
+	if err != nil {
+		return err
+	}
+	rewardProductID, err := s.parseReference(req.RewardProductID, s.repo.ProductExists, "produk hadiah")
+	if err != nil {
+		return err
+	}
+
+	tiers := make([]core.PromotionTier, 0, len(req.Tiers))
+	for _, tier := range req.Tiers {
+		tiers = append(tiers, core.PromotionTier{MinSpend: tier.MinSpend, DiscountPercent: tier.DiscountPercent})
+	}
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].MinSpend < tiers[j].MinSpend })
+
+	sort.Ints(req.Weekdays)
+	weekdays := make([]string, 0, len(req.Weekdays))
+	for _, wd := range req.Weekdays {
+		weekdays = append(weekdays, strconv.Itoa(wd))
+	}
+
+	promotion.Name = req.Name
+	promotion.Type = req.Type
+	promotion.Priority = req.Priority
+	promotion.StackWithVoucher = req.StackWithVoucher
+	promotion.IsActive = req.IsActive == nil || *req.IsActive
+	promotion.ProductID = productID
+	promotion.CategoryID = categoryID
+	promotion.BuyQty = req.BuyQty
+	promotion.GetQty = req.GetQty
+	promotion.DiscountPercent = req.DiscountPercent
+	promotion.MinQty = req.MinQty
+	promotion.MinSpend = req.MinSpend
+	promotion.RewardProductID = rewardProductID
+	promotion.Tiers = tiers
+	promotion.Weekdays = strings.Join(weekdays, ",")
+	promotion.StartTime = req.StartTime
+	promotion.EndTime = req.EndTime
+	promotion.StartDate = req.StartDate
+	promotion.EndDate = req.EndDate
+	return nil
+}
+
+// parseReference mengurai ID opsional dan memastikan datanya ada. raw kosong = nil.
+func (s *promotionService) parseReference(raw string, exists func(uuid.UUID) (bool, error), label string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id := uuid.MustParse(raw) // Sudah divalidasi tag uuid
+	ok, err := exists(id)
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s tidak ditemukan", core.ErrInvalidPromotion, label, raw)
+	}
+	return &id, nil
+}
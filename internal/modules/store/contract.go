@@ -1,15 +1,33 @@
 package store
 
-import "go-fiber-pos/internal/core"
+import (
+	"go-fiber-pos/internal/core"
+
+	"github.com/google/uuid"
+)
 
 // StoreRepository mendefinisikan kontrak akses data untuk StoreProfile.
 type StoreRepository interface {
 	GetProfile() (*core.StoreProfile, error)
 	Upsert(profile *core.StoreProfile) (*core.StoreProfile, error)
+	// ListChargeRules mengambil semua aturan service charge & pajak (aktif maupun tidak) beserta kategori yang dikecualikan.
+	ListChargeRules() ([]core.ChargeRule, error)
+	FindChargeRule(id uuid.UUID) (*core.ChargeRule, error)
+	// CountCategories menghitung berapa ID kategori yang benar-benar ada, untuk validasi pengecualian.
+	CountCategories(ids []uuid.UUID) (int64, error)
+	// SaveChargeRule meng-insert atau meng-update aturan dan mengganti seluruh kategori yang dikecualikan.
+	SaveChargeRule(rule *core.ChargeRule) error
+	DeleteChargeRule(id uuid.UUID) error
 }
 
 // StoreService mendefinisikan kontrak business logic untuk StoreProfile.
 type StoreService interface {
 	GetProfile() (*core.StoreProfile, error)
 	UpdateProfile(req UpdateStoreRequest) (*core.StoreProfile, error)
+	// ListChargeRules, CreateChargeRule, UpdateChargeRule, DeleteChargeRule mengelola aturan
+	// service charge & pajak yang dihitung saat checkout. Perubahan tidak mengubah order yang sudah ada.
+	ListChargeRules() ([]core.ChargeRule, error)
+	CreateChargeRule(req ChargeRuleRequest) (*core.ChargeRule, error)
+	UpdateChargeRule(id uuid.UUID, req ChargeRuleRequest) (*core.ChargeRule, error)
+	DeleteChargeRule(id uuid.UUID) error
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -44,6 +45,9 @@ func (ctrl *StoreController) UpdateProfile(c *fiber.Ctx) error {
 		if errors.As(err, &valErr) {
 			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
 		}
+		if errors.Is(err, core.ErrQueueScheme) || errors.Is(err, core.ErrCashRounding) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		}
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 		}
@@ -54,3 +58,75 @@ func (ctrl *StoreController) UpdateProfile(c *fiber.Ctx) error {
 		"data":    profile,
 	})
 }
+
+func (ctrl *StoreController) ListChargeRules(c *fiber.Ctx) error {
+	rules, err := ctrl.service.ListChargeRules()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": rules})
+}
+
+func (ctrl *StoreController) CreateChargeRule(c *fiber.Ctx) error {
+	var req ChargeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	rule, err := ctrl.service.CreateChargeRule(req)
+	if err != nil {
+		return chargeRuleError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Aturan pajak/service charge berhasil dibuat",
+		"data":    rule,
+	})
+}
+
+func (ctrl *StoreController) UpdateChargeRule(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID aturan tidak valid"})
+	}
+
+	var req ChargeRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Format JSON tidak valid"})
+	}
+
+	rule, err := ctrl.service.UpdateChargeRule(id, req)
+	if err != nil {
+		return chargeRuleError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Aturan pajak/service charge berhasil diperbarui",
+		"data":    rule,
+	})
+}
+
+func (ctrl *StoreController) DeleteChargeRule(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID aturan tidak valid"})
+	}
+
+	if err := ctrl.service.DeleteChargeRule(id); err != nil {
+		return chargeRuleError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Aturan pajak/service charge berhasil dihapus"})
+}
+
+// chargeRuleError memetakan error service aturan pajak/service charge ke status HTTP.
+func chargeRuleError(c *fiber.Ctx, err error) error {
+	var valErr validator.ValidationErrors
+	if errors.As(err, &valErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validasi gagal", "details": valErr.Error()})
+	}
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, core.ErrInvalidChargeRule):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
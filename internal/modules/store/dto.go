@@ -2,17 +2,62 @@ package store
 
 // UpdateStoreRequest adalah DTO untuk request update profil toko.
 type UpdateStoreRequest struct {
-	Name      string `json:"name" validate:"required,min=3"`
-	Address   string `json:"address"`
-	Phone     string `json:"phone"`
-	MarkupFee int    `json:"markup_fee" validate:"min=0"`
+	Name                  string `json:"name" validate:"required,min=3"`
+	Address               string `json:"address"`
+	Phone                 string `json:"phone"`
+	MarkupFee             int    `json:"markup_fee" validate:"min=0"`
+	OpeningTime           string `json:"opening_time" validate:"required_with=ClosingTime,omitempty,datetime=15:04"`
+	ClosingTime           string `json:"closing_time" validate:"required_with=OpeningTime,omitempty,datetime=15:04"`
+	PreOrderLeadMinutes   int    `json:"pre_order_lead_minutes" validate:"min=0,max=1440"`    // 0 = default 30 menit
+	UnpaidOrderTTLMinutes int    `json:"unpaid_order_ttl_minutes" validate:"min=0,max=10080"` // 0 = order tidak pernah kedaluwarsa
+
+	// Skema nomor antrean. Kosong/0 = default (K / E, 3 digit, reset harian tengah malam).
+	QueuePrefixCashier    string   `json:"queue_prefix_cashier" validate:"omitempty,alphanum,max=5"`
+	QueuePrefixEMenu      string   `json:"queue_prefix_e_menu" validate:"omitempty,alphanum,max=5"`
+	QueueNumberWidth      int      `json:"queue_number_width" validate:"min=0,max=6"`
+	QueueResetPolicy      string   `json:"queue_reset_policy" validate:"omitempty,oneof=DAILY SHIFT NEVER"`
+	QueueShiftStarts      []string `json:"queue_shift_starts" validate:"required_if=QueueResetPolicy SHIFT,max=6,unique,dive,datetime=15:04"` // Contoh ["06:00","14:00","22:00"]
+	BusinessDayCutoffHour int      `json:"business_day_cutoff_hour" validate:"min=0,max=23"`                                                  // Contoh 4 = hari bisnis berganti pukul 04:00
+
+	// Pembulatan pembayaran. Kosong = NONE (tanpa pembulatan); cakupan kosong = hanya CASH.
+	CashRoundingMode      string `json:"cash_rounding_mode" validate:"omitempty,oneof=NONE NEAREST UP DOWN"`
+	CashRoundingIncrement int    `json:"cash_rounding_increment" validate:"min=0,max=10000"` // Contoh 100 atau 500
+	CashRoundingScope     string `json:"cash_rounding_scope" validate:"omitempty,oneof=CASH ALL"`
+
+	Timezone string `json:"timezone" validate:"omitempty,timezone"` // Zona waktu jadwal promo, contoh "Asia/Jakarta"
 }
 
 // StoreResponse adalah DTO untuk response profil toko.
 type StoreResponse struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Address   string `json:"address"`
-	Phone     string `json:"phone"`
-	MarkupFee int    `json:"markup_fee"`
+	ID                    string `json:"id"`
+	Name                  string `json:"name"`
+	Address               string `json:"address"`
+	Phone                 string `json:"phone"`
+	MarkupFee             int    `json:"markup_fee"`
+	OpeningTime           string `json:"opening_time"`
+	ClosingTime           string `json:"closing_time"`
+	PreOrderLeadMinutes   int    `json:"pre_order_lead_minutes"`
+	UnpaidOrderTTLMinutes int    `json:"unpaid_order_ttl_minutes"`
+	QueuePrefixCashier    string `json:"queue_prefix_cashier"`
+	QueuePrefixEMenu      string `json:"queue_prefix_e_menu"`
+	QueueNumberWidth      int    `json:"queue_number_width"`
+	QueueResetPolicy      string `json:"queue_reset_policy"`
+	QueueShiftStarts      string `json:"queue_shift_starts"`
+	BusinessDayCutoffHour int    `json:"business_day_cutoff_hour"`
+	CashRoundingMode      string `json:"cash_rounding_mode"`
+	CashRoundingIncrement int    `json:"cash_rounding_increment"`
+	CashRoundingScope     string `json:"cash_rounding_scope"`
+	Timezone              string `json:"timezone"`
+}
+
+// ChargeRuleRequest adalah DTO untuk membuat/mengubah aturan service charge atau pajak.
+type ChargeRuleRequest struct {
+	Kind              string   `json:"kind" validate:"required,oneof=SERVICE TAX"`
+	Name              string   `json:"name" validate:"required,max=50"`     // Dicetak di struk, contoh "PB1" atau "PPN"
+	RateBps           int      `json:"rate_bps" validate:"min=1,max=10000"` // Basis poin, contoh 1000 = 10%
+	IsInclusive       bool     `json:"is_inclusive"`                        // Hanya untuk TAX: pajak sudah termasuk di harga menu
+	AfterDiscount     bool     `json:"after_discount"`                      // true = dasar pengenaan setelah diskon voucher
+	IsActive          *bool    `json:"is_active"`                           // Kosong = aktif
+	SortOrder         int      `json:"sort_order" validate:"min=0"`
+	ExemptCategoryIDs []string `json:"exempt_category_ids" validate:"unique,dive,uuid"`
 }
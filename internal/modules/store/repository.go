@@ -44,8 +44,73 @@ func (r *storeRepository) Upsert(profile *core.StoreProfile) (*core.StoreProfile
 	existing.Address = profile.Address
 	existing.Phone = profile.Phone
 	existing.MarkupFee = profile.MarkupFee
+	existing.OpeningTime = profile.OpeningTime
+	existing.ClosingTime = profile.ClosingTime
+	existing.PreOrderLeadMinutes = profile.PreOrderLeadMinutes
+	existing.UnpaidOrderTTLMinutes = profile.UnpaidOrderTTLMinutes
+	existing.QueuePrefixCashier = profile.QueuePrefixCashier
+	existing.QueuePrefixEMenu = profile.QueuePrefixEMenu
+	existing.QueueNumberWidth = profile.QueueNumberWidth
+	existing.QueueResetPolicy = profile.QueueResetPolicy
+	existing.QueueShiftStarts = profile.QueueShiftStarts
+	existing.BusinessDayCutoffHour = profile.BusinessDayCutoffHour
+	existing.CashRoundingMode = profile.CashRoundingMode
+	existing.CashRoundingIncrement = profile.CashRoundingIncrement
+	existing.CashRoundingScope = profile.CashRoundingScope
+	existing.Timezone = profile.Timezone
 	if saveErr := r.db.Save(&existing).Error; saveErr != nil {
 		return nil, saveErr
 	}
 	return &existing, nil
 }
+
+func (r *storeRepository) ListChargeRules() ([]core.ChargeRule, error) {
+	var rules []core.ChargeRule
+	err := r.db.Preload("ExemptCategories").Order("kind ASC, sort_order ASC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *storeRepository) FindChargeRule(id uuid.UUID) (*core.ChargeRule, error) {
+	var rule core.ChargeRule
+	if err := r.db.Preload("ExemptCategories").First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *storeRepository) CountCategories(ids []uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&core.Category{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+func (r *storeRepository) SaveChargeRule(rule *core.ChargeRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		categories := rule.ExemptCategories
+		save := tx.Omit("ExemptCategories").Save
+		if rule.ID == uuid.Nil {
+			rule.ID = uuid.New()
+			save = tx.Omit("ExemptCategories").Create
+		}
+		if err := save(rule).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(rule).Association("ExemptCategories").Replace(categories); err != nil {
+			return err
+		}
+		rule.ExemptCategories = categories
+		return nil
+	})
+}
+
+// DeleteChargeRule menghapus aturan beserta relasi pengecualiannya. Baris OrderCharge adalah snapshot
+// dan tidak terpengaruh.
+func (r *storeRepository) DeleteChargeRule(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		rule := core.ChargeRule{ID: id}
+		if err := tx.Model(&rule).Association("ExemptCategories").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&rule).Error
+	})
+}
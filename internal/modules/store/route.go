@@ -14,4 +14,8 @@ func SetupRoutes(adminGroup fiber.Router, db *gorm.DB, v *validator.Validate) {
 	// Admin-only endpoints
 	adminGroup.Get("/store-profile", ctrl.GetProfile)
 	adminGroup.Put("/store-profile", ctrl.UpdateProfile)
+	adminGroup.Get("/store-profile/charge-rules", ctrl.ListChargeRules)
+	adminGroup.Post("/store-profile/charge-rules", ctrl.CreateChargeRule)
+	adminGroup.Put("/store-profile/charge-rules/:id", ctrl.UpdateChargeRule)
+	adminGroup.Delete("/store-profile/charge-rules/:id", ctrl.DeleteChargeRule)
 }
@@ -2,9 +2,13 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"go-fiber-pos/internal/core"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type storeService struct {
@@ -34,10 +38,37 @@ func (s *storeService) UpdateProfile(req UpdateStoreRequest) (*core.StoreProfile
 	}
 
 	profile := &core.StoreProfile{
-		Name:      req.Name,
-		Address:   req.Address,
-		Phone:     req.Phone,
-		MarkupFee: req.MarkupFee,
+		Name:                  req.Name,
+		Address:               req.Address,
+		Phone:                 req.Phone,
+		MarkupFee:             req.MarkupFee,
+		OpeningTime:           req.OpeningTime,
+		ClosingTime:           req.ClosingTime,
+		PreOrderLeadMinutes:   req.PreOrderLeadMinutes,
+		UnpaidOrderTTLMinutes: req.UnpaidOrderTTLMinutes,
+		QueuePrefixCashier:    strings.ToUpper(req.QueuePrefixCashier),
+		QueuePrefixEMenu:      strings.ToUpper(req.QueuePrefixEMenu),
+		QueueNumberWidth:      req.QueueNumberWidth,
+		QueueResetPolicy:      req.QueueResetPolicy,
+		QueueShiftStarts:      strings.Join(core.ParseShiftStarts(strings.Join(req.QueueShiftStarts, ",")), ","),
+		BusinessDayCutoffHour: req.BusinessDayCutoffHour,
+		CashRoundingMode:      req.CashRoundingMode,
+		CashRoundingIncrement: req.CashRoundingIncrement,
+		CashRoundingScope:     req.CashRoundingScope,
+		Timezone:              req.Timezone,
+	}
+	if profile.CashRoundingMode == "" {
+		profile.CashRoundingMode = core.CashRoundingNone
+	}
+	if profile.CashRoundingScope == "" {
+		profile.CashRoundingScope = core.CashRoundingScopeCash
+	}
+	if profile.CashRoundingMode != core.CashRoundingNone && profile.CashRoundingIncrement <= 0 {
+		return nil, fmt.Errorf("%w: kelipatan wajib diisi untuk mode %s", core.ErrCashRounding, profile.CashRoundingMode)
+	}
+	// Counter antrean dipisah per source, jadi prefix yang sama akan menghasilkan nomor ganda
+	if profile.QueuePrefix(core.OrderSourceCashier) == profile.QueuePrefix(core.OrderSourceEMenu) {
+		return nil, fmt.Errorf("%w: prefix CASHIER dan E_MENU harus berbeda", core.ErrQueueScheme)
 	}
 
 	result, err := s.repo.Upsert(profile)
@@ -46,3 +77,87 @@ func (s *storeService) UpdateProfile(req UpdateStoreRequest) (*core.StoreProfile
 	}
 	return result, nil
 }
+
+func (s *storeService) ListChargeRules() ([]core.ChargeRule, error) {
+	rules, err := s.repo.ListChargeRules()
+	if err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return rules, nil
+}
+
+func (s *storeService) CreateChargeRule(req ChargeRuleRequest) (*core.ChargeRule, error) {
+	rule := &core.ChargeRule{}
+	if err := s.applyChargeRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveChargeRule(rule); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return rule, nil
+}
+
+func (s *storeService) UpdateChargeRule(id uuid.UUID, req ChargeRuleRequest) (*core.ChargeRule, error) {
+	rule, err := s.repo.FindChargeRule(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.ErrNotFound
+		}
+		return nil, core.ErrInternalServer
+	}
+	if err := s.applyChargeRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveChargeRule(rule); err != nil {
+		return nil, core.ErrInternalServer
+	}
+	return rule, nil
+}
+
+func (s *storeService) DeleteChargeRule(id uuid.UUID) error {
+	if _, err := s.repo.FindChargeRule(id); err != nil {
+		return core.ErrNotFound
+	}
+	if err := s.repo.DeleteChargeRule(id); err != nil {
+		return core.ErrInternalServer
+	}
+	return nil
+}
+
+// applyChargeRuleRequest memvalidasi request lalu menyalinnya ke rule.
+// Service charge selalu eksklusif: hanya pajak yang boleh sudah termasuk di harga menu.
+func (s *storeService) applyChargeRuleRequest(rule *core.ChargeRule, req ChargeRuleRequest) error {
+	if err := s.v.Struct(req); err != nil {
+		return err
+	}
+	if req.Kind == core.ChargeKindService && req.IsInclusive {
+		return fmt.Errorf("%w: service charge tidak bisa inklusif", core.ErrInvalidChargeRule)
+	}
+
+	categories := make([]core.Category, 0, len(req.ExemptCategoryIDs))
+	ids := make([]uuid.UUID, 0, len(req.ExemptCategoryIDs))
+	for _, raw := range req.ExemptCategoryIDs {
+		id := uuid.MustParse(raw) // Sudah divalidasi tag uuid
+		ids = append(ids, id)
+		categories = append(categories, core.Category{ID: id})
+	}
+	if len(ids) > 0 {
+		count, err := s.repo.CountCategories(ids)
+		if err != nil {
+			return core.ErrInternalServer
+		}
+		if count != int64(len(ids)) {
+			return fmt.Errorf("%w: kategori yang dikecualikan tidak ditemukan", core.ErrInvalidChargeRule)
+		}
+	}
+
+	rule.Kind = req.Kind
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.RateBps = req.RateBps
+	rule.IsInclusive = req.IsInclusive
+	rule.AfterDiscount = req.AfterDiscount
+	rule.IsActive = req.IsActive == nil || *req.IsActive
+	rule.SortOrder = req.SortOrder
+	rule.ExemptCategories = categories
+	return nil
+}
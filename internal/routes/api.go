@@ -3,12 +3,14 @@ package routes
 import (
 	"go-fiber-pos/internal/config"
 	"go-fiber-pos/internal/infrastructure/provider"
+	"go-fiber-pos/internal/infrastructure/realtime"
 	"go-fiber-pos/internal/middleware"
 	"go-fiber-pos/internal/modules/auth"
 	"go-fiber-pos/internal/modules/category"
 	"go-fiber-pos/internal/modules/order"
 	"go-fiber-pos/internal/modules/payment"
 	"go-fiber-pos/internal/modules/product"
+	"go-fiber-pos/internal/modules/promotion"
 	"go-fiber-pos/internal/modules/store"
 	"go-fiber-pos/internal/modules/voucher"
 
@@ -16,14 +18,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-// SetupRoutes merakit semua dependency dan mendaftarkan endpoint
-func SetupRoutes(app *fiber.App) {
+// SetupRoutes merakit semua dependency dan mendaftarkan endpoint. Background job yang dikembalikan
+// dijalankan oleh main agar ikut berhenti saat server shutdown.
+func SetupRoutes(app *fiber.App) []*order.BackgroundJob {
 	v := validator.New()
 
 	// Inisialisasi Payment Gateway Adapter (PORT & ADAPTER pattern)
 	// Mudah diganti dengan adapter lain tanpa mengubah service layer
 	midtransAdapter := provider.NewMidtransAdapter()
 
+	// Event bus in-memory untuk kitchen display realtime (SSE)
+	orderEvents := realtime.NewHub()
+
 	api := app.Group("/api")
 
 	// Route Test Ping
@@ -61,6 +67,9 @@ func SetupRoutes(app *fiber.App) {
 	// New modules
 	store.SetupRoutes(adminGroup, config.DB, v)
 	voucher.SetupRoutes(adminGroup, config.DB, v)
-	order.SetupRoutes(adminGroup, config.DB, v)
-	payment.SetupRoutes(adminGroup, webhookGroup, config.DB, v, midtransAdapter)
+	promotion.SetupRoutes(adminGroup, config.DB, v)
+	orderJobs := order.SetupRoutes(adminGroup, publicGroup, config.DB, v, orderEvents)
+	payment.SetupRoutes(adminGroup, webhookGroup, config.DB, v, midtransAdapter, orderEvents)
+
+	return orderJobs
 }
\ No newline at end of file
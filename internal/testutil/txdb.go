@@ -0,0 +1,108 @@
+// Package testutil berisi helper untuk test service yang repository-nya di-mock.
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// errNoDatabase dikembalikan untuk query apa pun: semua akses data harus lewat repository mock.
+var errNoDatabase = errors.New("testutil: TxDB tidak terhubung ke database")
+
+// TxDB adalah *gorm.DB palsu untuk test service. Begin/Commit/Rollback berhasil tanpa database
+// sehingga alur transaksi service bisa diuji, dan jumlah commit/rollback bisa diperiksa.
+type TxDB struct {
+	DB *gorm.DB
+	// OnEnd (opsional) dipanggil setelah setiap commit/rollback, misalnya untuk melepas lock
+	// baris yang ditiru oleh mock LockOrder. Set sebelum service dipakai.
+	OnEnd func()
+
+	commits   atomic.Int32
+	rollbacks atomic.Int32
+}
+
+// NewTxDB membuat TxDB baru; test gagal jika gorm tidak bisa diinisialisasi.
+func NewTxDB(t testing.TB) *TxDB {
+	t.Helper()
+	txdb := &TxDB{}
+	db, err := gorm.Open(dialector{pool: &connPool{db: txdb}}, &gorm.Config{
+		Logger:                 logger.Discard,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("testutil: gagal membuat TxDB: %v", err)
+	}
+	txdb.DB = db
+	return txdb
+}
+
+// Commits mengembalikan jumlah transaksi yang sudah di-commit.
+func (d *TxDB) Commits() int { return int(d.commits.Load()) }
+
+// Rollbacks mengembalikan jumlah transaksi yang di-rollback.
+func (d *TxDB) Rollbacks() int { return int(d.rollbacks.Load()) }
+
+type dialector struct{ pool *connPool }
+
+func (dialector) Name() string { return "testutil" }
+
+func (d dialector) Initialize(db *gorm.DB) error {
+	db.ConnPool = d.pool
+	return nil
+}
+
+func (dialector) Migrator(*gorm.DB) gorm.Migrator                     { return nil }
+func (dialector) DataTypeOf(*schema.Field) string                     { return "" }
+func (dialector) DefaultValueOf(*schema.Field) clause.Expression      { return clause.Expr{} }
+func (dialector) BindVarTo(w clause.Writer, _ *gorm.Statement, _ any) { _ = w.WriteByte('?') }
+func (dialector) QuoteTo(w clause.Writer, s string)                   { _, _ = w.WriteString(s) }
+func (dialector) Explain(sql string, _ ...any) string                 { return sql }
+
+// connPool menolak semua query dan membuka transaksi palsu.
+type connPool struct{ db *TxDB }
+
+func (p *connPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoDatabase
+}
+
+func (p *connPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errNoDatabase
+}
+
+func (p *connPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errNoDatabase
+}
+
+func (p *connPool) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (p *connPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &txPool{connPool: p}, nil
+}
+
+type txPool struct{ *connPool }
+
+func (t *txPool) Commit() error {
+	t.db.commits.Add(1)
+	if t.db.OnEnd != nil {
+		t.db.OnEnd()
+	}
+	return nil
+}
+
+func (t *txPool) Rollback() error {
+	t.db.rollbacks.Add(1)
+	if t.db.OnEnd != nil {
+		t.db.OnEnd()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"errors"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TableClaims adalah isi token meja yang dicetak sebagai QR di setiap meja (E-Menu).
+// Tidak punya ExpiresAt karena QR ditempel permanen; rotasi cukup dengan mengganti TABLE_TOKEN_SECRET.
+type TableClaims struct {
+	TableNumber string `json:"table_number"`
+	jwt.RegisteredClaims
+}
+
+// tableTokenIssuer membedakan token meja dari token login agar tidak bisa saling dipakai.
+const tableTokenIssuer = "pos-table-qr"
+
+// ErrTableSecretMissing dikembalikan jika TABLE_TOKEN_SECRET belum di-set. Tidak ada fallback:
+// secret bawaan yang tertulis di source code bisa dipakai siapa saja untuk memalsukan QR meja.
+var ErrTableSecretMissing = errors.New("TABLE_TOKEN_SECRET belum di-set")
+
+func tableSecret() ([]byte, error) {
+	secretStr := os.Getenv("TABLE_TOKEN_SECRET")
+	if secretStr == "" {
+		return nil, ErrTableSecretMissing
+	}
+	return []byte(secretStr), nil
+}
+
+// GenerateTableToken membuat token bertanda tangan untuk satu nomor meja.
+func GenerateTableToken(tableNumber string) (string, error) {
+	claims := &TableClaims{
+		TableNumber: tableNumber,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer: tableTokenIssuer,
+		},
+	}
+
+	secret, err := tableSecret()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secret)
+}
+
+// ParseTableToken memverifikasi tanda tangan token meja dan mengembalikan nomor mejanya.
+func ParseTableToken(tokenString string) (string, error) {
+	secret, err := tableSecret()
+	if err != nil {
+		return "", err
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &TableClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tableTokenIssuer))
+	if err != nil || !token.Valid {
+		return "", errors.New("token meja tidak valid")
+	}
+
+	claims, ok := token.Claims.(*TableClaims)
+	if !ok || claims.TableNumber == "" {
+		return "", errors.New("token meja tidak valid")
+	}
+	return claims.TableNumber, nil
+}
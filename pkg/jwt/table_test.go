@@ -0,0 +1,53 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"go-fiber-pos/pkg/jwt"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableToken(t *testing.T) {
+	t.Setenv("TABLE_TOKEN_SECRET", "rahasia-qr-meja-test")
+
+	t.Run("Sukses - Token meja bisa dibaca kembali", func(t *testing.T) {
+		token, err := jwt.GenerateTableToken("A-12")
+		assert.NoError(t, err)
+
+		tableNumber, err := jwt.ParseTableToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "A-12", tableNumber)
+	})
+
+	t.Run("Gagal - Token dimodifikasi", func(t *testing.T) {
+		token, err := jwt.GenerateTableToken("A-12")
+		assert.NoError(t, err)
+
+		_, err = jwt.ParseTableToken(token + "x")
+		assert.Error(t, err)
+	})
+
+	t.Run("Gagal - Token login tidak bisa dipakai sebagai token meja", func(t *testing.T) {
+		loginToken, err := jwt.GenerateToken(uuid.New(), "ADMIN")
+		assert.NoError(t, err)
+
+		_, err = jwt.ParseTableToken(loginToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestTableToken_SecretMissing(t *testing.T) {
+	t.Setenv("TABLE_TOKEN_SECRET", "rahasia-qr-meja-test")
+	token, err := jwt.GenerateTableToken("A-12")
+	assert.NoError(t, err)
+
+	t.Setenv("TABLE_TOKEN_SECRET", "")
+
+	_, err = jwt.GenerateTableToken("A-12")
+	assert.ErrorIs(t, err, jwt.ErrTableSecretMissing)
+
+	_, err = jwt.ParseTableToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTableSecretMissing)
+}